@@ -0,0 +1,123 @@
+// Catalog query benchmarks: the correlated-subquery SQL the catalog
+// endpoints ran before tour_search_index, against the same data read from
+// the index (tour-server/searchindex/migration.sql).
+//
+//	cd benchmark/go
+//	go test -run '^$' -bench . -benchmem
+//
+// Uses DB_DSN like the server; skipped when the database is unreachable
+// or the index has not been migrated.
+package main
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+const (
+	searchSubqueriesSQL = `
+		SELECT tours.id, tours.title, tours.price, tours.rating,
+		       COALESCE(tci.image_src, '/static/images/no-image.jpg') AS image_src,
+		       COALESCE((
+		           SELECT EXTRACT(DAY FROM (td.date_to - td.date_from))
+		           FROM tour_dates td WHERE td.tour_id = tours.id LIMIT 1
+		       ), 0) AS duration,
+		       COALESCE((
+		           SELECT CONCAT(l.name, ', ', l.country)
+		           FROM tour_dates td
+		           JOIN locations l ON td.to_location_id = l.id
+		           WHERE td.tour_id = tours.id LIMIT 1
+		       ), '') AS location,
+		       EXISTS (
+		           SELECT 1 FROM tour_dates td
+		           WHERE td.tour_id = tours.id AND td.is_guaranteed
+		           AND NOT td.is_retired AND td.date_from > NOW()
+		       ) AS guaranteed
+		FROM tours
+		LEFT JOIN tour_card_images tci ON tci.tour_id = tours.id
+		WHERE tours.status_id = (SELECT id FROM statuses WHERE name = 'active')
+		ORDER BY tours.price ASC
+		LIMIT 12`
+
+	searchIndexSQL = `
+		SELECT tour_id, title, price, rating,
+		       COALESCE(image_src, '/static/images/no-image.jpg') AS image_src,
+		       duration, destination, guaranteed
+		FROM tour_search_index
+		WHERE status_id = (SELECT id FROM statuses WHERE name = 'active')
+		ORDER BY price ASC
+		LIMIT 12`
+
+	cardsJoinSQL = `
+		SELECT tours.id, tours.title, tours.price, tours.rating,
+		       COALESCE(tour_card_images.image_src, 'no-image.jpg') AS image_src
+		FROM tours
+		LEFT JOIN tour_card_images ON tours.id = tour_card_images.tour_id
+		WHERE tours.status_id = (SELECT id FROM statuses WHERE name = 'active')
+		ORDER BY tours.id ASC`
+
+	cardsIndexSQL = `
+		SELECT tour_id, title, price, rating,
+		       COALESCE(image_src, 'no-image.jpg') AS image_src
+		FROM tour_search_index
+		WHERE status_id = (SELECT id FROM statuses WHERE name = 'active')
+		ORDER BY tour_id ASC`
+)
+
+func BenchmarkSearchSubqueries(b *testing.B) { benchQuery(b, searchSubqueriesSQL) }
+func BenchmarkSearchIndex(b *testing.B)      { benchQuery(b, searchIndexSQL) }
+func BenchmarkCardsJoin(b *testing.B)        { benchQuery(b, cardsJoinSQL) }
+func BenchmarkCardsIndex(b *testing.B)       { benchQuery(b, cardsIndexSQL) }
+
+// benchQuery runs q and drains its rows b.N times.
+func benchQuery(b *testing.B, q string) {
+	conn := benchDB(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := conn.Query(q)
+		if err != nil {
+			b.Fatalf("query: %v", err)
+		}
+		cols, _ := rows.Columns()
+		dest := make([]interface{}, len(cols))
+		for j := range dest {
+			dest[j] = new(sql.RawBytes)
+		}
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				b.Fatalf("scan: %v", err)
+			}
+		}
+		rows.Close()
+	}
+}
+
+var benchConn *sql.DB
+
+func benchDB(b *testing.B) *sql.DB {
+	if benchConn != nil {
+		return benchConn
+	}
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "host=localhost port=5432 user=touruser password=tourpass123 dbname=tourdb sslmode=disable"
+	}
+	conn, err := sql.Open("postgres", dsn)
+	if err == nil {
+		err = conn.Ping()
+	}
+	if err != nil {
+		b.Skipf("database unavailable: %v", err)
+	}
+	var migrated bool
+	conn.QueryRow("SELECT to_regclass('tour_search_index') IS NOT NULL").Scan(&migrated)
+	if !migrated {
+		b.Skip("tour_search_index missing: run tour-server/searchindex/migration.sql")
+	}
+	benchConn = conn
+	return conn
+}
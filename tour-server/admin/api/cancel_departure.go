@@ -0,0 +1,319 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"tour-server/email"
+	"tour-server/liqpay"
+	"tour-server/notify"
+	"tour-server/webhooks"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CancelDepartureRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Refund outcomes reported per booking.
+const (
+	RefundRefunded = "refunded"
+	RefundFailed   = "failed"
+	RefundNotPaid  = "not_paid"
+)
+
+var (
+	ErrTourDateNotFound   = errors.New("Tour date not found")
+	ErrDepartureCancelled = errors.New("Departure is already cancelled")
+)
+
+// CancelledBooking is one line of the cancellation report.
+type CancelledBooking struct {
+	BookingID      uint    `json:"booking_id"`
+	CustomerName   string  `json:"customer_name"`
+	PreviousStatus string  `json:"previous_status"`
+	Seats          int     `json:"seats"`
+	TotalPrice     float64 `json:"total_price"`
+	Refund         string  `json:"refund"`
+	Emailed        bool    `json:"emailed"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// DepartureCancellationReport summarises what CancelDeparture processed
+// and what still needs a human — chiefly refunds LiqPay rejected.
+type DepartureCancellationReport struct {
+	TourDateID   uint               `json:"tour_date_id"`
+	TourID       uint               `json:"tour_id"`
+	Cancelled    int                `json:"cancelled"`
+	Refunded     int                `json:"refunded"`
+	RefundFailed int                `json:"refund_failed"`
+	Notified     int                `json:"notified"`
+	Alternatives int                `json:"alternatives_offered"`
+	Bookings     []CancelledBooking `json:"bookings"`
+}
+
+// CancelTourDate cancels a whole departure on the operator's side:
+// POST /admin/tours/:id/dates/:dateId/cancel.
+func CancelTourDate(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+		dateID, err := strconv.Atoi(c.Param("dateId"))
+		if err != nil || dateID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID дати",
+			})
+		}
+
+		var req CancelDepartureRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		req.Reason = strings.TrimSpace(req.Reason)
+		if len(req.Reason) > 500 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "reason must be at most 500 characters",
+			})
+		}
+
+		var exists int64
+		db.Table("tour_dates").Where("id = ? AND tour_id = ?", dateID, tourID).Count(&exists)
+		if exists == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": ErrTourDateNotFound.Error(),
+			})
+		}
+
+		report, err := CancelDeparture(db, uint(dateID), req.Reason)
+		switch {
+		case errors.Is(err, ErrTourDateNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, ErrDepartureCancelled):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case err != nil:
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
+
+// CancelDeparture retires a departure and cancels every active booking on
+// it. The departure and its bookings change state in one transaction; each
+// booking is claimed with a conditional UPDATE that also marks a paid
+// booking reversed, so the LiqPay "reversed" callback for our own refund
+// finds nothing left to do and the customer gets a single email. Refunds
+// run after the commit; a failed refund doesn't stop the run — the booking
+// goes back to payment_status 'paid' and is recorded in the report for
+// staff to finish by hand. Each customer gets a departure-cancelled email
+// listing other dates of the same tour that still have room for their party.
+func CancelDeparture(db *gorm.DB, tourDateID uint, reason string) (DepartureCancellationReport, error) {
+	report := DepartureCancellationReport{TourDateID: tourDateID, Bookings: []CancelledBooking{}}
+
+	var date struct {
+		ID          uint       `gorm:"column:id"`
+		TourID      uint       `gorm:"column:tour_id"`
+		TourTitle   string     `gorm:"column:tour_title"`
+		DateFrom    time.Time  `gorm:"column:date_from"`
+		DateTo      time.Time  `gorm:"column:date_to"`
+		CancelledAt *time.Time `gorm:"column:cancelled_at"`
+	}
+	db.Raw(`
+		SELECT td.id, td.tour_id, t.title AS tour_title, td.date_from, td.date_to, td.cancelled_at
+		FROM tour_dates td
+		JOIN tours t ON td.tour_id = t.id
+		WHERE td.id = ?
+	`, tourDateID).Scan(&date)
+	if date.ID == 0 {
+		return report, ErrTourDateNotFound
+	}
+	report.TourID = date.TourID
+
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	// Claim the departure first so a second admin (or the go/no-go job)
+	// can't process the same bookings concurrently.
+	result := tx.Exec(`
+		UPDATE tour_dates
+		SET is_retired = TRUE, cancelled_at = NOW(), cancellation_reason = ?
+		WHERE id = ? AND cancelled_at IS NULL
+	`, reason, tourDateID)
+	if result.Error != nil {
+		return report, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return report, ErrDepartureCancelled
+	}
+
+	var bookings []struct {
+		ID            uint    `gorm:"column:id"`
+		CustomerName  string  `gorm:"column:customer_name"`
+		CustomerEmail string  `gorm:"column:customer_email"`
+		Seats         int     `gorm:"column:seats"`
+		TotalPrice    float64 `gorm:"column:total_price"`
+		Status        string  `gorm:"column:status"`
+		PaymentStatus string  `gorm:"column:payment_status"`
+		OrderID       string  `gorm:"column:liqpay_order_id"`
+	}
+	if err := tx.Raw(`
+		SELECT id, customer_name, COALESCE(customer_email, '') AS customer_email,
+			seats, total_price, status, COALESCE(payment_status, 'pending') AS payment_status,
+			COALESCE(liqpay_order_id, '') AS liqpay_order_id
+		FROM bookings
+		WHERE tour_date_id = ? AND status <> 'cancelled'
+		ORDER BY id
+		FOR UPDATE
+	`, tourDateID).Scan(&bookings).Error; err != nil {
+		return report, err
+	}
+
+	claimed := bookings[:0]
+	for _, b := range bookings {
+		res := tx.Exec(`
+			UPDATE bookings
+			SET status = 'cancelled',
+				payment_status = CASE WHEN payment_status = 'paid' THEN 'reversed' ELSE payment_status END
+			WHERE id = ? AND status <> 'cancelled' AND payment_status IS DISTINCT FROM 'reversed'
+		`, b.ID)
+		if res.Error != nil {
+			return report, res.Error
+		}
+		if res.RowsAffected == 0 {
+			// Cancelled by the customer or reversed by LiqPay in the meantime.
+			continue
+		}
+		claimed = append(claimed, b)
+	}
+
+	if err := RecalculateSeats(tx, tourDateID); err != nil {
+		return report, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return report, err
+	}
+
+	alternatives := alternativeDepartures(db, date.TourID, tourDateID)
+
+	for _, b := range claimed {
+		item := CancelledBooking{
+			BookingID:      b.ID,
+			CustomerName:   b.CustomerName,
+			PreviousStatus: b.Status,
+			Seats:          b.Seats,
+			TotalPrice:     b.TotalPrice,
+			Refund:         RefundNotPaid,
+		}
+		report.Cancelled++
+
+		if b.PaymentStatus == "paid" {
+			err := fmt.Errorf("no LiqPay order for booking #%d", b.ID)
+			if b.OrderID != "" {
+				err = liqpay.Refund(b.OrderID, b.TotalPrice)
+			}
+			if err != nil {
+				log.Printf("Departure #%d: refund for booking #%d failed: %v", tourDateID, b.ID, err)
+				db.Exec("UPDATE bookings SET payment_status = 'paid' WHERE id = ? AND payment_status = 'reversed'", b.ID)
+				item.Refund = RefundFailed
+				item.Error = err.Error()
+				report.RefundFailed++
+			} else {
+				item.Refund = RefundRefunded
+				report.Refunded++
+			}
+		}
+
+		notify.NotifyBooking(db, notify.EventBookingCancelled, b.ID)
+		webhooks.PublishBooking(db, webhooks.EventBookingCancelled, b.ID)
+
+		if b.CustomerEmail != "" {
+			var offered []email.AlternativeDate
+			for _, a := range alternatives {
+				if a.AvailableSeats >= b.Seats {
+					offered = append(offered, a)
+				}
+			}
+			report.Alternatives += len(offered)
+
+			email.NotifyDepartureCancelled(b.CustomerEmail, email.DepartureCancellation{
+				CustomerName: b.CustomerName,
+				BookingID:    b.ID,
+				TourTitle:    date.TourTitle,
+				DateFrom:     date.DateFrom,
+				DateTo:       date.DateTo,
+				Reason:       reason,
+				TotalPrice:   b.TotalPrice,
+				Refunded:     item.Refund == RefundRefunded,
+				RefundFailed: item.Refund == RefundFailed,
+				Alternatives: offered,
+				TourURL:      buildTourURL(date.TourID),
+			})
+			item.Emailed = true
+			report.Notified++
+		}
+
+		report.Bookings = append(report.Bookings, item)
+	}
+
+	webhooks.PublishTour(db, date.TourID)
+
+	log.Printf("Departure #%d cancelled: %d booking(s), %d refunded, %d refund(s) failed",
+		tourDateID, report.Cancelled, report.Refunded, report.RefundFailed)
+	return report, nil
+}
+
+// alternativeDepartures returns up to five upcoming bookable dates of the
+// tour other than excludeID.
+func alternativeDepartures(db *gorm.DB, tourID, excludeID uint) []email.AlternativeDate {
+	var rows []struct {
+		DateFrom       time.Time `gorm:"column:date_from"`
+		DateTo         time.Time `gorm:"column:date_to"`
+		AvailableSeats int       `gorm:"column:available_seats"`
+	}
+	db.Raw(`
+		SELECT td.date_from, td.date_to, ts.available_seats
+		FROM tour_dates td
+		JOIN tour_seats ts ON ts.tour_date_id = td.id
+		WHERE td.tour_id = ? AND td.id <> ? AND NOT td.is_retired
+			AND td.date_from > NOW() AND ts.available_seats > 0
+		ORDER BY td.date_from
+		LIMIT 5
+	`, tourID, excludeID).Scan(&rows)
+
+	out := make([]email.AlternativeDate, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, email.AlternativeDate{
+			DateFrom:       r.DateFrom,
+			DateTo:         r.DateTo,
+			AvailableSeats: r.AvailableSeats,
+		})
+	}
+	return out
+}
+
+func buildTourURL(tourID uint) string {
+	base := os.Getenv("FRONTEND_URL")
+	if base == "" {
+		base = "https://openworld.local"
+	}
+	return fmt.Sprintf("%s/TourDetails/%d", base, tourID)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestCancelTourDate_InvalidDateID(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/tours/1/dates/abc/cancel", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "dateId")
+	c.SetParamValues("1", "abc")
+
+	handler := CancelTourDate(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestCancelTourDate_ReasonTooLong(t *testing.T) {
+	e := echo.New()
+
+	body := `{"reason": "` + strings.Repeat("x", 501) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/admin/tours/1/dates/2/cancel", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "dateId")
+	c.SetParamValues("1", "2")
+
+	handler := CancelTourDate(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestBuildTourURL(t *testing.T) {
+	t.Setenv("FRONTEND_URL", "https://example.com")
+	if got := buildTourURL(12); got != "https://example.com/TourDetails/12" {
+		t.Errorf("unexpected tour URL: %s", got)
+	}
+}
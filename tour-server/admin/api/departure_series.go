@@ -0,0 +1,575 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"tour-server/tourdate/recurrence"
+	"tour-server/webhooks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// DepartureSeriesRequest describes a recurring set of departures, e.g.
+// every Saturday of the summer season for 7 days. With Preview set the
+// handler only returns the plan and writes nothing.
+type DepartureSeriesRequest struct {
+	RRule              string   `json:"rrule"`        // "FREQ=WEEKLY;BYDAY=SA"
+	SeasonStart        string   `json:"season_start"` // YYYY-MM-DD
+	SeasonEnd          string   `json:"season_end"`   // YYYY-MM-DD
+	DurationDays       int      `json:"duration_days"`
+	Exceptions         []string `json:"exceptions"` // YYYY-MM-DD dates to leave out
+	FromLocationID     uint     `json:"from_location_id"`
+	ToLocationID       uint     `json:"to_location_id"`
+	TotalSeats         *int     `json:"total_seats"`
+	MinParticipants    *int     `json:"min_participants"`
+	DecisionDaysBefore *int     `json:"decision_days_before"`
+	Preview            bool     `json:"preview"`
+}
+
+// Plan actions for one departure.
+const (
+	SeriesCreate       = "create"        // new departure
+	SeriesSkipExisting = "skip_existing" // a departure already exists that day
+	SeriesUpdate       = "update"        // kept, route/seats/go-no-go brought in line with the series
+	SeriesKeepBooked   = "keep_booked"   // no longer in the rule, but has bookings
+	SeriesKeepRetired  = "keep_retired"  // retired earlier; left as it is
+	SeriesRemove       = "remove"        // no longer in the rule, never booked — deleted
+	SeriesRetire       = "retire"        // no longer in the rule, only cancelled bookings
+)
+
+// SeriesOccurrence is one line of a generation plan.
+type SeriesOccurrence struct {
+	DateFrom   string `json:"date_from"`
+	DateTo     string `json:"date_to"`
+	Action     string `json:"action"`
+	TourDateID uint   `json:"tour_date_id,omitempty"`
+}
+
+// SeriesPlan is what generating (or regenerating) a series does.
+type SeriesPlan struct {
+	SeriesID    uint               `json:"series_id,omitempty"`
+	Preview     bool               `json:"preview"`
+	Created     int                `json:"created"`
+	Skipped     int                `json:"skipped"`
+	Updated     int                `json:"updated"`
+	Kept        int                `json:"kept"`
+	Removed     int                `json:"removed"`
+	Retired     int                `json:"retired"`
+	Occurrences []SeriesOccurrence `json:"occurrences"`
+}
+
+func (p *SeriesPlan) add(o SeriesOccurrence) {
+	switch o.Action {
+	case SeriesCreate:
+		p.Created++
+	case SeriesSkipExisting:
+		p.Skipped++
+	case SeriesUpdate:
+		p.Updated++
+	case SeriesKeepBooked, SeriesKeepRetired:
+		p.Kept++
+	case SeriesRemove:
+		p.Removed++
+	case SeriesRetire:
+		p.Retired++
+	}
+	p.Occurrences = append(p.Occurrences, o)
+}
+
+// seriesSpec is a validated DepartureSeriesRequest.
+type seriesSpec struct {
+	req        DepartureSeriesRequest
+	start, end time.Time
+	exceptions []time.Time
+	dates      []time.Time
+}
+
+// maxSeasonDays bounds how far ahead a single series may reach.
+const maxSeasonDays = 2 * 366
+
+func parseSeriesRequest(req DepartureSeriesRequest) (seriesSpec, string) {
+	spec := seriesSpec{req: req}
+
+	if req.FromLocationID == 0 || req.ToLocationID == 0 {
+		return spec, "from_location_id and to_location_id are required"
+	}
+	if req.DurationDays < 0 || req.DurationDays > 60 {
+		return spec, "duration_days must be between 0 and 60"
+	}
+	if req.TotalSeats != nil && *req.TotalSeats <= 0 {
+		return spec, "total_seats must be > 0"
+	}
+
+	rule, err := recurrence.Parse(req.RRule)
+	if err != nil {
+		return spec, err.Error()
+	}
+
+	start, err1 := time.Parse("2006-01-02", req.SeasonStart)
+	end, err2 := time.Parse("2006-01-02", req.SeasonEnd)
+	if err1 != nil || err2 != nil || end.Before(start) {
+		return spec, "season_start and season_end must be YYYY-MM-DD, end not before start"
+	}
+	if end.Sub(start).Hours()/24 > maxSeasonDays {
+		return spec, "season may span at most two years"
+	}
+	spec.start, spec.end = start, end
+
+	for _, e := range req.Exceptions {
+		d, err := time.Parse("2006-01-02", e)
+		if err != nil {
+			return spec, "exceptions must be YYYY-MM-DD dates"
+		}
+		spec.exceptions = append(spec.exceptions, d)
+	}
+
+	spec.dates, err = rule.Expand(start, end, spec.exceptions)
+	if err != nil {
+		return spec, err.Error()
+	}
+	if len(spec.dates) == 0 {
+		return spec, "rule produces no dates in the season"
+	}
+	return spec, ""
+}
+
+func (s seriesSpec) dateTo(from time.Time) time.Time {
+	return from.AddDate(0, 0, s.req.DurationDays)
+}
+
+// GetDepartureSeries lists a tour's series with their departure counts.
+func GetDepartureSeries(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		var series []struct {
+			ID             uint           `json:"id" gorm:"column:id"`
+			RRule          string         `json:"rrule" gorm:"column:rrule"`
+			SeasonStart    time.Time      `json:"season_start" gorm:"column:season_start"`
+			SeasonEnd      time.Time      `json:"season_end" gorm:"column:season_end"`
+			DurationDays   int            `json:"duration_days" gorm:"column:duration_days"`
+			Exceptions     pq.StringArray `json:"exceptions" gorm:"column:exceptions;type:date[]"`
+			FromLocationID uint           `json:"from_location_id" gorm:"column:from_location_id"`
+			ToLocationID   uint           `json:"to_location_id" gorm:"column:to_location_id"`
+			TotalSeats     *int           `json:"total_seats" gorm:"column:total_seats"`
+			Departures     int            `json:"departures" gorm:"column:departures"`
+			Upcoming       int            `json:"upcoming" gorm:"column:upcoming"`
+		}
+		err = db.Raw(`
+			SELECT s.id, s.rrule, s.season_start, s.season_end, s.duration_days, s.exceptions,
+				s.from_location_id, s.to_location_id, s.total_seats,
+				COUNT(td.id) AS departures,
+				COUNT(td.id) FILTER (WHERE td.date_from > NOW() AND NOT td.is_retired) AS upcoming
+			FROM departure_series s
+			LEFT JOIN tour_dates td ON td.series_id = s.id
+			WHERE s.tour_id = ?
+			GROUP BY s.id
+			ORDER BY s.season_start
+		`, tourID).Scan(&series).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch departure series",
+			})
+		}
+
+		return c.JSON(http.StatusOK, series)
+	}
+}
+
+// CreateDepartureSeries generates departures from a recurrence rule.
+// Days on which the tour already has a departure are skipped.
+func CreateDepartureSeries(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		var req DepartureSeriesRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		spec, msg := parseSeriesRequest(req)
+		if msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		var tourSeats int
+		db.Raw("SELECT total_seats FROM tours WHERE id = ?", tourID).Scan(&tourSeats)
+		if tourSeats == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tour not found",
+			})
+		}
+		capacity := tourSeats
+		if req.TotalSeats != nil {
+			capacity = *req.TotalSeats
+		}
+		if msg := validateGoNoGo(req.MinParticipants, req.DecisionDaysBefore, capacity); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		existing := existingDepartureDays(db, uint(tourID), nil)
+
+		plan := SeriesPlan{Preview: req.Preview, Occurrences: []SeriesOccurrence{}}
+		for _, d := range spec.dates {
+			action := SeriesCreate
+			if existing[d] {
+				action = SeriesSkipExisting
+			}
+			plan.add(SeriesOccurrence{
+				DateFrom: d.Format("2006-01-02"),
+				DateTo:   spec.dateTo(d).Format("2006-01-02"),
+				Action:   action,
+			})
+		}
+
+		if req.Preview {
+			return c.JSON(http.StatusOK, plan)
+		}
+
+		tx := db.Begin()
+
+		var seriesID uint
+		err = tx.Raw(`
+			INSERT INTO departure_series (tour_id, rrule, season_start, season_end, duration_days, exceptions,
+				from_location_id, to_location_id, total_seats, min_participants, decision_days_before)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			RETURNING id
+		`, tourID, req.RRule, spec.start, spec.end, req.DurationDays, pq.StringArray(req.Exceptions),
+			req.FromLocationID, req.ToLocationID, req.TotalSeats, positiveOrNil(req.MinParticipants),
+			decisionDaysOrDefault(req.DecisionDaysBefore)).Scan(&seriesID).Error
+		if err != nil || seriesID == 0 {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create departure series",
+			})
+		}
+		plan.SeriesID = seriesID
+
+		if err := insertSeriesDates(tx, uint(tourID), seriesID, spec, capacity, &plan); err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create tour dates",
+			})
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to save departure series",
+			})
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusCreated, plan)
+	}
+}
+
+// RegenerateDepartureSeries replaces a series' rule and rebuilds its
+// upcoming departures. Past departures and any upcoming departure with
+// active bookings are never touched; unbooked ones that no longer fit the
+// rule are deleted (or retired, if they have cancelled bookings that still
+// reference them), and unbooked ones that still fit take the series' new
+// route, capacity and go/no-go settings. Retired departures stay retired.
+func RegenerateDepartureSeries(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+		seriesID, err := strconv.Atoi(c.Param("seriesId"))
+		if err != nil || seriesID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID серії",
+			})
+		}
+
+		var req DepartureSeriesRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		spec, msg := parseSeriesRequest(req)
+		if msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		var tourSeats int
+		db.Raw(`
+			SELECT t.total_seats FROM departure_series s JOIN tours t ON s.tour_id = t.id
+			WHERE s.id = ? AND s.tour_id = ?
+		`, seriesID, tourID).Scan(&tourSeats)
+		if tourSeats == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Departure series not found",
+			})
+		}
+		capacity := tourSeats
+		if req.TotalSeats != nil {
+			capacity = *req.TotalSeats
+		}
+		if msg := validateGoNoGo(req.MinParticipants, req.DecisionDaysBefore, capacity); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		// Upcoming departures currently in the series.
+		var current []struct {
+			ID                 uint      `gorm:"column:id"`
+			DateFrom           time.Time `gorm:"column:date_from"`
+			DateTo             time.Time `gorm:"column:date_to"`
+			FromLocationID     uint      `gorm:"column:from_location_id"`
+			ToLocationID       uint      `gorm:"column:to_location_id"`
+			Capacity           int       `gorm:"column:capacity"`
+			AvailableSeats     int       `gorm:"column:available_seats"`
+			MinParticipants    *int      `gorm:"column:min_participants"`
+			DecisionDaysBefore int       `gorm:"column:decision_days_before"`
+			IsRetired          bool      `gorm:"column:is_retired"`
+			Bookings           int       `gorm:"column:bookings"`
+			ActiveBookings     int       `gorm:"column:active_bookings"`
+		}
+		err = db.Raw(`
+			SELECT td.id, td.date_from, td.date_to, td.from_location_id, td.to_location_id,
+				COALESCE(td.total_seats, t.total_seats) AS capacity,
+				COALESCE(ts.available_seats, 0) AS available_seats,
+				td.min_participants, td.decision_days_before, td.is_retired,
+				COUNT(b.id) AS bookings,
+				COUNT(b.id) FILTER (WHERE b.status <> 'cancelled') AS active_bookings
+			FROM tour_dates td
+			JOIN tours t ON td.tour_id = t.id
+			LEFT JOIN tour_seats ts ON ts.tour_date_id = td.id
+			LEFT JOIN bookings b ON b.tour_date_id = td.id
+			WHERE td.series_id = ? AND td.date_from > NOW() AND td.cancelled_at IS NULL
+			GROUP BY td.id, t.total_seats, ts.available_seats
+			ORDER BY td.date_from
+		`, seriesID).Scan(&current).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch series departures",
+			})
+		}
+
+		tomorrow := recurrence.Day(time.Now()).AddDate(0, 0, 1)
+		wanted := map[time.Time]bool{}
+		for _, d := range spec.dates {
+			if !d.Before(tomorrow) {
+				wanted[d] = true
+			}
+		}
+
+		plan := SeriesPlan{SeriesID: uint(seriesID), Preview: req.Preview, Occurrences: []SeriesOccurrence{}}
+		minParticipants := positiveOrNil(req.MinParticipants)
+		decisionDays := decisionDaysOrDefault(req.DecisionDaysBefore)
+		var removeIDs, retireIDs, updateIDs []uint
+		for _, td := range current {
+			from := recurrence.Day(td.DateFrom)
+			o := SeriesOccurrence{
+				DateFrom:   from.Format("2006-01-02"),
+				DateTo:     td.DateTo.Format("2006-01-02"),
+				TourDateID: td.ID,
+			}
+			upToDate := td.FromLocationID == req.FromLocationID && td.ToLocationID == req.ToLocationID &&
+				td.Capacity == capacity && td.AvailableSeats == capacity &&
+				td.DecisionDaysBefore == decisionDays && positiveOrNil(td.MinParticipants) == minParticipants
+			switch {
+			case td.IsRetired:
+				o.Action = SeriesKeepRetired
+			case td.ActiveBookings > 0:
+				o.Action = SeriesKeepBooked
+			case wanted[from] && recurrence.Day(td.DateTo).Equal(spec.dateTo(from)) && upToDate:
+				o.Action = SeriesSkipExisting
+			case wanted[from] && recurrence.Day(td.DateTo).Equal(spec.dateTo(from)):
+				o.Action = SeriesUpdate
+				updateIDs = append(updateIDs, td.ID)
+			case td.Bookings > 0:
+				o.Action = SeriesRetire
+				retireIDs = append(retireIDs, td.ID)
+			default:
+				o.Action = SeriesRemove
+				removeIDs = append(removeIDs, td.ID)
+			}
+			plan.add(o)
+		}
+
+		// Days still occupied after the removals above — kept series dates
+		// and any other departure of the tour.
+		existing := existingDepartureDays(db, uint(tourID), append(append([]uint{}, removeIDs...), retireIDs...))
+
+		for _, d := range spec.dates {
+			if !wanted[d] || existing[d] {
+				continue
+			}
+			plan.add(SeriesOccurrence{
+				DateFrom: d.Format("2006-01-02"),
+				DateTo:   spec.dateTo(d).Format("2006-01-02"),
+				Action:   SeriesCreate,
+			})
+		}
+
+		if req.Preview {
+			return c.JSON(http.StatusOK, plan)
+		}
+
+		tx := db.Begin()
+
+		if len(removeIDs) > 0 {
+			if err := tx.Exec("DELETE FROM tour_seats WHERE tour_date_id IN ?", removeIDs).Error; err != nil {
+				tx.Rollback()
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to remove tour seats",
+				})
+			}
+			if err := tx.Exec("DELETE FROM tour_dates WHERE id IN ?", removeIDs).Error; err != nil {
+				tx.Rollback()
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to remove tour dates",
+				})
+			}
+		}
+		if len(retireIDs) > 0 {
+			if err := tx.Exec("UPDATE tour_dates SET is_retired = TRUE WHERE id IN ?", retireIDs).Error; err != nil {
+				tx.Rollback()
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to retire tour dates",
+				})
+			}
+		}
+		if len(updateIDs) > 0 {
+			err = tx.Exec(`
+				UPDATE tour_dates
+				SET from_location_id = ?, to_location_id = ?, total_seats = ?,
+					min_participants = ?, decision_days_before = ?
+				WHERE id IN ?
+			`, req.FromLocationID, req.ToLocationID, capacity, minParticipants, decisionDays, updateIDs).Error
+			if err != nil {
+				tx.Rollback()
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to update tour dates",
+				})
+			}
+			for _, id := range updateIDs {
+				if err := RecalculateSeats(tx, id); err != nil {
+					tx.Rollback()
+					return c.JSON(http.StatusInternalServerError, map[string]string{
+						"error": "Failed to recalculate seats",
+					})
+				}
+			}
+		}
+
+		err = tx.Exec(`
+			UPDATE departure_series
+			SET rrule = ?, season_start = ?, season_end = ?, duration_days = ?, exceptions = ?,
+				from_location_id = ?, to_location_id = ?, total_seats = ?, min_participants = ?,
+				decision_days_before = ?, updated_at = NOW()
+			WHERE id = ?
+		`, req.RRule, spec.start, spec.end, req.DurationDays, pq.StringArray(req.Exceptions),
+			req.FromLocationID, req.ToLocationID, req.TotalSeats, minParticipants, decisionDays, seriesID).Error
+		if err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update departure series",
+			})
+		}
+
+		if err := insertSeriesDates(tx, uint(tourID), uint(seriesID), spec, capacity, &plan); err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create tour dates",
+			})
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to save departure series",
+			})
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusOK, plan)
+	}
+}
+
+// insertSeriesDates creates every departure the plan marks SeriesCreate
+// and records the new IDs in the plan.
+func insertSeriesDates(tx *gorm.DB, tourID, seriesID uint, spec seriesSpec, capacity int, plan *SeriesPlan) error {
+	for i, o := range plan.Occurrences {
+		if o.Action != SeriesCreate {
+			continue
+		}
+		from, _ := time.Parse("2006-01-02", o.DateFrom)
+
+		var tourDateID uint
+		err := tx.Raw(`
+			INSERT INTO tour_dates (tour_id, from_location_id, to_location_id, date_from, date_to,
+				total_seats, min_participants, decision_days_before, series_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			RETURNING id
+		`, tourID, spec.req.FromLocationID, spec.req.ToLocationID, from, spec.dateTo(from),
+			capacity, positiveOrNil(spec.req.MinParticipants), decisionDaysOrDefault(spec.req.DecisionDaysBefore),
+			seriesID).Scan(&tourDateID).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("INSERT INTO tour_seats (tour_date_id, available_seats) VALUES (?, ?)", tourDateID, capacity).Error; err != nil {
+			return err
+		}
+		plan.Occurrences[i].TourDateID = tourDateID
+	}
+	return nil
+}
+
+// existingDepartureDays returns the start days of the tour's departures
+// that aren't cancelled, ignoring the IDs in exclude.
+func existingDepartureDays(db *gorm.DB, tourID uint, exclude []uint) map[time.Time]bool {
+	q := db.Table("tour_dates").Select("date_from").Where("tour_id = ? AND cancelled_at IS NULL", tourID)
+	if len(exclude) > 0 {
+		q = q.Where("id NOT IN ?", exclude)
+	}
+	var days []time.Time
+	q.Scan(&days)
+
+	out := make(map[time.Time]bool, len(days))
+	for _, d := range days {
+		out[recurrence.Day(d)] = true
+	}
+	return out
+}
+
+func positiveOrNil(v *int) interface{} {
+	if v == nil || *v <= 0 {
+		return nil
+	}
+	return *v
+}
+
+func decisionDaysOrDefault(v *int) int {
+	if v == nil {
+		return 7
+	}
+	return *v
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseSeriesRequest(t *testing.T) {
+	base := DepartureSeriesRequest{
+		RRule:          "FREQ=WEEKLY;BYDAY=SA",
+		SeasonStart:    "2026-06-01",
+		SeasonEnd:      "2026-06-30",
+		DurationDays:   7,
+		Exceptions:     []string{"2026-06-13"},
+		FromLocationID: 1,
+		ToLocationID:   2,
+	}
+
+	spec, msg := parseSeriesRequest(base)
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if len(spec.dates) != 3 {
+		t.Errorf("expected 3 dates, got %d", len(spec.dates))
+	}
+	if got := spec.dateTo(spec.dates[0]).Format("2006-01-02"); got != "2026-06-13" {
+		t.Errorf("expected date_to 2026-06-13, got %s", got)
+	}
+
+	bad := []func(r *DepartureSeriesRequest){
+		func(r *DepartureSeriesRequest) { r.RRule = "FREQ=HOURLY" },
+		func(r *DepartureSeriesRequest) { r.SeasonEnd = "2026-05-01" },
+		func(r *DepartureSeriesRequest) { r.SeasonEnd = "2029-01-01" },
+		func(r *DepartureSeriesRequest) { r.DurationDays = -1 },
+		func(r *DepartureSeriesRequest) { r.Exceptions = []string{"13.06.2026"} },
+		func(r *DepartureSeriesRequest) { r.ToLocationID = 0 },
+		func(r *DepartureSeriesRequest) { r.RRule = "FREQ=WEEKLY;BYDAY=SA;UNTIL=20260501" },
+	}
+	for i, mutate := range bad {
+		r := base
+		mutate(&r)
+		if _, msg := parseSeriesRequest(r); msg == "" {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+}
+
+func TestCreateDepartureSeries_InvalidRule(t *testing.T) {
+	e := echo.New()
+
+	body := `{"rrule": "FREQ=YEARLY", "season_start": "2026-06-01", "season_end": "2026-08-31", "duration_days": 7, "from_location_id": 1, "to_location_id": 2}`
+	req := httptest.NewRequest(http.MethodPost, "/admin/tours/1/series", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := CreateDepartureSeries(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestRegenerateDepartureSeries_InvalidSeriesID(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/tours/1/series/x", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "seriesId")
+	c.SetParamValues("1", "x")
+
+	handler := RegenerateDepartureSeries(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestSeriesPlanAdd(t *testing.T) {
+	var plan SeriesPlan
+	for _, a := range []string{SeriesCreate, SeriesSkipExisting, SeriesUpdate, SeriesKeepBooked, SeriesKeepRetired, SeriesRetire} {
+		plan.add(SeriesOccurrence{Action: a})
+	}
+	if plan.Created != 1 || plan.Skipped != 1 || plan.Updated != 1 || plan.Kept != 2 || plan.Retired != 1 {
+		t.Errorf("unexpected counts: %+v", plan)
+	}
+	if len(plan.Occurrences) != 6 {
+		t.Errorf("expected 6 occurrences, got %d", len(plan.Occurrences))
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"log"
+	"time"
+	"tour-server/email"
+	"tour-server/webhooks"
+
+	"gorm.io/gorm"
+)
+
+// NoGoReason is recorded on departures cancelled by the go/no-go job and
+// shown to customers in the cancellation email.
+const NoGoReason = "Не набрано мінімальну кількість учасників"
+
+// confirmedSeatsSQL counts travellers on confirmed bookings of td. Pending
+// bookings don't count towards the minimum — many are never paid.
+const confirmedSeatsSQL = `(
+	SELECT COALESCE(SUM(b.seats), 0) FROM bookings b
+	WHERE b.tour_date_id = td.id AND b.status = 'confirmed'
+)`
+
+// lostGuaranteeSQL matches guaranteed upcoming departures that no longer
+// meet their threshold — bookings were cancelled, or min_participants was
+// raised or removed.
+const lostGuaranteeSQL = `td.is_guaranteed
+		  AND td.date_from > NOW()
+		  AND (td.min_participants IS NULL OR ` + confirmedSeatsSQL + ` < td.min_participants)`
+
+// StartGoNoGoJob periodically decides departures that have a
+// min_participants threshold: they are marked guaranteed as soon as the
+// threshold is met (and lose the mark if they drop below it again), and
+// cancelled with refunds (via CancelDeparture) if it still isn't met
+// decision_days_before days before departure.
+func StartGoNoGoJob(db *gorm.DB, interval time.Duration) {
+	go func() {
+		for {
+			RunGoNoGo(db)
+			time.Sleep(interval)
+		}
+	}()
+	log.Printf("Go/no-go job started: every %s", interval)
+}
+
+// RunGoNoGo performs one pass of the go/no-go decisions.
+func RunGoNoGo(db *gorm.DB) {
+	revokeLostGuarantees(db)
+	guaranteeDepartures(db)
+	cancelUnderbookedDepartures(db)
+}
+
+func guaranteeDepartures(db *gorm.DB) {
+	var rows []struct {
+		ID     uint `gorm:"column:id"`
+		TourID uint `gorm:"column:tour_id"`
+	}
+	// The UPDATE claims each departure, so a departure is announced once
+	// even if two instances run the job.
+	err := db.Raw(`
+		UPDATE tour_dates td
+		SET is_guaranteed = TRUE, guaranteed_at = NOW()
+		WHERE td.min_participants IS NOT NULL
+		  AND NOT td.is_guaranteed
+		  AND NOT td.is_retired
+		  AND td.cancelled_at IS NULL
+		  AND td.date_from > NOW()
+		  AND ` + confirmedSeatsSQL + ` >= td.min_participants
+		RETURNING td.id, td.tour_id
+	`).Scan(&rows).Error
+	if err != nil {
+		log.Printf("Go/no-go: guarantee query failed: %v", err)
+		return
+	}
+
+	for _, r := range rows {
+		n := notifyDepartureGuaranteed(db, r.ID)
+		webhooks.PublishTour(db, r.TourID)
+		log.Printf("Go/no-go: departure #%d guaranteed, %d customer(s) notified", r.ID, n)
+	}
+}
+
+// revokeLostGuarantees catches departures that fell below the minimum
+// through booking cancellations, which don't go through RecalculateSeats.
+func revokeLostGuarantees(db *gorm.DB) {
+	var ids []uint
+	err := db.Raw(`
+		UPDATE tour_dates td
+		SET is_guaranteed = FALSE, guaranteed_at = NULL
+		WHERE ` + lostGuaranteeSQL + `
+		RETURNING td.id
+	`).Scan(&ids).Error
+	if err != nil {
+		log.Printf("Go/no-go: revoke query failed: %v", err)
+		return
+	}
+	for _, id := range ids {
+		log.Printf("Go/no-go: departure #%d is below its minimum again, no longer guaranteed", id)
+	}
+}
+
+func cancelUnderbookedDepartures(db *gorm.DB) {
+	var ids []uint
+	err := db.Raw(`
+		SELECT td.id
+		FROM tour_dates td
+		WHERE td.min_participants IS NOT NULL
+		  AND NOT td.is_guaranteed
+		  AND td.cancelled_at IS NULL
+		  AND td.date_from > NOW()
+		  AND td.date_from - make_interval(days => td.decision_days_before) <= NOW()
+		  AND ` + confirmedSeatsSQL + ` < td.min_participants
+	`).Scan(&ids).Error
+	if err != nil {
+		log.Printf("Go/no-go: cancellation query failed: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		report, err := CancelDeparture(db, id, NoGoReason)
+		if err != nil {
+			// ErrDepartureCancelled: an admin got there first.
+			log.Printf("Go/no-go: cancelling departure #%d failed: %v", id, err)
+			continue
+		}
+		if report.RefundFailed > 0 {
+			log.Printf("Go/no-go: departure #%d has %d refund(s) to finish manually", id, report.RefundFailed)
+		}
+	}
+}
+
+// notifyDepartureGuaranteed emails every active booking on the departure.
+func notifyDepartureGuaranteed(db *gorm.DB, tourDateID uint) int {
+	var rows []struct {
+		ID            uint      `gorm:"column:id"`
+		CustomerName  string    `gorm:"column:customer_name"`
+		CustomerEmail string    `gorm:"column:customer_email"`
+		TourTitle     string    `gorm:"column:tour_title"`
+		DateFrom      time.Time `gorm:"column:date_from"`
+		DateTo        time.Time `gorm:"column:date_to"`
+	}
+	db.Raw(`
+		SELECT b.id, b.customer_name, COALESCE(b.customer_email, '') AS customer_email,
+			t.title AS tour_title, td.date_from, td.date_to
+		FROM bookings b
+		JOIN tour_dates td ON b.tour_date_id = td.id
+		JOIN tours t ON td.tour_id = t.id
+		WHERE b.tour_date_id = ? AND b.status <> 'cancelled'
+	`, tourDateID).Scan(&rows)
+
+	sent := 0
+	for _, r := range rows {
+		if r.CustomerEmail == "" {
+			continue
+		}
+		email.NotifyDepartureGuaranteed(r.CustomerEmail, email.DepartureGuaranteed{
+			CustomerName: r.CustomerName,
+			BookingID:    r.ID,
+			TourTitle:    r.TourTitle,
+			DateFrom:     r.DateFrom,
+			DateTo:       r.DateTo,
+		})
+		sent++
+	}
+	return sent
+}
@@ -0,0 +1,401 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"tour-server/database"
+	"tour-server/touritinerary"
+	"tour-server/webhooks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type ItineraryDayRequest struct {
+	DayNumber           *int     `json:"day_number"` // create: defaults to the next day
+	Title               *string  `json:"title"`
+	Description         *string  `json:"description"`
+	OvernightLocationID *uint    `json:"overnight_location_id"` // 0 clears it
+	Meals               []string `json:"meals"`
+	Images              []string `json:"images"`
+}
+
+type ReorderItineraryRequest struct {
+	DayIDs []uint `json:"day_ids"`
+}
+
+const maxItineraryImages = 10
+
+// validate checks the fields that are present and returns an error
+// message, or "" if valid.
+func (r *ItineraryDayRequest) validate() string {
+	if r.Title != nil {
+		t := strings.TrimSpace(*r.Title)
+		if t == "" || len(t) > 255 {
+			return "title is required (max 255 characters)"
+		}
+		r.Title = &t
+	}
+	if r.DayNumber != nil && *r.DayNumber < 1 {
+		return "day_number must be >= 1"
+	}
+	for _, m := range r.Meals {
+		if !touritinerary.ValidMeal(m) {
+			return "meals may contain only breakfast, lunch, dinner"
+		}
+	}
+	if len(r.Images) > maxItineraryImages {
+		return "at most 10 images per day"
+	}
+	for _, img := range r.Images {
+		if img == "" || len(img) > 500 {
+			return "invalid image path"
+		}
+	}
+	return ""
+}
+
+// GetAdminItinerary lists a tour's itinerary days.
+func GetAdminItinerary(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		days, err := touritinerary.Load(db, uint(tourID))
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch itinerary",
+			})
+		}
+
+		return c.JSON(http.StatusOK, days)
+	}
+}
+
+// CreateItineraryDay adds a day. Inserting at an existing day_number
+// shifts that day and the ones after it down by one.
+func CreateItineraryDay(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		var req ItineraryDayRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.Title == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "title is required",
+			})
+		}
+		if msg := req.validate(); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		var exists int64
+		db.Table("tours").Where("id = ?", tourID).Count(&exists)
+		if exists == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tour not found",
+			})
+		}
+
+		tx := db.Begin()
+
+		var lastDay int
+		if err := tx.Raw("SELECT COALESCE(MAX(day_number), 0) FROM tour_itinerary_days WHERE tour_id = ?", tourID).
+			Scan(&lastDay).Error; err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create itinerary day",
+			})
+		}
+		dayNumber := lastDay + 1
+		if req.DayNumber != nil && *req.DayNumber <= lastDay {
+			dayNumber = *req.DayNumber
+			if err := tx.Exec(`
+				UPDATE tour_itinerary_days SET day_number = day_number + 1
+				WHERE tour_id = ? AND day_number >= ?
+			`, tourID, dayNumber).Error; err != nil {
+				tx.Rollback()
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to create itinerary day",
+				})
+			}
+		}
+
+		description := ""
+		if req.Description != nil {
+			description = *req.Description
+		}
+
+		var dayID uint
+		err = tx.Raw(`
+			INSERT INTO tour_itinerary_days (tour_id, day_number, title, description, overnight_location_id, meals, images)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			RETURNING id
+		`, tourID, dayNumber, *req.Title, description, locationOrNil(req.OvernightLocationID),
+			pq.StringArray(nonNil(req.Meals)), pq.StringArray(nonNil(req.Images))).Scan(&dayID).Error
+		if err != nil || dayID == 0 {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create itinerary day",
+			})
+		}
+
+		// uq_tour_itinerary_day is deferred, so a concurrent insert of the
+		// same day number only surfaces here.
+		if err := tx.Commit().Error; err != nil {
+			if database.IsUniqueViolation(err) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "The itinerary was changed concurrently, please retry",
+				})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create itinerary day",
+			})
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message":    "Itinerary day created",
+			"day_id":     dayID,
+			"day_number": dayNumber,
+		})
+	}
+}
+
+// UpdateItineraryDay edits a day's content. Use ReorderItinerary to move it.
+func UpdateItineraryDay(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+		dayID, err := strconv.Atoi(c.Param("dayId"))
+		if err != nil || dayID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID дня",
+			})
+		}
+
+		var req ItineraryDayRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.DayNumber != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Use PUT /admin/tours/:id/itinerary/order to move days",
+			})
+		}
+		if msg := req.validate(); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		updates := map[string]interface{}{}
+		if req.Title != nil {
+			updates["title"] = *req.Title
+		}
+		if req.Description != nil {
+			updates["description"] = *req.Description
+		}
+		if req.OvernightLocationID != nil {
+			updates["overnight_location_id"] = locationOrNil(req.OvernightLocationID)
+		}
+		if req.Meals != nil {
+			updates["meals"] = pq.StringArray(req.Meals)
+		}
+		if req.Images != nil {
+			updates["images"] = pq.StringArray(req.Images)
+		}
+		if len(updates) == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Nothing to update",
+			})
+		}
+		updates["updated_at"] = gorm.Expr("NOW()")
+
+		result := db.Table("tour_itinerary_days").Where("id = ? AND tour_id = ?", dayID, tourID).Updates(updates)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update itinerary day",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Itinerary day not found",
+			})
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Itinerary day updated",
+		})
+	}
+}
+
+// DeleteItineraryDay removes a day and closes the gap in the numbering.
+func DeleteItineraryDay(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+		dayID, err := strconv.Atoi(c.Param("dayId"))
+		if err != nil || dayID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID дня",
+			})
+		}
+
+		tx := db.Begin()
+
+		var dayNumber int
+		tx.Raw("SELECT day_number FROM tour_itinerary_days WHERE id = ? AND tour_id = ?", dayID, tourID).Scan(&dayNumber)
+		if dayNumber == 0 {
+			tx.Rollback()
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Itinerary day not found",
+			})
+		}
+
+		if err := tx.Exec("DELETE FROM tour_itinerary_days WHERE id = ?", dayID).Error; err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete itinerary day",
+			})
+		}
+		if err := tx.Exec(`
+			UPDATE tour_itinerary_days SET day_number = day_number - 1
+			WHERE tour_id = ? AND day_number > ?
+		`, tourID, dayNumber).Error; err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete itinerary day",
+			})
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			if database.IsUniqueViolation(err) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "The itinerary was changed concurrently, please retry",
+				})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete itinerary day",
+			})
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Itinerary day deleted",
+		})
+	}
+}
+
+// ReorderItinerary renumbers the tour's days in the order given. day_ids
+// must list every day of the tour exactly once.
+func ReorderItinerary(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		var req ReorderItineraryRequest
+		if err := c.Bind(&req); err != nil || len(req.DayIDs) == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "day_ids required",
+			})
+		}
+
+		seen := map[uint]bool{}
+		for _, id := range req.DayIDs {
+			if id == 0 || seen[id] {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "day_ids must be unique",
+				})
+			}
+			seen[id] = true
+		}
+
+		var count int64
+		db.Table("tour_itinerary_days").Where("tour_id = ?", tourID).Count(&count)
+		var matched int64
+		db.Table("tour_itinerary_days").Where("tour_id = ? AND id IN ?", tourID, req.DayIDs).Count(&matched)
+		if int(count) != len(req.DayIDs) || matched != count {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "day_ids must list every day of the tour",
+			})
+		}
+
+		// The (tour_id, day_number) constraint is deferred, so the
+		// intermediate duplicates are fine until commit.
+		tx := db.Begin()
+		for i, id := range req.DayIDs {
+			if err := tx.Exec("UPDATE tour_itinerary_days SET day_number = ?, updated_at = NOW() WHERE id = ?", i+1, id).Error; err != nil {
+				tx.Rollback()
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to reorder itinerary",
+				})
+			}
+		}
+		if err := tx.Commit().Error; err != nil {
+			if database.IsUniqueViolation(err) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "The itinerary was changed concurrently, please retry",
+				})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to reorder itinerary",
+			})
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Itinerary reordered",
+		})
+	}
+}
+
+func locationOrNil(id *uint) interface{} {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return *id
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestItineraryDayRequestValidate(t *testing.T) {
+	title := "Львів"
+	dayNumber := 2
+	ok := ItineraryDayRequest{Title: &title, DayNumber: &dayNumber, Meals: []string{"breakfast", "dinner"}, Images: []string{"/img/lviv.jpg"}}
+	if msg := ok.validate(); msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+
+	blank := "   "
+	zero := 0
+	bad := []ItineraryDayRequest{
+		{Title: &blank},
+		{DayNumber: &zero},
+		{Meals: []string{"brunch"}},
+		{Images: make([]string, 11)},
+		{Images: []string{""}},
+		{Images: []string{strings.Repeat("a", 501)}},
+	}
+	for i, r := range bad {
+		if msg := r.validate(); msg == "" {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+}
+
+func TestCreateItineraryDay_MissingTitle(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/tours/1/itinerary", strings.NewReader(`{"meals": ["lunch"]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := CreateItineraryDay(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestUpdateItineraryDay_RejectsDayNumber(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/tours/1/itinerary/5", strings.NewReader(`{"day_number": 1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "dayId")
+	c.SetParamValues("1", "5")
+
+	handler := UpdateItineraryDay(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestReorderItinerary_DuplicateIDs(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/tours/1/itinerary/order", strings.NewReader(`{"day_ids": [3, 4, 3]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := ReorderItinerary(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"tour-server/webhooks"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -35,6 +36,7 @@ type CreateTourRequest struct {
 	StatusID            uint            `json:"status_id"`
 	DetailedDescription string          `json:"detailed_description"`
 	TotalSeats          int             `json:"total_seats"`
+	TransferCutoffHours *int            `json:"transfer_cutoff_hours"`
 	CardImage           string          `json:"card_image"`
 	GalleryImages       []string        `json:"gallery_images"`
 	Dates               []TourDateInput `json:"dates"`
@@ -48,6 +50,7 @@ type UpdateTourRequest struct {
 	StatusID            *uint            `json:"status_id"`
 	DetailedDescription *string          `json:"detailed_description"`
 	TotalSeats          *int             `json:"total_seats"`
+	TransferCutoffHours *int             `json:"transfer_cutoff_hours"`
 	CardImage           *string          `json:"card_image"`
 	GalleryImages       []string         `json:"gallery_images"`
 	Dates               []TourDateInput  `json:"dates"`
@@ -119,16 +122,25 @@ func CreateTour(db *gorm.DB) echo.HandlerFunc {
 		if req.CallToAction == "" {
 			req.CallToAction = "Забронювати"
 		}
+		transferCutoff := 72
+		if req.TransferCutoffHours != nil {
+			if *req.TransferCutoffHours < 0 {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "transfer_cutoff_hours must be >= 0",
+				})
+			}
+			transferCutoff = *req.TransferCutoffHours
+		}
 
 		tx := db.Begin()
 
 		// 1. Create tour
 		var tourID uint
 		err := tx.Raw(`
-			INSERT INTO tours (title, description, call_to_action, price, status_id, detailed_description, total_seats, rating)
-			VALUES (?, ?, ?, ?, ?, ?, ?, 0.0)
+			INSERT INTO tours (title, description, call_to_action, price, status_id, detailed_description, total_seats, transfer_cutoff_hours, rating)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0.0)
 			RETURNING id
-		`, req.Title, req.Description, req.CallToAction, req.Price, req.StatusID, req.DetailedDescription, req.TotalSeats).
+		`, req.Title, req.Description, req.CallToAction, req.Price, req.StatusID, req.DetailedDescription, req.TotalSeats, transferCutoff).
 			Scan(&tourID).Error
 
 		if err != nil {
@@ -200,6 +212,22 @@ func UpdateTour(db *gorm.DB) echo.HandlerFunc {
 			})
 		}
 
+		// Replacing all dates would orphan existing bookings; booked tours
+		// are edited one departure at a time via /admin/tours/:id/dates.
+		if req.Dates != nil {
+			var booked int64
+			db.Table("bookings").
+				Joins("JOIN tour_dates ON bookings.tour_date_id = tour_dates.id").
+				Where("tour_dates.tour_id = ?", tourID).
+				Count(&booked)
+			if booked > 0 {
+				return c.JSON(http.StatusConflict, map[string]interface{}{
+					"error":          "Tour has bookings; edit departures via /admin/tours/:id/dates",
+					"bookings_count": booked,
+				})
+			}
+		}
+
 		tx := db.Begin()
 
 		// 1. Update tour fields
@@ -225,6 +253,15 @@ func UpdateTour(db *gorm.DB) echo.HandlerFunc {
 		if req.TotalSeats != nil {
 			updates["total_seats"] = *req.TotalSeats
 		}
+		if req.TransferCutoffHours != nil {
+			if *req.TransferCutoffHours < 0 {
+				tx.Rollback()
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "transfer_cutoff_hours must be >= 0",
+				})
+			}
+			updates["transfer_cutoff_hours"] = *req.TransferCutoffHours
+		}
 
 		if len(updates) > 0 {
 			result := tx.Table("tours").Where("id = ?", tourID).Updates(updates)
@@ -292,6 +329,10 @@ func UpdateTour(db *gorm.DB) echo.HandlerFunc {
 
 		tx.Commit()
 
+		if id, err := strconv.Atoi(tourID); err == nil {
+			webhooks.PublishTour(db, uint(id))
+		}
+
 		return c.JSON(http.StatusOK, map[string]string{
 			"message": "Tour updated",
 		})
@@ -317,6 +358,10 @@ func DeleteTour(db *gorm.DB) echo.HandlerFunc {
 			})
 		}
 
+		if id, err := strconv.Atoi(tourID); err == nil {
+			webhooks.PublishTour(db, uint(id))
+		}
+
 		return c.JSON(http.StatusOK, map[string]string{
 			"message": "Tour deactivated",
 		})
@@ -338,6 +383,7 @@ func GetAdminTourDetail(db *gorm.DB) echo.HandlerFunc {
 			StatusName          string  `json:"status_name"`
 			DetailedDescription string  `json:"detailed_description"`
 			TotalSeats          int     `json:"total_seats"`
+			TransferCutoffHours int     `json:"transfer_cutoff_hours"`
 		}
 
 		err := db.Table("tours").
@@ -396,27 +442,4 @@ func GetAdminTourDetail(db *gorm.DB) echo.HandlerFunc {
 			"bookings_count": bookingsCount,
 		})
 	}
-}
-
-func GetLocations(db *gorm.DB) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		var locations []struct {
-			ID      uint   `json:"id"`
-			Name    string `json:"name"`
-			Country string `json:"country"`
-		}
-
-		err := db.Table("locations").
-			Select("id, name, country").
-			Order("country, name").
-			Find(&locations).Error
-
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to fetch locations",
-			})
-		}
-
-		return c.JSON(http.StatusOK, locations)
-	}
 }
\ No newline at end of file
@@ -0,0 +1,643 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tour-server/email"
+	"tour-server/webhooks"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// AdminTourDate is one departure as shown in the admin tour editor.
+type AdminTourDate struct {
+	ID               uint       `json:"id" gorm:"column:id"`
+	FromLocationID   uint       `json:"from_location_id" gorm:"column:from_location_id"`
+	FromLocationName string     `json:"from_location_name" gorm:"column:from_location_name"`
+	ToLocationID     uint       `json:"to_location_id" gorm:"column:to_location_id"`
+	ToLocationName   string     `json:"to_location_name" gorm:"column:to_location_name"`
+	DateFrom         time.Time  `json:"date_from" gorm:"column:date_from"`
+	DateTo           time.Time  `json:"date_to" gorm:"column:date_to"`
+	Capacity         int        `json:"capacity" gorm:"column:capacity"`
+	BookedSeats      int        `json:"booked_seats" gorm:"column:booked_seats"`
+	AvailableSeats   int        `json:"available_seats" gorm:"column:available_seats"`
+	ActiveBookings   int        `json:"active_bookings" gorm:"column:active_bookings"`
+	IsRetired        bool       `json:"is_retired" gorm:"column:is_retired"`
+	CancelledAt      *time.Time `json:"cancelled_at" gorm:"column:cancelled_at"`
+	// Go/no-go: see migration_min_participants.sql and StartGoNoGoJob.
+	MinParticipants    *int `json:"min_participants" gorm:"column:min_participants"`
+	DecisionDaysBefore int  `json:"decision_days_before" gorm:"column:decision_days_before"`
+	ConfirmedSeats     int  `json:"confirmed_seats" gorm:"column:confirmed_seats"`
+	IsGuaranteed       bool `json:"is_guaranteed" gorm:"column:is_guaranteed"`
+	// SeriesID is set for dates generated from a departure series.
+	SeriesID *uint `json:"series_id" gorm:"column:series_id"`
+}
+
+type CreateTourDateRequest struct {
+	FromLocationID uint   `json:"from_location_id"`
+	ToLocationID   uint   `json:"to_location_id"`
+	DateFrom       string `json:"date_from"`
+	DateTo         string `json:"date_to"`
+	TotalSeats     *int   `json:"total_seats"`
+	// MinParticipants is optional; DecisionDaysBefore defaults to 7.
+	MinParticipants    *int `json:"min_participants"`
+	DecisionDaysBefore *int `json:"decision_days_before"`
+}
+
+type UpdateTourDateRequest struct {
+	FromLocationID *uint   `json:"from_location_id"`
+	ToLocationID   *uint   `json:"to_location_id"`
+	DateFrom       *string `json:"date_from"`
+	DateTo         *string `json:"date_to"`
+	TotalSeats     *int    `json:"total_seats"`
+	IsRetired      *bool   `json:"is_retired"`
+	// MinParticipants 0 removes the minimum.
+	MinParticipants    *int `json:"min_participants"`
+	DecisionDaysBefore *int `json:"decision_days_before"`
+	// Confirm must be true to edit a departure that already has bookings.
+	Confirm bool `json:"confirm"`
+	// Reason is shown to customers when retiring a booked departure.
+	Reason string `json:"reason"`
+}
+
+// adminTourDatesQuery selects departures with live booking figures.
+// Capacity falls back to the tour's total_seats for dates created before
+// tour_dates.total_seats existed.
+const adminTourDatesQuery = `
+	SELECT td.id, td.from_location_id, fl.name AS from_location_name,
+		td.to_location_id, tl.name AS to_location_name,
+		td.date_from, td.date_to, td.is_retired, td.cancelled_at,
+		td.min_participants, td.decision_days_before, td.is_guaranteed, td.series_id,
+		COALESCE(td.total_seats, t.total_seats) AS capacity,
+		COALESCE(ts.available_seats, 0) AS available_seats,
+		COALESCE(SUM(b.seats) FILTER (WHERE b.status <> 'cancelled'), 0) AS booked_seats,
+		COUNT(b.id) FILTER (WHERE b.status <> 'cancelled') AS active_bookings,
+		COALESCE(SUM(b.seats) FILTER (WHERE b.status = 'confirmed'), 0) AS confirmed_seats
+	FROM tour_dates td
+	JOIN tours t ON td.tour_id = t.id
+	JOIN locations fl ON td.from_location_id = fl.id
+	JOIN locations tl ON td.to_location_id = tl.id
+	LEFT JOIN tour_seats ts ON ts.tour_date_id = td.id
+	LEFT JOIN bookings b ON b.tour_date_id = td.id
+`
+
+const adminTourDatesGroupBy = `
+	GROUP BY td.id, fl.name, tl.name, t.total_seats, ts.available_seats
+`
+
+// GetAdminTourDates lists every departure of a tour, retired ones included.
+func GetAdminTourDates(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		dates := []AdminTourDate{}
+		err = db.Raw(adminTourDatesQuery+`WHERE td.tour_id = ?`+adminTourDatesGroupBy+`ORDER BY td.date_from`, tourID).
+			Scan(&dates).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch tour dates",
+			})
+		}
+
+		return c.JSON(http.StatusOK, dates)
+	}
+}
+
+// CreateTourDate adds a single departure to an existing tour.
+func CreateTourDate(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		var req CreateTourDateRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+
+		if req.FromLocationID == 0 || req.ToLocationID == 0 || req.DateFrom == "" || req.DateTo == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "from_location_id, to_location_id, date_from and date_to are required",
+			})
+		}
+		dateFrom, dateTo, ok := parseDepartureDates(req.DateFrom, req.DateTo)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid dates: use YYYY-MM-DD and date_to must not be before date_from",
+			})
+		}
+		if req.TotalSeats != nil && *req.TotalSeats <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "total_seats must be > 0",
+			})
+		}
+
+		var tourSeats int
+		db.Raw("SELECT total_seats FROM tours WHERE id = ?", tourID).Scan(&tourSeats)
+		if tourSeats == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tour not found",
+			})
+		}
+		capacity := tourSeats
+		if req.TotalSeats != nil {
+			capacity = *req.TotalSeats
+		}
+		if msg := validateGoNoGo(req.MinParticipants, req.DecisionDaysBefore, capacity); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+		var minParticipants interface{}
+		if req.MinParticipants != nil && *req.MinParticipants > 0 {
+			minParticipants = *req.MinParticipants
+		}
+		decisionDays := 7
+		if req.DecisionDaysBefore != nil {
+			decisionDays = *req.DecisionDaysBefore
+		}
+
+		tx := db.Begin()
+
+		var tourDateID uint
+		err = tx.Raw(`
+			INSERT INTO tour_dates (tour_id, from_location_id, to_location_id, date_from, date_to,
+				total_seats, min_participants, decision_days_before)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			RETURNING id
+		`, tourID, req.FromLocationID, req.ToLocationID, dateFrom, dateTo,
+			capacity, minParticipants, decisionDays).Scan(&tourDateID).Error
+		if err != nil || tourDateID == 0 {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create tour date",
+			})
+		}
+
+		if err := tx.Exec("INSERT INTO tour_seats (tour_date_id, available_seats) VALUES (?, ?)", tourDateID, capacity).Error; err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create tour seats",
+			})
+		}
+
+		tx.Commit()
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message":      "Tour date created",
+			"tour_date_id": tourDateID,
+		})
+	}
+}
+
+// UpdateTourDate edits one departure in place, so bookings keep pointing
+// at it. If the departure has active bookings the request must carry
+// "confirm": true; without it the handler answers 409 with the number of
+// affected bookings. Capacity can't drop below the seats already booked.
+// Customers are emailed when the dates or route change. Retiring a booked
+// departure cancels it through CancelDeparture, so its customers are
+// refunded and notified just like with the cancel endpoint.
+func UpdateTourDate(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+		dateID, err := strconv.Atoi(c.Param("dateId"))
+		if err != nil || dateID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID дати",
+			})
+		}
+
+		var req UpdateTourDateRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.TotalSeats != nil && *req.TotalSeats <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "total_seats must be > 0",
+			})
+		}
+		if (req.FromLocationID != nil && *req.FromLocationID == 0) || (req.ToLocationID != nil && *req.ToLocationID == 0) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid location",
+			})
+		}
+
+		var current AdminTourDate
+		db.Raw(adminTourDatesQuery+`WHERE td.id = ? AND td.tour_id = ?`+adminTourDatesGroupBy, dateID, tourID).
+			Scan(&current)
+		if current.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tour date not found",
+			})
+		}
+
+		newFrom, newTo := current.DateFrom, current.DateTo
+		if req.DateFrom != nil || req.DateTo != nil {
+			from, to := current.DateFrom.Format("2006-01-02"), current.DateTo.Format("2006-01-02")
+			if req.DateFrom != nil {
+				from = *req.DateFrom
+			}
+			if req.DateTo != nil {
+				to = *req.DateTo
+			}
+			var ok bool
+			newFrom, newTo, ok = parseDepartureDates(from, to)
+			if !ok {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Invalid dates: use YYYY-MM-DD and date_to must not be before date_from",
+				})
+			}
+		}
+
+		if current.CancelledAt != nil {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "Departure was cancelled and can no longer be edited",
+			})
+		}
+
+		capacity := current.Capacity
+		if req.TotalSeats != nil {
+			capacity = *req.TotalSeats
+		}
+		if msg := validateGoNoGo(req.MinParticipants, req.DecisionDaysBefore, capacity); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		// Go/no-go settings don't change anything for booked customers.
+		customerVisible := req.FromLocationID != nil || req.ToLocationID != nil ||
+			req.DateFrom != nil || req.DateTo != nil || req.TotalSeats != nil || req.IsRetired != nil
+		if current.ActiveBookings > 0 && customerVisible && !req.Confirm {
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error":                 "Ця дата вже має бронювання. Підтвердіть зміни — клієнти отримають повідомлення",
+				"requires_confirmation": true,
+				"affected_bookings":     current.ActiveBookings,
+			})
+		}
+
+		if req.TotalSeats != nil && *req.TotalSeats < current.BookedSeats {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":        "total_seats cannot be less than seats already booked",
+				"booked_seats": current.BookedSeats,
+			})
+		}
+
+		updates := map[string]interface{}{}
+		if req.FromLocationID != nil {
+			updates["from_location_id"] = *req.FromLocationID
+		}
+		if req.ToLocationID != nil {
+			updates["to_location_id"] = *req.ToLocationID
+		}
+		if req.DateFrom != nil || req.DateTo != nil {
+			updates["date_from"] = newFrom
+			updates["date_to"] = newTo
+		}
+		if req.TotalSeats != nil {
+			updates["total_seats"] = *req.TotalSeats
+		}
+		if req.IsRetired != nil {
+			updates["is_retired"] = *req.IsRetired
+		}
+		if req.MinParticipants != nil {
+			if *req.MinParticipants == 0 {
+				updates["min_participants"] = nil
+			} else {
+				updates["min_participants"] = *req.MinParticipants
+			}
+		}
+		if req.DecisionDaysBefore != nil {
+			updates["decision_days_before"] = *req.DecisionDaysBefore
+		}
+		if len(updates) == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Nothing to update",
+			})
+		}
+		if len(strings.TrimSpace(req.Reason)) > 500 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "reason must be at most 500 characters",
+			})
+		}
+
+		// A booked departure that is retired won't run — CancelDeparture
+		// sets is_retired itself and takes care of the customers.
+		cancel := req.IsRetired != nil && *req.IsRetired && !current.IsRetired && current.ActiveBookings > 0
+		if cancel {
+			delete(updates, "is_retired")
+		}
+
+		tx := db.Begin()
+		defer tx.Rollback()
+
+		if len(updates) > 0 {
+			if err := tx.Table("tour_dates").Where("id = ?", dateID).Updates(updates).Error; err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to update tour date",
+				})
+			}
+		}
+		if err := RecalculateSeats(tx, uint(dateID)); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to recalculate seats",
+			})
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update tour date",
+			})
+		}
+
+		var updated AdminTourDate
+		db.Raw(adminTourDatesQuery+`WHERE td.id = ?`+adminTourDatesGroupBy, dateID).Scan(&updated)
+
+		notified := 0
+		if current.ActiveBookings > 0 && departureChanged(current, updated) {
+			notified = notifyDepartureChanged(db, current, updated)
+		}
+
+		var cancellation *DepartureCancellationReport
+		if cancel {
+			report, err := CancelDeparture(db, uint(dateID), strings.TrimSpace(req.Reason))
+			if err != nil && !errors.Is(err, ErrDepartureCancelled) {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to cancel bookings of the retired departure",
+				})
+			}
+			cancellation = &report
+			notified += report.Notified
+			db.Raw(adminTourDatesQuery+`WHERE td.id = ?`+adminTourDatesGroupBy, dateID).Scan(&updated)
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message":            "Tour date updated",
+			"tour_date":          updated,
+			"customers_notified": notified,
+			"cancellation":       cancellation,
+		})
+	}
+}
+
+// DeleteTourDate removes a departure that was never booked. Departures
+// whose bookings are all cancelled are retired instead — they disappear
+// from the public site and take no new bookings, but the bookings keep
+// their tour_date_id. A departure with active bookings needs
+// ?confirm=true (and optionally &reason=...) and is then cancelled through
+// CancelDeparture, so its customers are refunded and notified; without
+// confirm the handler answers 409 with the number of affected bookings.
+func DeleteTourDate(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+		dateID, err := strconv.Atoi(c.Param("dateId"))
+		if err != nil || dateID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID дати",
+			})
+		}
+		reason := strings.TrimSpace(c.QueryParam("reason"))
+		if len(reason) > 500 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "reason must be at most 500 characters",
+			})
+		}
+
+		var exists int64
+		db.Table("tour_dates").Where("id = ? AND tour_id = ?", dateID, tourID).Count(&exists)
+		if exists == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tour date not found",
+			})
+		}
+
+		var counts struct {
+			Bookings       int64 `gorm:"column:bookings"`
+			ActiveBookings int64 `gorm:"column:active_bookings"`
+		}
+		if err := db.Raw(`
+			SELECT COUNT(*) AS bookings,
+				COUNT(*) FILTER (WHERE status <> 'cancelled') AS active_bookings
+			FROM bookings WHERE tour_date_id = ?
+		`, dateID).Scan(&counts).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch bookings",
+			})
+		}
+
+		if counts.ActiveBookings > 0 {
+			if c.QueryParam("confirm") != "true" {
+				return c.JSON(http.StatusConflict, map[string]interface{}{
+					"error":                 "Ця дата вже має бронювання. Підтвердіть скасування — клієнти отримають повідомлення",
+					"requires_confirmation": true,
+					"affected_bookings":     counts.ActiveBookings,
+				})
+			}
+			report, err := CancelDeparture(db, uint(dateID), reason)
+			switch {
+			case errors.Is(err, ErrDepartureCancelled):
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": err.Error(),
+				})
+			case err != nil:
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to cancel tour date",
+				})
+			}
+			return c.JSON(http.StatusOK, map[string]interface{}{
+				"message":      "Tour date has bookings and was cancelled instead of deleted",
+				"retired":      true,
+				"cancellation": report,
+			})
+		}
+
+		if counts.Bookings > 0 {
+			if err := db.Exec("UPDATE tour_dates SET is_retired = TRUE WHERE id = ?", dateID).Error; err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to retire tour date",
+				})
+			}
+			webhooks.PublishTour(db, uint(tourID))
+			return c.JSON(http.StatusOK, map[string]interface{}{
+				"message": "Tour date has bookings and was retired instead of deleted",
+				"retired": true,
+			})
+		}
+
+		tx := db.Begin()
+		if err := tx.Exec("DELETE FROM tour_seats WHERE tour_date_id = ?", dateID).Error; err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete tour seats",
+			})
+		}
+		if err := tx.Exec("DELETE FROM tour_dates WHERE id = ?", dateID).Error; err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete tour date",
+			})
+		}
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete tour date",
+			})
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message": "Tour date deleted",
+			"retired": false,
+		})
+	}
+}
+
+// RecalculateSeats sets tour_seats.available_seats for a departure from its
+// capacity minus the seats held by non-cancelled bookings, creating the
+// tour_seats row if it is missing, and drops the guaranteed mark if the
+// departure no longer meets min_participants (the go/no-go job sets it
+// again, with the customer emails, once it does). Call it inside a
+// transaction: the date and seat rows are locked first, so a booking whose
+// trigger is still adjusting tour_seats finishes before the seats are
+// counted.
+func RecalculateSeats(db *gorm.DB, tourDateID uint) error {
+	var locked []uint
+	if err := db.Raw("SELECT id FROM tour_dates WHERE id = ? FOR UPDATE", tourDateID).Scan(&locked).Error; err != nil {
+		return err
+	}
+	if err := db.Raw("SELECT tour_date_id FROM tour_seats WHERE tour_date_id = ? FOR UPDATE", tourDateID).Scan(&locked).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		UPDATE tour_dates td
+		SET is_guaranteed = FALSE, guaranteed_at = NULL
+		WHERE td.id = ? AND `+lostGuaranteeSQL, tourDateID).Error; err != nil {
+		return err
+	}
+
+	const available = `
+		SELECT GREATEST(
+			COALESCE(td.total_seats, t.total_seats) - COALESCE((
+				SELECT SUM(b.seats) FROM bookings b
+				WHERE b.tour_date_id = td.id AND b.status <> 'cancelled'
+			), 0), 0)
+		FROM tour_dates td
+		JOIN tours t ON td.tour_id = t.id
+		WHERE td.id = ?`
+
+	result := db.Exec(`UPDATE tour_seats SET available_seats = (`+available+`) WHERE tour_date_id = ?`, tourDateID, tourDateID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	return db.Exec(`INSERT INTO tour_seats (tour_date_id, available_seats) VALUES (?, (`+available+`))`, tourDateID, tourDateID).Error
+}
+
+// validateGoNoGo checks the minimum-participants settings against the
+// departure's capacity and returns an error message, or "" if valid.
+func validateGoNoGo(minParticipants, decisionDays *int, capacity int) string {
+	if minParticipants != nil && (*minParticipants < 0 || *minParticipants > capacity) {
+		return "min_participants must be between 0 and the departure capacity"
+	}
+	if decisionDays != nil && (*decisionDays < 0 || *decisionDays > 90) {
+		return "decision_days_before must be between 0 and 90"
+	}
+	return ""
+}
+
+// parseDepartureDates accepts "2006-01-02" or RFC 3339 and checks order.
+func parseDepartureDates(from, to string) (time.Time, time.Time, bool) {
+	parse := func(s string) (time.Time, error) {
+		if t, err := time.Parse("2006-01-02", s); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.RFC3339, s)
+	}
+	dateFrom, err := parse(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	dateTo, err := parse(to)
+	if err != nil || dateTo.Before(dateFrom) {
+		return time.Time{}, time.Time{}, false
+	}
+	return dateFrom, dateTo, true
+}
+
+func departureChanged(old, updated AdminTourDate) bool {
+	return !old.DateFrom.Equal(updated.DateFrom) || !old.DateTo.Equal(updated.DateTo) ||
+		old.FromLocationID != updated.FromLocationID || old.ToLocationID != updated.ToLocationID
+}
+
+// notifyDepartureChanged emails every active booking on the departure and
+// returns how many emails were queued.
+func notifyDepartureChanged(db *gorm.DB, old, updated AdminTourDate) int {
+	var rows []struct {
+		ID            uint   `gorm:"column:id"`
+		CustomerName  string `gorm:"column:customer_name"`
+		CustomerEmail string `gorm:"column:customer_email"`
+		TourTitle     string `gorm:"column:tour_title"`
+	}
+	db.Raw(`
+		SELECT b.id, b.customer_name, COALESCE(b.customer_email, '') AS customer_email, t.title AS tour_title
+		FROM bookings b
+		JOIN tour_dates td ON b.tour_date_id = td.id
+		JOIN tours t ON td.tour_id = t.id
+		WHERE b.tour_date_id = ? AND b.status <> 'cancelled'
+	`, updated.ID).Scan(&rows)
+
+	sent := 0
+	for _, r := range rows {
+		if r.CustomerEmail == "" {
+			continue
+		}
+		email.NotifyDepartureChanged(r.CustomerEmail, email.DepartureChange{
+			CustomerName: r.CustomerName,
+			BookingID:    r.ID,
+			TourTitle:    r.TourTitle,
+			OldDateFrom:  old.DateFrom,
+			OldDateTo:    old.DateTo,
+			NewDateFrom:  updated.DateFrom,
+			NewDateTo:    updated.DateTo,
+			OldRoute:     old.FromLocationName + " → " + old.ToLocationName,
+			NewRoute:     updated.FromLocationName + " → " + updated.ToLocationName,
+		})
+		sent++
+	}
+	log.Printf("Departure #%d changed: %d customer(s) notified", updated.ID, sent)
+	return sent
+}
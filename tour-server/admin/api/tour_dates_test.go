@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestGetAdminTourDates_InvalidID(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/tours/abc/dates", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+
+	handler := GetAdminTourDates(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestCreateTourDate_MissingFields(t *testing.T) {
+	e := echo.New()
+
+	body := `{"from_location_id": 1, "date_from": "2026-07-01"}`
+	req := httptest.NewRequest(http.MethodPost, "/admin/tours/1/dates", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := CreateTourDate(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestCreateTourDate_DateToBeforeDateFrom(t *testing.T) {
+	e := echo.New()
+
+	body := `{"from_location_id": 1, "to_location_id": 2, "date_from": "2026-07-10", "date_to": "2026-07-01"}`
+	req := httptest.NewRequest(http.MethodPost, "/admin/tours/1/dates", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := CreateTourDate(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+
+	var resp map[string]string
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp["error"] == "" {
+		t.Error("expected error message for invalid dates")
+	}
+}
+
+func TestUpdateTourDate_InvalidDateID(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/tours/1/dates/x", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "dateId")
+	c.SetParamValues("1", "x")
+
+	handler := UpdateTourDate(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestUpdateTourDate_NonPositiveSeats(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/tours/1/dates/2", strings.NewReader(`{"total_seats": 0}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "dateId")
+	c.SetParamValues("1", "2")
+
+	handler := UpdateTourDate(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestDeleteTourDate_InvalidID(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/tours/0/dates/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "dateId")
+	c.SetParamValues("0", "1")
+
+	handler := DeleteTourDate(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestParseDepartureDates(t *testing.T) {
+	cases := []struct {
+		from, to string
+		ok       bool
+	}{
+		{"2026-07-01", "2026-07-08", true},
+		{"2026-07-01", "2026-07-01", true},
+		{"2026-07-01T00:00:00Z", "2026-07-08T00:00:00Z", true},
+		{"2026-07-08", "2026-07-01", false},
+		{"01.07.2026", "2026-07-08", false},
+	}
+	for _, tc := range cases {
+		if _, _, ok := parseDepartureDates(tc.from, tc.to); ok != tc.ok {
+			t.Errorf("parseDepartureDates(%q, %q) ok = %v, want %v", tc.from, tc.to, ok, tc.ok)
+		}
+	}
+}
+
+func TestValidateGoNoGo(t *testing.T) {
+	cases := []struct {
+		min, days *int
+		ok        bool
+	}{
+		{nil, nil, true},
+		{intPtr(0), nil, true},
+		{intPtr(6), intPtr(14), true},
+		{intPtr(-1), nil, false},
+		{intPtr(21), nil, false},
+		{nil, intPtr(-1), false},
+		{nil, intPtr(91), false},
+	}
+	for i, tc := range cases {
+		if got := validateGoNoGo(tc.min, tc.days, 20) == ""; got != tc.ok {
+			t.Errorf("case %d: valid = %v, want %v", i, got, tc.ok)
+		}
+	}
+}
+
+func intPtr(v int) *int { return &v }
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 	"tour-server/email"
+	"tour-server/notify"
+	"tour-server/touritinerary"
+	"tour-server/webhooks"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -14,6 +18,11 @@ type UpdateStatusRequest struct {
 	Status string `json:"status"`
 }
 
+var (
+	ErrBookingNotFound = errors.New("Booking not found")
+	ErrNotEnoughSeats  = errors.New("Not enough available seats to reactivate booking")
+)
+
 func UpdateBookingStatus(db *gorm.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		idParam := c.Param("id")
@@ -37,129 +46,162 @@ func UpdateBookingStatus(db *gorm.DB) echo.HandlerFunc {
 			})
 		}
 
-		tx := db.Begin()
-		if tx.Error != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to start transaction",
-			})
-		}
-
-		var booking struct {
-			ID            uint    `gorm:"column:id"`
-			TourDateID    uint    `gorm:"column:tour_date_id"`
-			Seats         uint    `gorm:"column:seats"`
-			Status        string  `gorm:"column:status"`
-			CustomerName  string  `gorm:"column:customer_name"`
-			CustomerEmail string  `gorm:"column:customer_email"`
-			TotalPrice    float64 `gorm:"column:total_price"`
-		}
-		if err := tx.Raw(
-			`SELECT id, tour_date_id, seats, status, customer_name, customer_email, total_price
-			 FROM bookings WHERE id = ?`,
-			bookingIDInt,
-		).Scan(&booking).Error; err != nil || booking.ID == 0 {
-			tx.Rollback()
+		changed, err := ApplyBookingStatus(db, uint(bookingIDInt), req.Status)
+		switch {
+		case errors.Is(err, ErrBookingNotFound):
 			return c.JSON(http.StatusNotFound, map[string]string{
 				"error": "Booking not found",
 			})
+		case errors.Is(err, ErrNotEnoughSeats):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		case err != nil:
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": err.Error(),
+			})
 		}
 
-		prevStatus := booking.Status
-		newStatus := req.Status
-
-		if prevStatus == newStatus {
-			tx.Rollback()
+		if !changed {
 			return c.JSON(http.StatusOK, map[string]string{
 				"message": "Booking status unchanged",
 			})
 		}
 
-		switch {
-		case newStatus == "cancelled" && (prevStatus == "pending" || prevStatus == "confirmed"):
-			if err := tx.Exec(
-				"UPDATE tour_seats SET available_seats = available_seats + ? WHERE tour_date_id = ?",
-				booking.Seats, booking.TourDateID,
-			).Error; err != nil {
-				tx.Rollback()
-				return c.JSON(http.StatusInternalServerError, map[string]string{
-					"error": "Failed to restore seats",
-				})
-			}
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Booking status updated",
+		})
+	}
+}
 
-		case prevStatus == "cancelled" && (newStatus == "pending" || newStatus == "confirmed"):
-			var availableSeats uint
-			tx.Raw(
-				"SELECT available_seats FROM tour_seats WHERE tour_date_id = ?",
-				booking.TourDateID,
-			).Scan(&availableSeats)
-
-			if availableSeats < booking.Seats {
-				tx.Rollback()
-				return c.JSON(http.StatusBadRequest, map[string]string{
-					"error": "Not enough available seats to reactivate booking",
-				})
-			}
+// ApplyBookingStatus moves a booking to newStatus: it returns seats to (or
+// takes them from) tour_seats when crossing the cancelled boundary and
+// notifies the customer. Shared by the admin panel and the staff Telegram
+// bot so both follow the same rules. changed is false when the booking
+// already had newStatus.
+func ApplyBookingStatus(db *gorm.DB, bookingID uint, newStatus string) (bool, error) {
+	return ApplyBookingStatusFrom(db, bookingID, "", newStatus)
+}
 
-			if err := tx.Exec(
-				"UPDATE tour_seats SET available_seats = available_seats - ? WHERE tour_date_id = ?",
-				booking.Seats, booking.TourDateID,
-			).Error; err != nil {
-				tx.Rollback()
-				return c.JSON(http.StatusInternalServerError, map[string]string{
-					"error": "Failed to reserve seats",
-				})
-			}
-		}
+// ApplyBookingStatusFrom is ApplyBookingStatus that only acts while the
+// booking still has fromStatus ("" for any). The booking row is locked
+// for the whole change, so when two callers race only the first one
+// changes it and notifies the customer; the other gets changed=false.
+func ApplyBookingStatusFrom(db *gorm.DB, bookingID uint, fromStatus, newStatus string) (bool, error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return false, errors.New("Failed to start transaction")
+	}
+
+	var booking struct {
+		ID            uint    `gorm:"column:id"`
+		TourDateID    uint    `gorm:"column:tour_date_id"`
+		Seats         uint    `gorm:"column:seats"`
+		Status        string  `gorm:"column:status"`
+		CustomerName  string  `gorm:"column:customer_name"`
+		CustomerEmail string  `gorm:"column:customer_email"`
+		TotalPrice    float64 `gorm:"column:total_price"`
+	}
+	if err := tx.Raw(
+		`SELECT id, tour_date_id, seats, status, customer_name, customer_email, total_price
+		 FROM bookings WHERE id = ? FOR UPDATE`,
+		bookingID,
+	).Scan(&booking).Error; err != nil || booking.ID == 0 {
+		tx.Rollback()
+		return false, ErrBookingNotFound
+	}
+
+	prevStatus := booking.Status
+
+	if prevStatus == newStatus || (fromStatus != "" && prevStatus != fromStatus) {
+		tx.Rollback()
+		return false, nil
+	}
 
-		result := tx.Exec(
-			"UPDATE bookings SET status = ? WHERE id = ?",
-			newStatus, bookingIDInt,
-		)
-		if result.Error != nil {
+	switch {
+	case newStatus == "cancelled" && (prevStatus == "pending" || prevStatus == "confirmed"):
+		if err := tx.Exec(
+			"UPDATE tour_seats SET available_seats = available_seats + ? WHERE tour_date_id = ?",
+			booking.Seats, booking.TourDateID,
+		).Error; err != nil {
 			tx.Rollback()
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to update booking status",
-			})
+			return false, errors.New("Failed to restore seats")
 		}
-		if result.RowsAffected == 0 {
+
+	case prevStatus == "cancelled" && (newStatus == "pending" || newStatus == "confirmed"):
+		var availableSeats uint
+		tx.Raw(
+			"SELECT available_seats FROM tour_seats WHERE tour_date_id = ? FOR UPDATE",
+			booking.TourDateID,
+		).Scan(&availableSeats)
+
+		if availableSeats < booking.Seats {
 			tx.Rollback()
-			return c.JSON(http.StatusNotFound, map[string]string{
-				"error": "Booking not found",
-			})
+			return false, ErrNotEnoughSeats
 		}
 
-		if err := tx.Commit().Error; err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to commit status update",
-			})
+		if err := tx.Exec(
+			"UPDATE tour_seats SET available_seats = available_seats - ? WHERE tour_date_id = ?",
+			booking.Seats, booking.TourDateID,
+		).Error; err != nil {
+			tx.Rollback()
+			return false, errors.New("Failed to reserve seats")
 		}
+	}
 
-		if booking.CustomerEmail != "" {
-			tourTitle := getTourTitleByBooking(db, booking.ID)
-			notif := email.BookingNotification{
-				CustomerName: booking.CustomerName,
-				TourTitle:    tourTitle,
-				Seats:        int(booking.Seats),
-				TotalPrice:   booking.TotalPrice,
-				BookingID:    booking.ID,
-				Status:       newStatus,
-			}
+	result := tx.Exec(
+		"UPDATE bookings SET status = ? WHERE id = ? AND status = ?",
+		newStatus, bookingID, prevStatus,
+	)
+	if result.Error != nil {
+		tx.Rollback()
+		return false, errors.New("Failed to update booking status")
+	}
+	if result.RowsAffected == 0 {
+		// Changed by someone else since we read it: already handled.
+		tx.Rollback()
+		return false, nil
+	}
 
-			switch newStatus {
-			case "confirmed":
-				email.NotifyBookingConfirmed(booking.CustomerEmail, notif)
-			case "cancelled":
-				email.NotifyBookingCancelled(booking.CustomerEmail, notif)
-			}
+	if err := tx.Commit().Error; err != nil {
+		return false, errors.New("Failed to commit status update")
+	}
 
-			log.Printf("Email notification queued: booking #%d → %s → %s",
-				booking.ID, newStatus, booking.CustomerEmail)
+	if booking.CustomerEmail != "" {
+		tourTitle := getTourTitleByBooking(db, booking.ID)
+		notif := email.BookingNotification{
+			CustomerName: booking.CustomerName,
+			TourTitle:    tourTitle,
+			Seats:        int(booking.Seats),
+			TotalPrice:   booking.TotalPrice,
+			BookingID:    booking.ID,
+			Status:       newStatus,
 		}
 
-		return c.JSON(http.StatusOK, map[string]string{
-			"message": "Booking status updated",
-		})
+		switch newStatus {
+		case "confirmed":
+			if days, err := touritinerary.LoadForBooking(db, booking.ID); err == nil {
+				notif.Itinerary = touritinerary.EmailDays(days)
+			}
+			email.NotifyBookingConfirmed(booking.CustomerEmail, notif)
+		case "cancelled":
+			email.NotifyBookingCancelled(booking.CustomerEmail, notif)
+		}
+
+		log.Printf("Email notification queued: booking #%d → %s → %s",
+			booking.ID, newStatus, booking.CustomerEmail)
 	}
+
+	switch newStatus {
+	case "confirmed":
+		notify.NotifyBooking(db, notify.EventBookingConfirmed, booking.ID)
+		webhooks.PublishBooking(db, webhooks.EventBookingConfirmed, booking.ID)
+	case "cancelled":
+		notify.NotifyBooking(db, notify.EventBookingCancelled, booking.ID)
+		webhooks.PublishBooking(db, webhooks.EventBookingCancelled, booking.ID)
+	}
+
+	return true, nil
 }
 
 func getTourTitleByBooking(db *gorm.DB, bookingID uint) string {
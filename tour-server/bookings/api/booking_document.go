@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"tour-server/touritinerary"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// bookingDocument is the data behind the printable travel document.
+type bookingDocument struct {
+	ID            uint       `gorm:"column:id"`
+	UserID        *uint      `gorm:"column:user_id"`
+	CustomerName  string     `gorm:"column:customer_name"`
+	CustomerEmail string     `gorm:"column:customer_email"`
+	CustomerPhone string     `gorm:"column:customer_phone"`
+	Seats         uint       `gorm:"column:seats"`
+	TotalPrice    float64    `gorm:"column:total_price"`
+	Status        string     `gorm:"column:status"`
+	PaymentStatus string     `gorm:"column:payment_status"`
+	TourID        uint       `gorm:"column:tour_id"`
+	TourTitle     string     `gorm:"column:tour_title"`
+	DateFrom      time.Time  `gorm:"column:date_from"`
+	DateTo        time.Time  `gorm:"column:date_to"`
+	FromLocation  string     `gorm:"column:from_location"`
+	ToLocation    string     `gorm:"column:to_location"`
+	ExpiresAt     *time.Time `gorm:"column:payment_token_expires_at"`
+
+	Itinerary []touritinerary.Day `gorm:"-"`
+	IssuedAt  time.Time           `gorm:"-"`
+}
+
+const bookingDocumentSelect = `
+	SELECT b.id, b.user_id, b.customer_name, b.customer_email, b.customer_phone,
+		b.seats, b.total_price, b.status,
+		COALESCE(b.payment_status, 'pending') AS payment_status,
+		b.payment_token_expires_at,
+		t.id AS tour_id, t.title AS tour_title,
+		td.date_from, td.date_to,
+		COALESCE(fl.name, '') AS from_location,
+		COALESCE(tl.name, '') AS to_location
+	FROM bookings b
+	JOIN tour_dates td ON b.tour_date_id = td.id
+	JOIN tours t ON td.tour_id = t.id
+	LEFT JOIN locations fl ON td.from_location_id = fl.id
+	LEFT JOIN locations tl ON td.to_location_id = tl.id
+`
+
+// GetBookingDocument renders the printable travel document of one of the
+// signed-in user's bookings.
+func GetBookingDocument(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		bookingID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || bookingID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID бронювання",
+			})
+		}
+
+		userID, ok := c.Get("user_id").(uint)
+		if !ok || userID == 0 {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "Необхідна авторизація",
+			})
+		}
+
+		var doc bookingDocument
+		if err := db.Raw(bookingDocumentSelect+"WHERE b.id = ?", bookingID).Scan(&doc).Error; err != nil || doc.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Бронювання не знайдено",
+			})
+		}
+		if doc.UserID == nil || *doc.UserID != userID {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Немає доступу до цього бронювання",
+			})
+		}
+
+		return renderBookingDocument(c, db, &doc)
+	}
+}
+
+// GetBookingDocumentByToken renders the travel document for a guest
+// booking, authorised by its payment token.
+func GetBookingDocumentByToken(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Param("token")
+		if len(token) != 64 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+
+		var doc bookingDocument
+		err := db.Raw(bookingDocumentSelect+"WHERE b.payment_token = ?", token).Scan(&doc).Error
+		if err != nil || doc.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+
+		if doc.ExpiresAt != nil && time.Now().After(*doc.ExpiresAt) {
+			return c.JSON(http.StatusGone, map[string]string{"error": "Термін дії посилання сплив"})
+		}
+
+		return renderBookingDocument(c, db, &doc)
+	}
+}
+
+func renderBookingDocument(c echo.Context, db *gorm.DB, doc *bookingDocument) error {
+	days, err := touritinerary.Load(db, doc.TourID)
+	if err != nil {
+		log.Printf("booking document %d: itinerary: %v", doc.ID, err)
+	}
+	doc.Itinerary = days
+	doc.IssuedAt = time.Now()
+
+	var buf bytes.Buffer
+	if err := bookingDocumentTmpl.Execute(&buf, doc); err != nil {
+		log.Printf("booking document %d: render: %v", doc.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to render document",
+		})
+	}
+	return c.HTMLBlob(http.StatusOK, buf.Bytes())
+}
+
+var bookingDocumentTmpl = template.Must(template.New("booking_document").Funcs(template.FuncMap{
+	"date":  func(t time.Time) string { return t.Format("02.01.2006") },
+	"price": func(p float64) string { return strconv.FormatFloat(p, 'f', 2, 64) },
+	"statusText": func(s string) string {
+		switch s {
+		case "confirmed":
+			return "Підтверджено"
+		case "cancelled":
+			return "Скасовано"
+		default:
+			return "Очікує підтвердження"
+		}
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="uk">
+<head>
+<meta charset="UTF-8">
+<title>Туристичний документ — бронювання #{{.ID}}</title>
+<style>
+	body { font-family: Arial, sans-serif; color: #222; max-width: 800px; margin: 24px auto; padding: 0 16px; }
+	h1 { color: #2b6cb0; font-size: 24px; margin-bottom: 4px; }
+	h2 { font-size: 18px; border-bottom: 1px solid #ddd; padding-bottom: 4px; margin-top: 28px; }
+	table { width: 100%; border-collapse: collapse; }
+	td { padding: 6px 0; vertical-align: top; }
+	td.label { color: #666; width: 35%; }
+	.day { margin: 14px 0; page-break-inside: avoid; }
+	.day h3 { font-size: 15px; margin: 0 0 4px; }
+	.day p { margin: 4px 0; }
+	.meta { color: #666; font-size: 13px; }
+	.footer { margin-top: 32px; color: #999; font-size: 12px; }
+	@media print { body { margin: 0; } .no-print { display: none; } }
+</style>
+</head>
+<body>
+<button class="no-print" onclick="window.print()">Друкувати</button>
+<h1>{{.TourTitle}}</h1>
+<div class="meta">Бронювання #{{.ID}} · {{statusText .Status}}</div>
+
+<h2>Деталі бронювання</h2>
+<table>
+	<tr><td class="label">Турист</td><td>{{.CustomerName}}</td></tr>
+	<tr><td class="label">Email</td><td>{{.CustomerEmail}}</td></tr>
+	{{if .CustomerPhone}}<tr><td class="label">Телефон</td><td>{{.CustomerPhone}}</td></tr>{{end}}
+	<tr><td class="label">Дати</td><td>{{date .DateFrom}} — {{date .DateTo}}</td></tr>
+	{{if or .FromLocation .ToLocation}}<tr><td class="label">Маршрут</td><td>{{.FromLocation}}{{if and .FromLocation .ToLocation}} → {{end}}{{.ToLocation}}</td></tr>{{end}}
+	<tr><td class="label">Кількість місць</td><td>{{.Seats}}</td></tr>
+	<tr><td class="label">Вартість</td><td>{{price .TotalPrice}} грн</td></tr>
+	<tr><td class="label">Оплата</td><td>{{if eq .PaymentStatus "paid"}}Оплачено{{else}}Не оплачено{{end}}</td></tr>
+</table>
+
+{{if .Itinerary}}
+<h2>Програма туру</h2>
+{{range .Itinerary}}
+<div class="day">
+	<h3>День {{.DayNumber}}. {{.Title}}</h3>
+	{{if .Description}}<p>{{.Description}}</p>{{end}}
+	{{with .OvernightText}}<p class="meta">Ночівля: {{.}}</p>{{end}}
+	{{with .MealsText}}<p class="meta">Харчування: {{.}}</p>{{end}}
+</div>
+{{end}}
+{{end}}
+
+<div class="footer">Документ сформовано {{date .IssuedAt}}. OpenWorld.</div>
+</body>
+</html>
+`))
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"strconv"
 	"tour-server/email"
+	"tour-server/notify"
+	"tour-server/webhooks"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -120,6 +122,9 @@ func CancelBooking(db *gorm.DB) echo.HandlerFunc {
 			log.Printf("Cancel email queued: booking #%d → %s", booking.ID, booking.CustomerEmail)
 		}
 
+		notify.NotifyBooking(db, notify.EventBookingCancelled, booking.ID)
+		webhooks.PublishBooking(db, webhooks.EventBookingCancelled, booking.ID)
+
 		return c.JSON(http.StatusOK, map[string]string{
 			"message": "Бронювання скасовано",
 		})
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"time"
 	"tour-server/email"
+	"tour-server/notify"
+	"tour-server/webhooks"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -103,6 +105,9 @@ func CancelBookingByToken(db *gorm.DB) echo.HandlerFunc {
 			log.Printf("Guest cancel email queued: booking #%d → %s", booking.ID, booking.CustomerEmail)
 		}
 
+		notify.NotifyBooking(db, notify.EventBookingCancelled, booking.ID)
+		webhooks.PublishBooking(db, webhooks.EventBookingCancelled, booking.ID)
+
 		return c.JSON(http.StatusOK, map[string]string{"message": "Бронювання скасовано"})
 	}
 }
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tour-server/email"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	guestCodeTTL        = 10 * time.Minute
+	guestSessionTTL     = 30 * time.Minute
+	guestCodeMaxTries   = 5
+	guestCodesPerWindow = 3
+	guestCodeWindow     = 15 * time.Minute
+
+	// GuestSessionHeader carries the session token issued by VerifyGuestAccessCode.
+	GuestSessionHeader = "X-Guest-Session"
+)
+
+type GuestAccessRequest struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+type GuestBookingItem struct {
+	ID            uint      `json:"id" gorm:"column:id"`
+	TourID        uint      `json:"tour_id" gorm:"column:tour_id"`
+	TourTitle     string    `json:"tour_title" gorm:"column:tour_title"`
+	Seats         uint      `json:"seats" gorm:"column:seats"`
+	TotalPrice    float64   `json:"total_price" gorm:"column:total_price"`
+	Status        string    `json:"status" gorm:"column:status"`
+	PaymentStatus string    `json:"payment_status" gorm:"column:payment_status"`
+	DateFrom      time.Time `json:"date_from" gorm:"column:date_from"`
+	DateTo        time.Time `json:"date_to" gorm:"column:date_to"`
+	BookedAt      time.Time `json:"booked_at" gorm:"column:booked_at"`
+	CanPay        bool      `json:"can_pay" gorm:"-"`
+}
+
+// POST /guest-bookings/code
+// Emails a 6-digit one-time code if there are guest bookings for the email.
+// Always returns the same 200 response so the endpoint can't be used to
+// probe which emails have bookings; per-email throttling is silent too.
+func RequestGuestAccessCode(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req GuestAccessRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невірний формат запиту",
+			})
+		}
+
+		addr := normalizeEmail(req.Email)
+		if !strings.Contains(addr, "@") {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Вкажіть коректний email",
+			})
+		}
+
+		successResponse := map[string]string{
+			"message": "Якщо за цим email є бронювання, ми надіслали код доступу",
+		}
+
+		var bookingCount int64
+		db.Raw(
+			"SELECT COUNT(*) FROM bookings WHERE LOWER(customer_email) = ? AND is_guest_booking",
+			addr,
+		).Scan(&bookingCount)
+		if bookingCount == 0 {
+			return c.JSON(http.StatusOK, successResponse)
+		}
+
+		var recent int64
+		db.Raw(
+			"SELECT COUNT(*) FROM guest_access_codes WHERE email = ? AND created_at > ?",
+			addr, time.Now().Add(-guestCodeWindow),
+		).Scan(&recent)
+		if recent >= guestCodesPerWindow {
+			log.Printf("Guest access code throttled: %s", addr)
+			return c.JSON(http.StatusOK, successResponse)
+		}
+
+		code, err := generateAccessCode()
+		if err != nil {
+			log.Printf("Failed to generate access code: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Помилка сервера",
+			})
+		}
+
+		// Only the newest code is valid.
+		db.Exec("UPDATE guest_access_codes SET used_at = NOW() WHERE email = ? AND used_at IS NULL", addr)
+		if err := db.Exec(
+			"INSERT INTO guest_access_codes (email, code_hash, expires_at) VALUES (?, ?, ?)",
+			addr, hashToken(code), time.Now().Add(guestCodeTTL),
+		).Error; err != nil {
+			log.Printf("Failed to save access code: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Помилка сервера",
+			})
+		}
+
+		email.NotifyGuestAccessCode(addr, code, int(guestCodeTTL.Minutes()))
+		log.Printf("Guest access code sent: %s", addr)
+		return c.JSON(http.StatusOK, successResponse)
+	}
+}
+
+// POST /guest-bookings/verify
+// Exchanges a valid code for a guest session token. Each code allows a
+// limited number of wrong guesses before it is burned.
+func VerifyGuestAccessCode(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req GuestAccessRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невірний формат запиту",
+			})
+		}
+
+		addr := normalizeEmail(req.Email)
+		code := strings.TrimSpace(req.Code)
+		if !strings.Contains(addr, "@") || !isAccessCode(code) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Вкажіть email і 6-значний код",
+			})
+		}
+
+		invalid := map[string]string{"error": "Код недійсний або прострочений"}
+
+		var row struct {
+			ID       uint   `gorm:"column:id"`
+			CodeHash string `gorm:"column:code_hash"`
+			Attempts int    `gorm:"column:attempts"`
+		}
+		db.Raw(`
+			SELECT id, code_hash, attempts FROM guest_access_codes
+			WHERE email = ? AND used_at IS NULL AND expires_at > NOW()
+			ORDER BY created_at DESC LIMIT 1
+		`, addr).Scan(&row)
+		if row.ID == 0 || row.Attempts >= guestCodeMaxTries {
+			return c.JSON(http.StatusBadRequest, invalid)
+		}
+
+		if subtle.ConstantTimeCompare([]byte(hashToken(code)), []byte(row.CodeHash)) != 1 {
+			db.Exec("UPDATE guest_access_codes SET attempts = attempts + 1 WHERE id = ?", row.ID)
+			return c.JSON(http.StatusBadRequest, invalid)
+		}
+
+		// Single use: whoever flips used_at first wins.
+		if res := db.Exec(
+			"UPDATE guest_access_codes SET used_at = NOW() WHERE id = ? AND used_at IS NULL", row.ID,
+		); res.Error != nil || res.RowsAffected == 0 {
+			return c.JSON(http.StatusBadRequest, invalid)
+		}
+
+		token, err := generateBookingToken()
+		if err != nil {
+			log.Printf("Failed to generate guest session: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Помилка сервера",
+			})
+		}
+		expiresAt := time.Now().Add(guestSessionTTL)
+
+		db.Exec("DELETE FROM guest_sessions WHERE expires_at < NOW()")
+		if err := db.Exec(
+			"INSERT INTO guest_sessions (email, token_hash, expires_at) VALUES (?, ?, ?)",
+			addr, hashToken(token), expiresAt,
+		).Error; err != nil {
+			log.Printf("Failed to save guest session: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Помилка сервера",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"session_token": token,
+			"expires_at":    expiresAt,
+		})
+	}
+}
+
+// GET /guest-bookings (X-Guest-Session header)
+// Lists every guest booking made with the session's email.
+func GetGuestBookings(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		addr, ok := guestSessionEmail(db, c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "Сесія недійсна або завершилась",
+			})
+		}
+
+		var items []GuestBookingItem
+		err := db.Raw(`
+			SELECT b.id, td.tour_id, t.title AS tour_title, b.seats, b.total_price,
+				b.status, COALESCE(b.payment_status, 'pending') AS payment_status,
+				td.date_from, td.date_to, b.booked_at
+			FROM bookings b
+			JOIN tour_dates td ON b.tour_date_id = td.id
+			JOIN tours t ON td.tour_id = t.id
+			WHERE LOWER(b.customer_email) = ? AND b.is_guest_booking
+			ORDER BY b.booked_at DESC
+		`, addr).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Не вдалося завантажити бронювання",
+			})
+		}
+		for i := range items {
+			items[i].CanPay = items[i].Status != "cancelled" && items[i].PaymentStatus != "paid"
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"email":    addr,
+			"bookings": items,
+		})
+	}
+}
+
+// POST /guest-bookings/:id/payment-link (X-Guest-Session header)
+// Re-issues the booking's magic link. The returned token is the booking's
+// payment_token — the page then pays through /liqpay/create-payment-by-token
+// as usual. A still-valid token is reused so links already emailed keep
+// working; a missing or expired one is replaced.
+func ReissueGuestPaymentLink(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		addr, ok := guestSessionEmail(db, c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "Сесія недійсна або завершилась",
+			})
+		}
+
+		bookingID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || bookingID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невірний ID бронювання",
+			})
+		}
+
+		var booking struct {
+			ID            uint       `gorm:"column:id"`
+			Status        string     `gorm:"column:status"`
+			PaymentStatus string     `gorm:"column:payment_status"`
+			Token         *string    `gorm:"column:payment_token"`
+			ExpiresAt     *time.Time `gorm:"column:payment_token_expires_at"`
+		}
+		db.Raw(`
+			SELECT id, status, COALESCE(payment_status, 'pending') AS payment_status,
+				payment_token, payment_token_expires_at
+			FROM bookings
+			WHERE id = ? AND LOWER(customer_email) = ? AND is_guest_booking
+		`, bookingID, addr).Scan(&booking)
+		if booking.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Бронювання не знайдено",
+			})
+		}
+		if booking.PaymentStatus == "paid" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Вже оплачено"})
+		}
+		if booking.Status == "cancelled" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Бронювання скасовано"})
+		}
+
+		token, expiresAt := "", time.Time{}
+		if booking.Token != nil && booking.ExpiresAt != nil && time.Until(*booking.ExpiresAt) > time.Hour {
+			token, expiresAt = *booking.Token, *booking.ExpiresAt
+		} else {
+			if token, err = generateBookingToken(); err != nil {
+				log.Printf("Failed to generate booking token: %v", err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Помилка сервера",
+				})
+			}
+			expiresAt = time.Now().Add(7 * 24 * time.Hour)
+			if err := db.Exec(
+				"UPDATE bookings SET payment_token = ?, payment_token_expires_at = ? WHERE id = ?",
+				token, expiresAt, booking.ID,
+			).Error; err != nil {
+				log.Printf("Failed to save booking token: %v", err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Помилка сервера",
+				})
+			}
+			log.Printf("Payment link re-issued: booking #%d", booking.ID)
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"booking_id":  booking.ID,
+			"token":       token,
+			"payment_url": buildBookingURL(token),
+			"expires_at":  expiresAt,
+		})
+	}
+}
+
+// guestSessionEmail resolves the X-Guest-Session header to its email.
+func guestSessionEmail(db *gorm.DB, c echo.Context) (string, bool) {
+	token := c.Request().Header.Get(GuestSessionHeader)
+	if len(token) != 64 {
+		return "", false
+	}
+	var addr string
+	db.Raw(
+		"SELECT email FROM guest_sessions WHERE token_hash = ? AND expires_at > NOW()",
+		hashToken(token),
+	).Scan(&addr)
+	return addr, addr != ""
+}
+
+// hashToken is what gets stored for codes and session tokens — a DB leak
+// must not hand out working credentials.
+func hashToken(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateAccessCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func isAccessCode(s string) bool {
+	if len(s) != 6 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeEmail(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
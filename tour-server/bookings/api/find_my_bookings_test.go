@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequestGuestAccessCode_InvalidEmail(t *testing.T) {
+	for _, body := range []string{`{}`, `{"email":"not-an-email"}`, `{"email":"   "}`} {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/guest-bookings/code", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		RequestGuestAccessCode(nil)(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestVerifyGuestAccessCode_BadCodeFormat(t *testing.T) {
+	for _, code := range []string{"", "12345", "1234567", "12a456"} {
+		e := echo.New()
+		body := `{"email":"guest@example.com","code":"` + code + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/guest-bookings/verify", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		VerifyGuestAccessCode(nil)(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("code %q: expected 400, got %d", code, rec.Code)
+		}
+	}
+}
+
+func TestGetGuestBookings_NoSession(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/guest-bookings", nil)
+	req.Header.Set(GuestSessionHeader, "short")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	GetGuestBookings(nil)(c)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestReissueGuestPaymentLink_NoSession(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/guest-bookings/1/payment-link", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	ReissueGuestPaymentLink(nil)(c)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestGenerateAccessCode(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		code, err := generateAccessCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isAccessCode(code) {
+			t.Fatalf("generated code %q is not 6 digits", code)
+		}
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	if hashToken("123456") != hashToken("123456") {
+		t.Error("hashToken is not deterministic")
+	}
+	if hashToken("123456") == hashToken("123457") {
+		t.Error("hashToken collision")
+	}
+	if len(hashToken("x")) != 64 {
+		t.Error("expected 64-char hex digest")
+	}
+}
@@ -13,6 +13,10 @@ import (
 	"tour-server/bookings/models"
 	"tour-server/email"
 	"tour-server/middleware"
+	"tour-server/notify"
+	"tour-server/searchlog"
+	"tour-server/telegram"
+	"tour-server/webhooks"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -74,6 +78,15 @@ func PostBookings(db *gorm.DB) echo.HandlerFunc {
 			isGuestBooking = true
 		}
 
+		// ── SMS preference: explicit choice wins, else the account default ─
+		smsNotifications := false
+		if req.SMSNotifications != nil {
+			smsNotifications = *req.SMSNotifications
+		} else if userID != nil {
+			db.Raw("SELECT COALESCE(sms_notifications, FALSE) FROM tour_users WHERE id = ?", *userID).
+				Scan(&smsNotifications)
+		}
+
 		// ── Check available seats + get price from DB ─────────────────────
 		var seatInfo struct {
 			AvailableSeats uint    `gorm:"column:available_seats"`
@@ -85,7 +98,7 @@ func PostBookings(db *gorm.DB) echo.HandlerFunc {
 			FROM tour_seats ts
 			JOIN tour_dates td ON ts.tour_date_id = td.id
 			JOIN tours t ON td.tour_id = t.id
-			WHERE ts.tour_date_id = ?
+			WHERE ts.tour_date_id = ? AND NOT td.is_retired
 		`, req.TourDateID).Scan(&seatInfo).Error
 
 		if err != nil || seatInfo.Price == 0 {
@@ -107,15 +120,16 @@ func PostBookings(db *gorm.DB) echo.HandlerFunc {
 			req.TotalPrice, calculatedPrice, seatInfo.Price, req.Seats)
 
 		booking := models.Bookings{
-			TourDateID:     req.TourDateID,
-			CustomerName:   req.CustomerName,
-			CustomerEmail:  req.CustomerEmail,
-			CustomerPhone:  req.CustomerPhone,
-			Seats:          req.Seats,
-			TotalPrice:     calculatedPrice, // from DB, not from client
-			Status:         "pending",
-			UserID:         userID,
-			IsGuestBooking: isGuestBooking,
+			TourDateID:       req.TourDateID,
+			CustomerName:     req.CustomerName,
+			CustomerEmail:    req.CustomerEmail,
+			CustomerPhone:    req.CustomerPhone,
+			Seats:            req.Seats,
+			TotalPrice:       calculatedPrice, // from DB, not from client
+			Status:           "pending",
+			UserID:           userID,
+			IsGuestBooking:   isGuestBooking,
+			SMSNotifications: smsNotifications,
 		}
 
 		log.Printf("Creating booking: UserID=%v, IsGuest=%v, tour_date_id=%d, price=%.2f",
@@ -159,6 +173,13 @@ func PostBookings(db *gorm.DB) echo.HandlerFunc {
 		email.NotifyBookingCreated(req.CustomerEmail, notification)
 		log.Printf("Booking created email queued: #%d → %s", booking.ID, req.CustomerEmail)
 
+		notify.NotifyBooking(db, notify.EventBookingCreated, booking.ID)
+		telegram.AlertBooking(db, telegram.EventNewBooking, booking.ID)
+		webhooks.PublishBooking(db, webhooks.EventBookingCreated, booking.ID)
+		if err := searchlog.RecordBooking(db, req.SearchID, booking.ID); err != nil {
+			log.Printf("Failed to attribute booking #%d to search: %v", booking.ID, err)
+		}
+
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"message":     "Booking successful",
 			"booking_id":  booking.ID,
@@ -0,0 +1,414 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"tour-server/email"
+	"tour-server/middleware"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// transferInviteTTL is the longest an accept link stays valid; it is
+// shortened further so it never outlives the tour's transfer cut-off.
+const transferInviteTTL = 48 * time.Hour
+
+type TransferBookingRequest struct {
+	RecipientName  string `json:"recipient_name"`
+	RecipientEmail string `json:"recipient_email"`
+	RecipientPhone string `json:"recipient_phone"`
+}
+
+// transferableBooking is what both transfer steps need to know about a booking.
+type transferableBooking struct {
+	ID            uint      `gorm:"column:id"`
+	UserID        *uint     `gorm:"column:user_id"`
+	Status        string    `gorm:"column:status"`
+	Seats         uint      `gorm:"column:seats"`
+	CustomerName  string    `gorm:"column:customer_name"`
+	CustomerEmail string    `gorm:"column:customer_email"`
+	TourTitle     string    `gorm:"column:tour_title"`
+	DateFrom      time.Time `gorm:"column:date_from"`
+	CutoffHours   int       `gorm:"column:transfer_cutoff_hours"`
+}
+
+const transferableBookingQuery = `
+	SELECT b.id, b.user_id, b.status, b.seats, b.customer_name,
+		COALESCE(b.customer_email, '') AS customer_email,
+		t.title AS tour_title, td.date_from,
+		COALESCE(t.transfer_cutoff_hours, 72) AS transfer_cutoff_hours
+	FROM bookings b
+	JOIN tour_dates td ON b.tour_date_id = td.id
+	JOIN tours t ON td.tour_id = t.id
+`
+
+// cutoff is the last moment a transfer can be started or accepted.
+func (b transferableBooking) cutoff() time.Time {
+	return b.DateFrom.Add(-time.Duration(b.CutoffHours) * time.Hour)
+}
+
+// transferError returns the reason the booking can't change hands now, or "".
+func (b transferableBooking) transferError(now time.Time) string {
+	if b.Status == "cancelled" {
+		return "Скасоване бронювання не можна передати"
+	}
+	if now.After(b.cutoff()) {
+		return fmt.Sprintf("Передача недоступна менше ніж за %d год до відправлення", b.CutoffHours)
+	}
+	return ""
+}
+
+// POST /bookings/:id/transfer
+// The signed-in owner invites another traveller to take over the booking.
+func TransferBooking(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		bookingID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || bookingID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID бронювання",
+			})
+		}
+
+		userID, ok := c.Get("user_id").(uint)
+		if !ok || userID == 0 {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "Необхідна авторизація",
+			})
+		}
+
+		req, errMsg := bindTransferRequest(c)
+		if errMsg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+		}
+
+		var booking transferableBooking
+		db.Raw(transferableBookingQuery+" WHERE b.id = ?", bookingID).Scan(&booking)
+		if booking.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Бронювання не знайдено",
+			})
+		}
+		if booking.UserID == nil || *booking.UserID != userID {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Це не ваше бронювання",
+			})
+		}
+
+		return startTransfer(db, c, booking, req)
+	}
+}
+
+// POST /bookings/by-token/:token/transfer
+// Same as TransferBooking for a guest holding the magic-link token.
+func TransferBookingByToken(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Param("token")
+		if len(token) != 64 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+
+		req, errMsg := bindTransferRequest(c)
+		if errMsg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+		}
+
+		var row struct {
+			ID        uint       `gorm:"column:id"`
+			ExpiresAt *time.Time `gorm:"column:payment_token_expires_at"`
+		}
+		db.Raw("SELECT id, payment_token_expires_at FROM bookings WHERE payment_token = ?", token).Scan(&row)
+		if row.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+		if row.ExpiresAt != nil && time.Now().After(*row.ExpiresAt) {
+			return c.JSON(http.StatusGone, map[string]string{"error": "Термін дії посилання сплив"})
+		}
+
+		var booking transferableBooking
+		db.Raw(transferableBookingQuery+" WHERE b.id = ?", row.ID).Scan(&booking)
+
+		return startTransfer(db, c, booking, req)
+	}
+}
+
+func bindTransferRequest(c echo.Context) (TransferBookingRequest, string) {
+	var req TransferBookingRequest
+	if err := c.Bind(&req); err != nil {
+		return req, "Невірний формат запиту"
+	}
+
+	req.RecipientName = middleware.SanitizeName(req.RecipientName, 100)
+	if errMsg := middleware.ValidateName(req.RecipientName); errMsg != "" {
+		return req, errMsg
+	}
+	if errMsg := middleware.ValidatePhone(req.RecipientPhone); errMsg != "" {
+		return req, errMsg
+	}
+	req.RecipientEmail = strings.TrimSpace(req.RecipientEmail)
+	if req.RecipientEmail == "" {
+		return req, "Email отримувача обов'язковий"
+	}
+	if errMsg := middleware.ValidateEmail(req.RecipientEmail); errMsg != "" {
+		return req, errMsg
+	}
+	return req, ""
+}
+
+// startTransfer records the invite (replacing any pending one for the
+// booking) and emails the recipient and the current owner.
+func startTransfer(db *gorm.DB, c echo.Context, booking transferableBooking, req TransferBookingRequest) error {
+	now := time.Now()
+	if errMsg := booking.transferError(now); errMsg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+	}
+	if strings.EqualFold(req.RecipientEmail, booking.CustomerEmail) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Бронювання вже оформлене на цей email",
+		})
+	}
+
+	token, err := generateBookingToken()
+	if err != nil {
+		log.Printf("Failed to generate transfer token: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Помилка сервера",
+		})
+	}
+	expiresAt := now.Add(transferInviteTTL)
+	if cutoff := booking.cutoff(); cutoff.Before(expiresAt) {
+		expiresAt = cutoff
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to start transaction",
+		})
+	}
+	tx.Exec("UPDATE booking_transfers SET status = 'cancelled' WHERE booking_id = ? AND status = 'pending'", booking.ID)
+	if err := tx.Exec(`
+		INSERT INTO booking_transfers
+			(booking_id, from_name, from_email, to_name, to_email, to_phone, token_hash, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, booking.ID, booking.CustomerName, booking.CustomerEmail,
+		req.RecipientName, req.RecipientEmail, req.RecipientPhone,
+		hashToken(token), expiresAt).Error; err != nil {
+		tx.Rollback()
+		log.Printf("Failed to save transfer: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Не вдалося створити передачу",
+		})
+	}
+	if err := tx.Commit().Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Помилка збереження",
+		})
+	}
+
+	n := email.TransferNotification{
+		BookingID: booking.ID,
+		TourTitle: booking.TourTitle,
+		DateFrom:  booking.DateFrom,
+		Seats:     int(booking.Seats),
+		FromName:  booking.CustomerName,
+		ToName:    req.RecipientName,
+		AcceptURL: buildTransferURL(token),
+		ExpiresAt: expiresAt,
+	}
+	email.NotifyTransferInvite(req.RecipientEmail, n)
+	if booking.CustomerEmail != "" {
+		email.NotifyTransferRequested(booking.CustomerEmail, n)
+	}
+	log.Printf("Booking transfer requested: #%d → %s", booking.ID, req.RecipientEmail)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":    "Запрошення надіслано",
+		"expires_at": expiresAt,
+	})
+}
+
+// GET /booking-transfers/:token
+// What the recipient sees before accepting.
+func GetBookingTransfer(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Param("token")
+		if len(token) != 64 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+
+		var row struct {
+			BookingID uint      `gorm:"column:booking_id"`
+			Status    string    `gorm:"column:status"`
+			FromName  string    `gorm:"column:from_name"`
+			ToName    string    `gorm:"column:to_name"`
+			ExpiresAt time.Time `gorm:"column:expires_at"`
+		}
+		db.Raw(
+			"SELECT booking_id, status, from_name, to_name, expires_at FROM booking_transfers WHERE token_hash = ?",
+			hashToken(token),
+		).Scan(&row)
+		if row.BookingID == 0 || row.Status == "cancelled" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+
+		var booking transferableBooking
+		db.Raw(transferableBookingQuery+" WHERE b.id = ?", row.BookingID).Scan(&booking)
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"booking_id": row.BookingID,
+			"tour_title": booking.TourTitle,
+			"date_from":  booking.DateFrom,
+			"seats":      booking.Seats,
+			"from_name":  row.FromName,
+			"to_name":    row.ToName,
+			"status":     row.Status,
+			"expires_at": row.ExpiresAt,
+			"can_accept": row.Status == "pending" && time.Now().Before(row.ExpiresAt) &&
+				booking.transferError(time.Now()) == "",
+		})
+	}
+}
+
+// POST /booking-transfers/:token/accept
+// Moves the booking to the recipient: contact details, user_id (when the
+// recipient email has an account) and a fresh payment_token, so links the
+// previous owner holds stop working. Both parties are emailed.
+func AcceptBookingTransfer(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Param("token")
+		if len(token) != 64 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+
+		tx := db.Begin()
+		if tx.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to start transaction",
+			})
+		}
+
+		var transfer struct {
+			ID        uint      `gorm:"column:id"`
+			BookingID uint      `gorm:"column:booking_id"`
+			Status    string    `gorm:"column:status"`
+			FromName  string    `gorm:"column:from_name"`
+			FromEmail string    `gorm:"column:from_email"`
+			ToName    string    `gorm:"column:to_name"`
+			ToEmail   string    `gorm:"column:to_email"`
+			ToPhone   string    `gorm:"column:to_phone"`
+			ExpiresAt time.Time `gorm:"column:expires_at"`
+		}
+		tx.Raw(`
+			SELECT id, booking_id, status, from_name, from_email, to_name, to_email, to_phone, expires_at
+			FROM booking_transfers WHERE token_hash = ? FOR UPDATE
+		`, hashToken(token)).Scan(&transfer)
+		if transfer.ID == 0 || transfer.Status == "cancelled" {
+			tx.Rollback()
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+		if transfer.Status == "accepted" {
+			tx.Rollback()
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Передачу вже прийнято"})
+		}
+		if time.Now().After(transfer.ExpiresAt) {
+			tx.Rollback()
+			return c.JSON(http.StatusGone, map[string]string{"error": "Термін дії запрошення сплив"})
+		}
+
+		var booking transferableBooking
+		tx.Raw(transferableBookingQuery+" WHERE b.id = ? FOR UPDATE OF b", transfer.BookingID).Scan(&booking)
+		if booking.ID == 0 {
+			tx.Rollback()
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Бронювання не знайдено"})
+		}
+		if errMsg := booking.transferError(time.Now()); errMsg != "" {
+			tx.Rollback()
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+		}
+
+		// The recipient's account, if they have one, becomes the owner.
+		var recipientID uint
+		tx.Raw("SELECT id FROM tour_users WHERE LOWER(email) = LOWER(?)", transfer.ToEmail).Scan(&recipientID)
+		var newUserID *uint
+		if recipientID != 0 {
+			newUserID = &recipientID
+		}
+
+		newToken, err := generateBookingToken()
+		if err != nil {
+			tx.Rollback()
+			log.Printf("Failed to generate booking token: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Помилка сервера",
+			})
+		}
+		tokenExpiresAt := time.Now().Add(7 * 24 * time.Hour)
+
+		if err := tx.Exec(`
+			UPDATE bookings
+			SET customer_name = ?, customer_email = ?, customer_phone = ?,
+				user_id = ?, is_guest_booking = ?,
+				-- the previous owner's SMS opt-in doesn't carry over
+				sms_notifications = COALESCE((SELECT sms_notifications FROM tour_users WHERE id = ?), FALSE),
+				payment_token = ?, payment_token_expires_at = ?
+			WHERE id = ?
+		`, transfer.ToName, transfer.ToEmail, transfer.ToPhone,
+			newUserID, newUserID == nil, newUserID,
+			newToken, tokenExpiresAt, booking.ID).Error; err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Не вдалося передати бронювання",
+			})
+		}
+		tx.Exec("UPDATE booking_transfers SET status = 'accepted', accepted_at = NOW() WHERE id = ?", transfer.ID)
+		tx.Exec("UPDATE booking_transfers SET status = 'cancelled' WHERE booking_id = ? AND status = 'pending'", booking.ID)
+
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Помилка збереження",
+			})
+		}
+
+		n := email.TransferNotification{
+			BookingID: booking.ID,
+			TourTitle: booking.TourTitle,
+			DateFrom:  booking.DateFrom,
+			Seats:     int(booking.Seats),
+			FromName:  transfer.FromName,
+			ToName:    transfer.ToName,
+		}
+		if transfer.FromEmail != "" {
+			email.NotifyTransferCompleted(transfer.FromEmail, n)
+		}
+		// Account holders manage bookings from their cabinet, like in PostBookings.
+		if newUserID == nil {
+			n.PaymentURL = buildBookingURL(newToken)
+		}
+		email.NotifyTransferReceived(transfer.ToEmail, n)
+		log.Printf("Booking transferred: #%d → %s (user_id=%v)", booking.ID, transfer.ToEmail, newUserID)
+
+		resp := map[string]interface{}{
+			"message":     "Бронювання передано",
+			"booking_id":  booking.ID,
+			"has_account": newUserID != nil,
+		}
+		if newUserID == nil {
+			resp["token"] = newToken
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func buildTransferURL(token string) string {
+	base := os.Getenv("FRONTEND_URL")
+	if base == "" {
+		base = "https://openworld.local"
+	}
+	return fmt.Sprintf("%s/transfer/%s", base, token)
+}
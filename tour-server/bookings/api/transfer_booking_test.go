@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newTransferContext(body, id string, userID interface{}) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/bookings/"+id+"/transfer", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	if userID != nil {
+		c.Set("user_id", userID)
+	}
+	return c, rec
+}
+
+func TestTransferBooking_InvalidID(t *testing.T) {
+	c, rec := newTransferContext(`{}`, "abc", uint(1))
+	TransferBooking(nil)(c)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestTransferBooking_Unauthorized(t *testing.T) {
+	c, rec := newTransferContext(`{}`, "1", nil)
+	TransferBooking(nil)(c)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestTransferBooking_InvalidRecipient(t *testing.T) {
+	bodies := []string{
+		`{"recipient_email":"friend@example.com","recipient_phone":"+380951234567"}`,
+		`{"recipient_name":"Олена","recipient_email":"friend@example.com","recipient_phone":"12345"}`,
+		`{"recipient_name":"Олена","recipient_phone":"+380951234567"}`,
+		`{"recipient_name":"Олена","recipient_email":"not-an-email","recipient_phone":"+380951234567"}`,
+	}
+	for _, body := range bodies {
+		c, rec := newTransferContext(body, "1", uint(1))
+		TransferBooking(nil)(c)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestTransferTokenEndpoints_ShortToken(t *testing.T) {
+	handlers := map[string]echo.HandlerFunc{
+		"TransferBookingByToken": TransferBookingByToken(nil),
+		"GetBookingTransfer":     GetBookingTransfer(nil),
+		"AcceptBookingTransfer":  AcceptBookingTransfer(nil),
+	}
+	for name, h := range handlers {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("token")
+		c.SetParamValues("short")
+
+		h(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", name, rec.Code)
+		}
+	}
+}
+
+func TestTransferableBooking_Cutoff(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	b := transferableBooking{Status: "confirmed", DateFrom: now.Add(96 * time.Hour), CutoffHours: 72}
+
+	if msg := b.transferError(now); msg != "" {
+		t.Errorf("4 days before departure with 72h cut-off: unexpected %q", msg)
+	}
+	if msg := b.transferError(now.Add(25 * time.Hour)); msg == "" {
+		t.Error("71h before departure: expected cut-off error")
+	}
+
+	b.Status = "cancelled"
+	if msg := b.transferError(now); msg == "" {
+		t.Error("cancelled booking: expected error")
+	}
+}
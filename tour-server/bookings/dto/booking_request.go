@@ -7,4 +7,10 @@ type BookingRequest struct {
 	CustomerPhone string  `json:"customer_phone"`
 	Seats         uint    `json:"seats"`
 	TotalPrice    float64 `json:"total_price"`
+	// SMSNotifications opts the booking into SMS updates. nil = use the
+	// account default (guests: off).
+	SMSNotifications *bool `json:"sms_notifications,omitempty"`
+	// SearchID is the searchId of the search the tour was found through,
+	// for search analytics.
+	SearchID string `json:"search_id,omitempty"`
 }
@@ -0,0 +1,27 @@
+-- Migration: "find my bookings" for guests
+-- A guest proves they own an email with a 6-digit one-time code and gets a
+-- short-lived session that lists their bookings. Only SHA-256 hashes of the
+-- codes and session tokens are stored.
+
+CREATE TABLE IF NOT EXISTS guest_access_codes (
+    id          SERIAL PRIMARY KEY,
+    email       VARCHAR(255) NOT NULL,      -- lower-cased
+    code_hash   VARCHAR(64) NOT NULL,
+    attempts    INTEGER NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMP NOT NULL,
+    used_at     TIMESTAMP,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_guest_access_codes_email ON guest_access_codes(email, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS guest_sessions (
+    id          SERIAL PRIMARY KEY,
+    email       VARCHAR(255) NOT NULL,
+    token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    expires_at  TIMESTAMP NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Guest bookings are looked up by email case-insensitively.
+CREATE INDEX IF NOT EXISTS idx_bookings_customer_email_lower
+    ON bookings(LOWER(customer_email)) WHERE is_guest_booking;
@@ -0,0 +1,25 @@
+-- Migration: booking transfers
+-- The owner (or guest token holder) invites another traveller by email; the
+-- booking changes hands when the invite is accepted. Only a SHA-256 hash of
+-- the accept token is stored.
+
+ALTER TABLE tours
+    ADD COLUMN IF NOT EXISTS transfer_cutoff_hours INTEGER NOT NULL DEFAULT 72;
+
+CREATE TABLE IF NOT EXISTS booking_transfers (
+    id               SERIAL PRIMARY KEY,
+    booking_id       INTEGER NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    from_name        VARCHAR(255) NOT NULL,
+    from_email       VARCHAR(255) NOT NULL DEFAULT '',
+    to_name          VARCHAR(255) NOT NULL,
+    to_email         VARCHAR(255) NOT NULL,
+    to_phone         VARCHAR(20) NOT NULL,
+    token_hash       VARCHAR(64) NOT NULL UNIQUE,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending'
+                     CHECK (status IN ('pending', 'accepted', 'cancelled')),
+    expires_at       TIMESTAMP NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    accepted_at      TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_transfers_booking ON booking_transfers(booking_id);
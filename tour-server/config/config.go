@@ -3,17 +3,21 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	JWT      JWTConfig      `yaml:"jwt"`
-	App      AppConfig      `yaml:"app"`
-	SMTP     SMTPConfig     `yaml:"smtp"`
+	Server     ServerConfig     `yaml:"server"`
+	Database   DatabaseConfig   `yaml:"database"`
+	JWT        JWTConfig        `yaml:"jwt"`
+	App        AppConfig        `yaml:"app"`
+	SMTP       SMTPConfig       `yaml:"smtp"`
+	SMS        SMSConfig        `yaml:"sms"`
+	Telegram   TelegramConfig   `yaml:"telegram"`
+	Moderation ModerationConfig `yaml:"moderation"`
 }
 
 type ServerConfig struct {
@@ -50,6 +54,45 @@ type SMTPConfig struct {
 	From     string `yaml:"from"`
 }
 
+// SMSConfig selects the SMS gateway. Provider is "turbosms", "http",
+// "fake" (log only) or empty (SMS disabled).
+type SMSConfig struct {
+	Provider      string `yaml:"provider"`
+	Sender        string `yaml:"sender"`
+	Token         string `yaml:"token"`
+	URL           string `yaml:"url"`
+	ReminderHours int    `yaml:"reminder_hours"`
+}
+
+// TelegramConfig configures the staff alert bot. Mode is "webhook" or
+// "polling"; StaffIDs are the Telegram user IDs allowed to press the
+// confirm/cancel buttons.
+type TelegramConfig struct {
+	BotToken      string  `yaml:"bot_token"`
+	ChatID        int64   `yaml:"chat_id"`
+	Mode          string  `yaml:"mode"`
+	WebhookURL    string  `yaml:"webhook_url"`
+	WebhookSecret string  `yaml:"webhook_secret"`
+	StaffIDs      []int64 `yaml:"staff_ids"`
+	APIURL        string  `yaml:"api_url"`
+}
+
+// ModerationConfig is the comment pre-screening policy. AutoApprove
+// decides who skips the queue when screening finds nothing: "all",
+// "users" (signed in), "verified" (verified buyers) or "none".
+// Comments with more than MaxLinks links, a banned word, text duplicated
+// from another comment or posted from an account younger than
+// NewAccountHours always wait for a moderator. ReportThreshold reports
+// from signed-in readers (default 3) hide a published comment until it is
+// reviewed.
+type ModerationConfig struct {
+	AutoApprove     string   `yaml:"auto_approve"`
+	MaxLinks        int      `yaml:"max_links"`
+	BannedWords     []string `yaml:"banned_words"`
+	NewAccountHours int      `yaml:"new_account_hours"`
+	ReportThreshold int      `yaml:"report_threshold"`
+}
+
 var appConfig Config
 
 func LoadConfig(configPath string) error {
@@ -83,6 +126,27 @@ func LoadConfig(configPath string) error {
 		appConfig.SMTP.From = smtpFrom
 	}
 
+	// SMS overrides from environment
+	if smsToken := os.Getenv("SMS_TOKEN"); smsToken != "" {
+		appConfig.SMS.Token = smsToken
+	}
+	if smsProvider := os.Getenv("SMS_PROVIDER"); smsProvider != "" {
+		appConfig.SMS.Provider = smsProvider
+	}
+
+	// Telegram overrides from environment
+	if botToken := os.Getenv("TELEGRAM_BOT_TOKEN"); botToken != "" {
+		appConfig.Telegram.BotToken = botToken
+	}
+	if chatID := os.Getenv("TELEGRAM_CHAT_ID"); chatID != "" {
+		if id, err := strconv.ParseInt(chatID, 10, 64); err == nil {
+			appConfig.Telegram.ChatID = id
+		}
+	}
+	if secret := os.Getenv("TELEGRAM_WEBHOOK_SECRET"); secret != "" {
+		appConfig.Telegram.WebhookSecret = secret
+	}
+
 	if appConfig.JWT.Secret == "" {
 		log.Fatal("JWT_SECRET is required! Set it in .env file or environment variable.")
 	}
@@ -101,6 +165,18 @@ func LoadConfig(configPath string) error {
 		log.Println("SMTP: not configured — email notifications disabled")
 	}
 
+	if appConfig.SMS.Provider != "" {
+		log.Printf("SMS: %s gateway, sender %q", appConfig.SMS.Provider, appConfig.SMS.Sender)
+	} else {
+		log.Println("SMS: not configured — SMS notifications disabled")
+	}
+
+	if appConfig.Telegram.BotToken != "" {
+		log.Printf("Telegram: staff chat %d, %s mode", appConfig.Telegram.ChatID, appConfig.Telegram.Mode)
+	} else {
+		log.Println("Telegram: not configured — staff alerts disabled")
+	}
+
 	return nil
 }
 
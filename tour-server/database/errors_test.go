@@ -0,0 +1,21 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	if !IsUniqueViolation(fmt.Errorf("commit: %w", &pgconn.PgError{Code: "23505"})) {
+		t.Error("expected wrapped 23505 to be a unique violation")
+	}
+	if IsUniqueViolation(&pgconn.PgError{Code: "23503"}) {
+		t.Error("foreign key violation is not a unique violation")
+	}
+	if IsUniqueViolation(errors.New("boom")) || IsUniqueViolation(nil) {
+		t.Error("plain errors are not unique violations")
+	}
+}
@@ -0,0 +1,76 @@
+package email
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// CommentRejection holds the data for the comment-rejected email.
+type CommentRejection struct {
+	AuthorName string
+	TourTitle  string
+	Comment    string
+	Reason     string
+	TourURL    string
+}
+
+// NotifyCommentRejected tells an author that a moderator rejected their
+// comment and why.
+func NotifyCommentRejected(to string, d CommentRejection) {
+	subject := fmt.Sprintf("Ваш коментар до туру «%s» не опубліковано", d.TourTitle)
+
+	excerpt := d.Comment
+	if utf8.RuneCountInString(excerpt) > 300 {
+		excerpt = string([]rune(excerpt)[:300]) + "…"
+	}
+
+	body, err := renderTemplate(commentRejectedTemplate, struct {
+		CommentRejection
+		Excerpt string
+	}{d, excerpt})
+	if err != nil {
+		fmt.Printf("Email template error: %v\n", err)
+		return
+	}
+	SendAsync(to, subject, body)
+}
+
+const commentRejectedTemplate = `<!DOCTYPE html>
+<html lang="uk">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width,initial-scale=1.0"></head>
+<body style="margin:0;padding:0;background:#f1f5f9;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;">
+<table width="100%" cellpadding="0" cellspacing="0" style="background:#f1f5f9;padding:32px 16px;">
+<tr><td align="center">
+<table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
+
+<tr><td style="background:linear-gradient(135deg,#64748b,#475569);padding:32px 40px;text-align:center;">
+  <div style="font-size:36px;margin-bottom:8px;">💬</div>
+  <h1 style="color:#ffffff;font-size:22px;font-weight:800;margin:0;">Коментар не опубліковано</h1>
+</td></tr>
+
+<tr><td style="padding:32px 40px;">
+  <p style="color:#334155;font-size:16px;line-height:1.6;margin:0 0 16px;">
+    Привіт, <strong>{{.AuthorName}}</strong>! Модератор не пропустив ваш коментар до туру <strong>{{.TourTitle}}</strong>.
+  </p>
+
+  <table width="100%" cellpadding="0" cellspacing="0" style="background:#f8fafc;border:1px solid #e2e8f0;border-radius:12px;margin-bottom:24px;">
+  <tr><td style="padding:20px 24px;color:#64748b;font-size:14px;line-height:1.6;font-style:italic;">{{.Excerpt}}</td></tr>
+  </table>
+
+  <p style="color:#334155;font-size:15px;line-height:1.6;margin:0 0 24px;">Причина: {{.Reason}}</p>
+
+  <div style="text-align:center;margin-bottom:24px;">
+    <a href="{{.TourURL}}" style="display:inline-block;background:#0ea5e9;color:#ffffff;text-decoration:none;font-weight:700;font-size:15px;padding:14px 32px;border-radius:10px;">Перейти до туру</a>
+  </div>
+
+  <p style="color:#64748b;font-size:14px;line-height:1.6;margin:0;">
+    Ви можете написати новий коментар з урахуванням правил. Якщо вважаєте, що сталася помилка — просто відповідайте на цей лист.
+  </p>
+</td></tr>
+
+<tr><td style="background:#f8fafc;border-top:1px solid #e2e8f0;padding:24px 40px;text-align:center;">
+  <p style="color:#94a3b8;font-size:13px;margin:0 0 4px;">© 2026 OpenWorld — Ваш провідник у світ подорожей</p>
+  <p style="color:#94a3b8;font-size:12px;margin:0;">Цей лист відправлено автоматично.</p>
+</td></tr>
+
+</table></td></tr></table></body></html>`
@@ -0,0 +1,122 @@
+package email
+
+import (
+	"fmt"
+	"time"
+)
+
+// AlternativeDate is another departure of the same tour offered to a
+// customer whose departure was cancelled.
+type AlternativeDate struct {
+	DateFrom       time.Time
+	DateTo         time.Time
+	AvailableSeats int
+}
+
+// DepartureCancellation holds the data for the departure-cancelled email.
+type DepartureCancellation struct {
+	CustomerName string
+	BookingID    uint
+	TourTitle    string
+	DateFrom     time.Time
+	DateTo       time.Time
+	Reason       string
+	TotalPrice   float64
+	Refunded     bool // a provider refund went through
+	RefundFailed bool // paid, but the refund must be finished by staff
+	Alternatives []AlternativeDate
+	TourURL      string
+}
+
+// NotifyDepartureCancelled tells a customer that the operator cancelled
+// their departure, what happens to their money and which dates they can
+// rebook instead.
+func NotifyDepartureCancelled(to string, d DepartureCancellation) {
+	subject := fmt.Sprintf("❌ Відправлення скасовано — %s (бронювання #%d)", d.TourTitle, d.BookingID)
+
+	type altRow struct{ Dates, Seats string }
+	alts := make([]altRow, 0, len(d.Alternatives))
+	for _, a := range d.Alternatives {
+		alts = append(alts, altRow{
+			Dates: formatDateRange(a.DateFrom, a.DateTo),
+			Seats: fmt.Sprintf("%d вільних місць", a.AvailableSeats),
+		})
+	}
+
+	body, err := renderTemplate(departureCancelledTemplate, struct {
+		DepartureCancellation
+		Dates    string
+		PriceStr string
+		AltRows  []altRow
+	}{
+		DepartureCancellation: d,
+		Dates:                 formatDateRange(d.DateFrom, d.DateTo),
+		PriceStr:              formatPrice(d.TotalPrice),
+		AltRows:               alts,
+	})
+	if err != nil {
+		fmt.Printf("Email template error: %v\n", err)
+		return
+	}
+	SendAsync(to, subject, body)
+}
+
+const departureCancelledTemplate = `<!DOCTYPE html>
+<html lang="uk">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width,initial-scale=1.0"></head>
+<body style="margin:0;padding:0;background:#f1f5f9;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;">
+<table width="100%" cellpadding="0" cellspacing="0" style="background:#f1f5f9;padding:32px 16px;">
+<tr><td align="center">
+<table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
+
+<tr><td style="background:linear-gradient(135deg,#ef4444,#dc2626);padding:32px 40px;text-align:center;">
+  <div style="font-size:36px;margin-bottom:8px;">❌</div>
+  <h1 style="color:#ffffff;font-size:22px;font-weight:800;margin:0;">Відправлення скасовано</h1>
+</td></tr>
+
+<tr><td style="padding:32px 40px;">
+  <p style="color:#334155;font-size:16px;line-height:1.6;margin:0 0 16px;">
+    Привіт, <strong>{{.CustomerName}}</strong>! На жаль, ми змушені скасувати відправлення туру <strong>{{.TourTitle}}</strong> ({{.Dates}}). Ваше бронювання #{{.BookingID}} скасовано.
+  </p>
+  {{if .Reason}}
+  <p style="color:#64748b;font-size:14px;line-height:1.6;margin:0 0 24px;">Причина: {{.Reason}}</p>
+  {{end}}
+
+  <table width="100%" cellpadding="0" cellspacing="0" style="background:#f8fafc;border:1px solid #e2e8f0;border-radius:12px;margin-bottom:24px;">
+  <tr><td style="padding:20px 24px;color:#334155;font-size:15px;line-height:1.6;">
+    {{if .Refunded}}
+      💳 Кошти у розмірі <strong>{{.PriceStr}} ₴</strong> повернено на вашу картку. Зарахування може зайняти до 7 банківських днів.
+    {{else if .RefundFailed}}
+      💳 Ми повернемо <strong>{{.PriceStr}} ₴</strong> найближчим часом — наш менеджер зв'яжеться з вами щодо повернення.
+    {{else}}
+      Оплата за це бронювання не надходила, тож нічого сплачувати не потрібно.
+    {{end}}
+  </td></tr>
+  </table>
+
+  {{if .AltRows}}
+  <h2 style="color:#0f172a;font-size:17px;margin:0 0 12px;">Інші дати цього туру</h2>
+  <table width="100%" cellpadding="0" cellspacing="0" style="border:1px solid #e2e8f0;border-radius:12px;margin-bottom:24px;">
+    {{range $i, $a := .AltRows}}
+    <tr>
+      <td style="padding:12px 20px;{{if $i}}border-top:1px solid #e2e8f0;{{end}}color:#0f172a;font-size:15px;font-weight:600;">{{$a.Dates}}</td>
+      <td style="padding:12px 20px;{{if $i}}border-top:1px solid #e2e8f0;{{end}}color:#16a34a;font-size:13px;text-align:right;">{{$a.Seats}}</td>
+    </tr>
+    {{end}}
+  </table>
+  <div style="text-align:center;margin-bottom:24px;">
+    <a href="{{.TourURL}}" style="display:inline-block;background:#0ea5e9;color:#ffffff;text-decoration:none;font-weight:700;font-size:15px;padding:14px 32px;border-radius:10px;">Обрати іншу дату</a>
+  </div>
+  {{end}}
+
+  <p style="color:#64748b;font-size:14px;line-height:1.6;margin:0;">
+    Перепрошуємо за незручності. Якщо у вас є питання — просто відповідайте на цей лист.
+  </p>
+</td></tr>
+
+<tr><td style="background:#f8fafc;border-top:1px solid #e2e8f0;padding:24px 40px;text-align:center;">
+  <p style="color:#94a3b8;font-size:13px;margin:0 0 4px;">© 2026 OpenWorld — Ваш провідник у світ подорожей</p>
+  <p style="color:#94a3b8;font-size:12px;margin:0;">Цей лист відправлено автоматично.</p>
+</td></tr>
+
+</table></td></tr></table></body></html>`
@@ -0,0 +1,102 @@
+package email
+
+import (
+	"fmt"
+	"time"
+)
+
+// DepartureChange describes an edit to a departure a customer has booked.
+type DepartureChange struct {
+	CustomerName string
+	BookingID    uint
+	TourTitle    string
+	OldDateFrom  time.Time
+	OldDateTo    time.Time
+	NewDateFrom  time.Time
+	NewDateTo    time.Time
+	OldRoute     string // "Київ → Дубай"
+	NewRoute     string
+}
+
+// NotifyDepartureChanged tells a customer their booked departure was edited.
+func NotifyDepartureChanged(to string, d DepartureChange) {
+	subject := fmt.Sprintf("📅 Зміни у вашому турі — %s (бронювання #%d)", d.TourTitle, d.BookingID)
+	body, err := renderTemplate(departureChangedTemplate, struct {
+		DepartureChange
+		OldDates, NewDates string
+		DatesChanged       bool
+		RouteChanged       bool
+	}{
+		DepartureChange: d,
+		OldDates:        formatDateRange(d.OldDateFrom, d.OldDateTo),
+		NewDates:        formatDateRange(d.NewDateFrom, d.NewDateTo),
+		DatesChanged:    !d.OldDateFrom.Equal(d.NewDateFrom) || !d.OldDateTo.Equal(d.NewDateTo),
+		RouteChanged:    d.OldRoute != d.NewRoute,
+	})
+	if err != nil {
+		fmt.Printf("Email template error: %v\n", err)
+		return
+	}
+	SendAsync(to, subject, body)
+}
+
+func formatDateRange(from, to time.Time) string {
+	return from.Format("02.01.2006") + " — " + to.Format("02.01.2006")
+}
+
+const departureChangedTemplate = `<!DOCTYPE html>
+<html lang="uk">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width,initial-scale=1.0"></head>
+<body style="margin:0;padding:0;background:#f1f5f9;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;">
+<table width="100%" cellpadding="0" cellspacing="0" style="background:#f1f5f9;padding:32px 16px;">
+<tr><td align="center">
+<table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
+
+<tr><td style="background:linear-gradient(135deg,#0ea5e9,#0284c7);padding:32px 40px;text-align:center;">
+  <div style="font-size:36px;margin-bottom:8px;">📅</div>
+  <h1 style="color:#ffffff;font-size:22px;font-weight:800;margin:0;">Зміни у вашому турі</h1>
+</td></tr>
+
+<tr><td style="padding:32px 40px;">
+  <p style="color:#334155;font-size:16px;line-height:1.6;margin:0 0 24px;">
+    Привіт, <strong>{{.CustomerName}}</strong>! Ми змінили деталі відправлення туру <strong>{{.TourTitle}}</strong>, на який у вас є бронювання #{{.BookingID}}.
+  </p>
+
+  <table width="100%" cellpadding="0" cellspacing="0" style="background:#f0f9ff;border:1px solid #bae6fd;border-radius:12px;margin-bottom:24px;">
+  <tr><td style="padding:20px 24px;">
+    <table width="100%" cellpadding="0" cellspacing="0">
+      {{if .DatesChanged}}
+      <tr>
+        <td style="padding:8px 0;color:#64748b;font-size:13px;font-weight:600;">Було</td>
+        <td style="padding:8px 0;color:#94a3b8;font-size:15px;text-align:right;text-decoration:line-through;">{{.OldDates}}</td>
+      </tr>
+      <tr>
+        <td style="padding:8px 0;border-top:1px solid #bae6fd;color:#64748b;font-size:13px;font-weight:600;">Стало</td>
+        <td style="padding:8px 0;border-top:1px solid #bae6fd;color:#0c4a6e;font-size:15px;font-weight:700;text-align:right;">{{.NewDates}}</td>
+      </tr>
+      {{end}}
+      {{if .RouteChanged}}
+      <tr>
+        <td style="padding:8px 0;{{if .DatesChanged}}border-top:1px solid #bae6fd;{{end}}color:#64748b;font-size:13px;font-weight:600;">Маршрут був</td>
+        <td style="padding:8px 0;{{if .DatesChanged}}border-top:1px solid #bae6fd;{{end}}color:#94a3b8;font-size:15px;text-align:right;text-decoration:line-through;">{{.OldRoute}}</td>
+      </tr>
+      <tr>
+        <td style="padding:8px 0;border-top:1px solid #bae6fd;color:#64748b;font-size:13px;font-weight:600;">Новий маршрут</td>
+        <td style="padding:8px 0;border-top:1px solid #bae6fd;color:#0c4a6e;font-size:15px;font-weight:700;text-align:right;">{{.NewRoute}}</td>
+      </tr>
+      {{end}}
+    </table>
+  </td></tr>
+  </table>
+
+  <p style="color:#64748b;font-size:14px;line-height:1.6;margin:0;">
+    Ваше бронювання залишається дійсним. Якщо нові умови вам не підходять — зв'яжіться з нами, і ми допоможемо підібрати інший варіант або скасувати бронювання.
+  </p>
+</td></tr>
+
+<tr><td style="background:#f8fafc;border-top:1px solid #e2e8f0;padding:24px 40px;text-align:center;">
+  <p style="color:#94a3b8;font-size:13px;margin:0 0 4px;">© 2026 OpenWorld — Ваш провідник у світ подорожей</p>
+  <p style="color:#94a3b8;font-size:12px;margin:0;">Цей лист відправлено автоматично.</p>
+</td></tr>
+
+</table></td></tr></table></body></html>`
@@ -0,0 +1,62 @@
+package email
+
+import (
+	"fmt"
+	"time"
+)
+
+// DepartureGuaranteed holds the data for the "your tour will run" email.
+type DepartureGuaranteed struct {
+	CustomerName string
+	BookingID    uint
+	TourTitle    string
+	DateFrom     time.Time
+	DateTo       time.Time
+}
+
+// NotifyDepartureGuaranteed tells a customer their departure reached its
+// minimum group size and will definitely run.
+func NotifyDepartureGuaranteed(to string, d DepartureGuaranteed) {
+	subject := fmt.Sprintf("✅ Тур гарантовано — %s", d.TourTitle)
+	body, err := renderTemplate(departureGuaranteedTemplate, struct {
+		DepartureGuaranteed
+		Dates string
+	}{
+		DepartureGuaranteed: d,
+		Dates:               formatDateRange(d.DateFrom, d.DateTo),
+	})
+	if err != nil {
+		fmt.Printf("Email template error: %v\n", err)
+		return
+	}
+	SendAsync(to, subject, body)
+}
+
+const departureGuaranteedTemplate = `<!DOCTYPE html>
+<html lang="uk">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width,initial-scale=1.0"></head>
+<body style="margin:0;padding:0;background:#f1f5f9;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;">
+<table width="100%" cellpadding="0" cellspacing="0" style="background:#f1f5f9;padding:32px 16px;">
+<tr><td align="center">
+<table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
+
+<tr><td style="background:linear-gradient(135deg,#22c55e,#16a34a);padding:32px 40px;text-align:center;">
+  <div style="font-size:36px;margin-bottom:8px;">✅</div>
+  <h1 style="color:#ffffff;font-size:22px;font-weight:800;margin:0;">Тур гарантовано!</h1>
+</td></tr>
+
+<tr><td style="padding:32px 40px;">
+  <p style="color:#334155;font-size:16px;line-height:1.6;margin:0 0 24px;">
+    Привіт, <strong>{{.CustomerName}}</strong>! Група на тур <strong>{{.TourTitle}}</strong> ({{.Dates}}) зібрана — відправлення відбудеться. Ваше бронювання #{{.BookingID}} в силі.
+  </p>
+  <p style="color:#64748b;font-size:14px;line-height:1.6;margin:0;">
+    Тепер можна спокійно бронювати квитки та готуватися до подорожі. До зустрічі!
+  </p>
+</td></tr>
+
+<tr><td style="background:#f8fafc;border-top:1px solid #e2e8f0;padding:24px 40px;text-align:center;">
+  <p style="color:#94a3b8;font-size:13px;margin:0 0 4px;">© 2026 OpenWorld — Ваш провідник у світ подорожей</p>
+  <p style="color:#94a3b8;font-size:12px;margin:0;">Цей лист відправлено автоматично.</p>
+</td></tr>
+
+</table></td></tr></table></body></html>`
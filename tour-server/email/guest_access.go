@@ -0,0 +1,62 @@
+package email
+
+import "fmt"
+
+// NotifyGuestAccessCode sends the one-time code for the guest
+// "find my bookings" flow.
+func NotifyGuestAccessCode(to, code string, validMinutes int) {
+	subject := fmt.Sprintf("🔑 Код доступу до бронювань: %s", code)
+	body, err := renderTemplate(guestAccessCodeTemplate, struct {
+		Code         string
+		ValidMinutes int
+	}{code, validMinutes})
+	if err != nil {
+		fmt.Printf("Email template error: %v\n", err)
+		return
+	}
+	SendAsync(to, subject, body)
+}
+
+const guestAccessCodeTemplate = `<!DOCTYPE html>
+<html lang="uk">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width,initial-scale=1.0"></head>
+<body style="margin:0;padding:0;background:#f1f5f9;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;">
+<table width="100%" cellpadding="0" cellspacing="0" style="background:#f1f5f9;padding:32px 16px;">
+<tr><td align="center">
+<table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
+
+<tr><td style="background:linear-gradient(135deg,#6366f1,#8b5cf6);padding:32px 40px;text-align:center;">
+  <div style="font-size:36px;margin-bottom:8px;">🔑</div>
+  <h1 style="color:#ffffff;font-size:22px;font-weight:800;margin:0;">Код доступу до бронювань</h1>
+</td></tr>
+
+<tr><td style="padding:32px 40px;">
+  <p style="color:#334155;font-size:16px;line-height:1.6;margin:0 0 24px;">
+    Ви запросили доступ до своїх бронювань на OpenWorld. Введіть цей код на сайті:
+  </p>
+
+  <table width="100%" cellpadding="0" cellspacing="0" style="background:#f8fafc;border:1px solid #e2e8f0;border-radius:12px;margin-bottom:24px;">
+  <tr><td align="center" style="padding:24px;">
+    <span style="color:#1e293b;font-size:36px;font-weight:900;letter-spacing:0.3em;font-family:'SF Mono',Menlo,monospace;">{{.Code}}</span>
+  </td></tr>
+  </table>
+
+  <p style="color:#475569;font-size:14px;line-height:1.6;margin:0 0 24px;">
+    Код дійсний <strong>{{.ValidMinutes}} хвилин</strong> і може бути використаний лише один раз.
+  </p>
+
+  <table width="100%" cellpadding="0" cellspacing="0" style="background:#fef3c7;border:1px solid #fde68a;border-radius:10px;">
+  <tr><td style="padding:14px 18px;">
+    <p style="color:#92400e;font-size:13px;font-weight:600;margin:0;">
+      ⚠️ Якщо ви не запитували код — просто проігноруйте цей лист. Нікому не повідомляйте код.
+    </p>
+  </td></tr>
+  </table>
+</td></tr>
+
+<tr><td style="background:#f8fafc;border-top:1px solid #e2e8f0;padding:24px 40px;text-align:center;">
+  <p style="color:#94a3b8;font-size:13px;margin:0 0 4px;">© 2026 OpenWorld — Ваш провідник у світ подорожей</p>
+  <p style="color:#94a3b8;font-size:12px;margin:0;">Цей лист відправлено автоматично.</p>
+</td></tr>
+
+</table></td></tr></table></body></html>`
@@ -0,0 +1,30 @@
+package email
+
+// ItineraryDay is one day of the tour programme as shown in emails.
+type ItineraryDay struct {
+	DayNumber   int
+	Title       string
+	Description string
+	Overnight   string // "Name, Country"; empty if not set
+	Meals       string // "сніданок, вечеря"; empty if none
+}
+
+// itineraryBlock defines the "itinerary" template used by the booking
+// confirmation emails. It renders nothing when the tour has no itinerary.
+const itineraryBlock = `{{define "itinerary"}}{{if .Itinerary}}
+  <h2 style="color:#0f172a;font-size:17px;margin:0 0 12px;">Програма туру</h2>
+  <table width="100%" cellpadding="0" cellspacing="0" style="border:1px solid #e2e8f0;border-radius:12px;margin-bottom:24px;">
+    {{range $i, $d := .Itinerary}}
+    <tr><td style="padding:14px 20px;{{if $i}}border-top:1px solid #e2e8f0;{{end}}">
+      <div style="color:#0ea5e9;font-size:12px;font-weight:700;text-transform:uppercase;letter-spacing:0.05em;">День {{$d.DayNumber}}</div>
+      <div style="color:#0f172a;font-size:15px;font-weight:700;margin:2px 0 6px;">{{$d.Title}}</div>
+      {{if $d.Description}}<div style="color:#475569;font-size:14px;line-height:1.5;">{{$d.Description}}</div>{{end}}
+      {{if or $d.Overnight $d.Meals}}
+      <div style="color:#64748b;font-size:13px;margin-top:6px;">
+        {{if $d.Overnight}}🏨 {{$d.Overnight}}{{end}}{{if and $d.Overnight $d.Meals}} · {{end}}{{if $d.Meals}}🍽 {{$d.Meals}}{{end}}
+      </div>
+      {{end}}
+    </td></tr>
+    {{end}}
+  </table>
+{{end}}{{end}}`
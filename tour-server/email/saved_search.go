@@ -0,0 +1,143 @@
+package email
+
+import (
+	"fmt"
+	"time"
+)
+
+// SavedSearchTour is one tour in a saved-search alert.
+type SavedSearchTour struct {
+	Title      string
+	URL        string
+	Price      float64
+	OldPrice   float64 // price drops only
+	Departures []SavedSearchDeparture
+}
+
+type SavedSearchDeparture struct {
+	DateFrom time.Time
+	DateTo   time.Time
+}
+
+// SavedSearchAlert holds the data for the daily saved-search email.
+type SavedSearchAlert struct {
+	UserName       string
+	SearchName     string
+	SearchURL      string
+	UnsubscribeURL string
+	NewTours       []SavedSearchTour
+	PriceDrops     []SavedSearchTour
+	NewDepartures  []SavedSearchTour // only the departures that are new
+}
+
+// alertDatesShown caps the dates listed per tour.
+const alertDatesShown = 5
+
+// NotifySavedSearchAlert sends what's new for one of the user's saved
+// searches.
+func NotifySavedSearchAlert(to string, a SavedSearchAlert) {
+	subject := fmt.Sprintf("🔔 Нове за вашим пошуком «%s»", a.SearchName)
+
+	type tourRow struct {
+		Title, URL, Price, OldPrice string
+		Dates                       []string
+		MoreDates                   int
+	}
+	rows := func(tours []SavedSearchTour) []tourRow {
+		out := make([]tourRow, 0, len(tours))
+		for _, t := range tours {
+			r := tourRow{Title: t.Title, URL: t.URL, Price: formatPrice(t.Price)}
+			if t.OldPrice > 0 {
+				r.OldPrice = formatPrice(t.OldPrice)
+			}
+			for i, d := range t.Departures {
+				if i == alertDatesShown {
+					r.MoreDates = len(t.Departures) - alertDatesShown
+					break
+				}
+				r.Dates = append(r.Dates, formatDateRange(d.DateFrom, d.DateTo))
+			}
+			out = append(out, r)
+		}
+		return out
+	}
+
+	body, err := renderTemplate(savedSearchTemplate, struct {
+		SavedSearchAlert
+		NewTourRows      []tourRow
+		PriceDropRows    []tourRow
+		NewDepartureRows []tourRow
+	}{
+		SavedSearchAlert: a,
+		NewTourRows:      rows(a.NewTours),
+		PriceDropRows:    rows(a.PriceDrops),
+		NewDepartureRows: rows(a.NewDepartures),
+	})
+	if err != nil {
+		fmt.Printf("Email template error: %v\n", err)
+		return
+	}
+	SendAsync(to, subject, body)
+}
+
+const savedSearchTemplate = `{{define "tours"}}
+  <table width="100%" cellpadding="0" cellspacing="0" style="border:1px solid #e2e8f0;border-radius:12px;margin-bottom:24px;">
+    {{range $i, $t := .}}
+    <tr><td style="padding:14px 20px;{{if $i}}border-top:1px solid #e2e8f0;{{end}}">
+      <a href="{{$t.URL}}" style="color:#0f172a;font-size:15px;font-weight:700;text-decoration:none;">{{$t.Title}}</a>
+      <div style="margin-top:4px;font-size:14px;">
+        {{if $t.OldPrice}}<span style="color:#94a3b8;text-decoration:line-through;">{{$t.OldPrice}}</span> {{end}}
+        <span style="color:#16a34a;font-weight:700;">{{$t.Price}}</span>
+      </div>
+      {{range $t.Dates}}<div style="color:#64748b;font-size:13px;margin-top:2px;">📅 {{.}}</div>{{end}}
+      {{if $t.MoreDates}}<div style="color:#64748b;font-size:13px;margin-top:2px;">і ще {{$t.MoreDates}}</div>{{end}}
+    </td></tr>
+    {{end}}
+  </table>
+{{end}}<!DOCTYPE html>
+<html lang="uk">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width,initial-scale=1.0"></head>
+<body style="margin:0;padding:0;background:#f1f5f9;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;">
+<table width="100%" cellpadding="0" cellspacing="0" style="background:#f1f5f9;padding:32px 16px;">
+<tr><td align="center">
+<table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
+
+<tr><td style="background:linear-gradient(135deg,#0ea5e9,#6366f1);padding:32px 40px;text-align:center;">
+  <div style="font-size:36px;margin-bottom:8px;">🔔</div>
+  <h1 style="color:#ffffff;font-size:22px;font-weight:800;margin:0;">Нове за вашим пошуком</h1>
+</td></tr>
+
+<tr><td style="padding:32px 40px;">
+  <p style="color:#334155;font-size:16px;line-height:1.6;margin:0 0 24px;">
+    Привіт, <strong>{{.UserName}}</strong>! Ось що з'явилося за пошуком <strong>«{{.SearchName}}»</strong> від минулого листа.
+  </p>
+
+  {{if .NewTourRows}}
+  <h2 style="color:#0f172a;font-size:17px;margin:0 0 12px;">Нові тури</h2>
+  {{template "tours" .NewTourRows}}
+  {{end}}
+
+  {{if .PriceDropRows}}
+  <h2 style="color:#0f172a;font-size:17px;margin:0 0 12px;">Ціна знизилась</h2>
+  {{template "tours" .PriceDropRows}}
+  {{end}}
+
+  {{if .NewDepartureRows}}
+  <h2 style="color:#0f172a;font-size:17px;margin:0 0 12px;">Нові дати</h2>
+  {{template "tours" .NewDepartureRows}}
+  {{end}}
+
+  <div style="text-align:center;margin-bottom:8px;">
+    <a href="{{.SearchURL}}" style="display:inline-block;background:#0ea5e9;color:#ffffff;text-decoration:none;font-weight:700;font-size:15px;padding:14px 32px;border-radius:10px;">Відкрити пошук</a>
+  </div>
+</td></tr>
+
+<tr><td style="background:#f8fafc;border-top:1px solid #e2e8f0;padding:24px 40px;text-align:center;">
+  <p style="color:#94a3b8;font-size:13px;margin:0 0 4px;">© 2026 OpenWorld — Ваш провідник у світ подорожей</p>
+  <p style="color:#94a3b8;font-size:12px;margin:0;">
+    Ви отримали цей лист, бо зберегли пошук на сайті.
+    <a href="{{.UnsubscribeURL}}" style="color:#94a3b8;">Відписатися від цього пошуку</a>
+  </p>
+</td></tr>
+
+</table></td></tr></table></body></html>`
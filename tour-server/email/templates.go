@@ -11,6 +11,7 @@ type BookingNotification struct {
 	BookingID    uint
 	Status       string // "confirmed", "cancelled", "pending", "paid"
 	PaymentURL   string // optional magic-link to resume payment (guest bookings)
+	Itinerary    []ItineraryDay
 }
 
 // NotifyBookingConfirmed sends email when booking is confirmed by admin.
@@ -65,6 +66,7 @@ type tmplData struct {
 	BookingID    uint
 	SeatsWord    string
 	PaymentURL   string
+	Itinerary    []ItineraryDay
 }
 
 func templateData(n BookingNotification) tmplData {
@@ -82,6 +84,7 @@ func templateData(n BookingNotification) tmplData {
 		BookingID:    n.BookingID,
 		SeatsWord:    word,
 		PaymentURL:   n.PaymentURL,
+		Itinerary:    n.Itinerary,
 	}
 }
 
@@ -189,6 +192,8 @@ const confirmedTemplate = `<!DOCTYPE html>
   </td></tr>
   </table>
 
+  {{template "itinerary" .}}
+
   <p style="color:#64748b;font-size:14px;line-height:1.6;margin:0;">
     Якщо у вас є питання — зверніться до нашої служби підтримки.
   </p>
@@ -199,7 +204,7 @@ const confirmedTemplate = `<!DOCTYPE html>
   <p style="color:#94a3b8;font-size:12px;margin:0;">Цей лист відправлено автоматично.</p>
 </td></tr>
 
-</table></td></tr></table></body></html>`
+</table></td></tr></table></body></html>` + itineraryBlock
 
 const cancelledTemplate = `<!DOCTYPE html>
 <html lang="uk">
@@ -301,6 +306,8 @@ const paymentReceivedTemplate = `<!DOCTYPE html>
   </td></tr>
   </table>
 
+  {{template "itinerary" .}}
+
   <p style="color:#64748b;font-size:14px;line-height:1.6;margin:0;">
     Менеджер зв'яжеться з вами для уточнення деталей подорожі. Дякуємо за довіру!
   </p>
@@ -311,7 +318,7 @@ const paymentReceivedTemplate = `<!DOCTYPE html>
   <p style="color:#94a3b8;font-size:12px;margin:0;">Цей лист відправлено автоматично.</p>
 </td></tr>
 
-</table></td></tr></table></body></html>`
+</table></td></tr></table></body></html>` + itineraryBlock
 
 const createdTemplate = `<!DOCTYPE html>
 <html lang="uk">
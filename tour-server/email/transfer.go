@@ -0,0 +1,181 @@
+package email
+
+import (
+	"fmt"
+	"time"
+)
+
+// TransferNotification holds the data for booking-transfer emails.
+type TransferNotification struct {
+	BookingID  uint
+	TourTitle  string
+	DateFrom   time.Time
+	Seats      int
+	FromName   string
+	ToName     string
+	AcceptURL  string    // invite only
+	ExpiresAt  time.Time // invite only
+	PaymentURL string    // new owner, guests only
+}
+
+// NotifyTransferInvite asks the recipient to accept a transferred booking.
+func NotifyTransferInvite(to string, n TransferNotification) {
+	subject := fmt.Sprintf("🎁 %s передає вам бронювання — %s", n.FromName, n.TourTitle)
+	sendTransferEmail(to, subject, transferEmail{
+		Color1:     "#6366f1",
+		Color2:     "#8b5cf6",
+		Icon:       "🎁",
+		Title:      "Вам передають бронювання",
+		Greeting:   n.ToName,
+		Message:    fmt.Sprintf("%s хоче передати вам своє бронювання. Прийміть його, щоб місця стали вашими.", n.FromName),
+		ButtonURL:  n.AcceptURL,
+		ButtonText: "Прийняти бронювання",
+		Note:       fmt.Sprintf("Запрошення дійсне до %s.", n.ExpiresAt.Format("02.01.2006 15:04")),
+	}, n)
+}
+
+// NotifyTransferRequested confirms to the current owner that an invite went out.
+func NotifyTransferRequested(to string, n TransferNotification) {
+	subject := fmt.Sprintf("📨 Запрошення на передачу бронювання #%d надіслано", n.BookingID)
+	sendTransferEmail(to, subject, transferEmail{
+		Color1:   "#f59e0b",
+		Color2:   "#d97706",
+		Icon:     "📨",
+		Title:    "Запрошення надіслано",
+		Greeting: n.FromName,
+		Message:  fmt.Sprintf("Ми надіслали %s запрошення прийняти ваше бронювання. Поки його не прийнято, бронювання залишається вашим.", n.ToName),
+	}, n)
+}
+
+// NotifyTransferCompleted tells the previous owner the booking is no longer theirs.
+func NotifyTransferCompleted(to string, n TransferNotification) {
+	subject := fmt.Sprintf("🔁 Бронювання #%d передано — %s", n.BookingID, n.TourTitle)
+	sendTransferEmail(to, subject, transferEmail{
+		Color1:   "#64748b",
+		Color2:   "#475569",
+		Icon:     "🔁",
+		Title:    "Бронювання передано",
+		Greeting: n.FromName,
+		Message:  fmt.Sprintf("%s прийняв(ла) ваше бронювання. Старі посилання на нього більше не діють.", n.ToName),
+	}, n)
+}
+
+// NotifyTransferReceived welcomes the new owner.
+func NotifyTransferReceived(to string, n TransferNotification) {
+	subject := fmt.Sprintf("✅ Бронювання #%d тепер ваше — %s", n.BookingID, n.TourTitle)
+	e := transferEmail{
+		Color1:   "#10b981",
+		Color2:   "#059669",
+		Icon:     "✅",
+		Title:    "Бронювання тепер ваше",
+		Greeting: n.ToName,
+		Message:  "Бронювання успішно передано вам.",
+	}
+	if n.PaymentURL != "" {
+		e.ButtonURL = n.PaymentURL
+		e.ButtonText = "Переглянути бронювання"
+		e.Note = "За цим посиланням можна оплатити або скасувати бронювання. Діє 7 днів."
+	} else {
+		e.Note = "Бронювання доступне в особистому кабінеті на сайті."
+	}
+	sendTransferEmail(to, subject, e, n)
+}
+
+type transferEmail struct {
+	Color1, Color2 string
+	Icon, Title    string
+	Greeting       string
+	Message        string
+	ButtonURL      string
+	ButtonText     string
+	Note           string
+
+	BookingID uint
+	TourTitle string
+	Date      string
+	Seats     int
+	SeatsWord string
+}
+
+func sendTransferEmail(to, subject string, e transferEmail, n TransferNotification) {
+	td := templateData(BookingNotification{Seats: n.Seats})
+	e.BookingID = n.BookingID
+	e.TourTitle = n.TourTitle
+	e.Seats = n.Seats
+	e.SeatsWord = td.SeatsWord
+	if !n.DateFrom.IsZero() {
+		e.Date = n.DateFrom.Format("02.01.2006")
+	}
+
+	body, err := renderTemplate(transferTemplate, e)
+	if err != nil {
+		fmt.Printf("Email template error: %v\n", err)
+		return
+	}
+	SendAsync(to, subject, body)
+}
+
+const transferTemplate = `<!DOCTYPE html>
+<html lang="uk">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width,initial-scale=1.0"></head>
+<body style="margin:0;padding:0;background:#f1f5f9;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;">
+<table width="100%" cellpadding="0" cellspacing="0" style="background:#f1f5f9;padding:32px 16px;">
+<tr><td align="center">
+<table width="600" cellpadding="0" cellspacing="0" style="background:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
+
+<tr><td style="background:linear-gradient(135deg,{{.Color1}},{{.Color2}});padding:32px 40px;text-align:center;">
+  <div style="font-size:36px;margin-bottom:8px;">{{.Icon}}</div>
+  <h1 style="color:#ffffff;font-size:22px;font-weight:800;margin:0;">{{.Title}}</h1>
+</td></tr>
+
+<tr><td style="padding:32px 40px;">
+  <p style="color:#334155;font-size:16px;line-height:1.6;margin:0 0 24px;">
+    Привіт, <strong>{{.Greeting}}</strong>! {{.Message}}
+  </p>
+
+  <table width="100%" cellpadding="0" cellspacing="0" style="background:#f8fafc;border:1px solid #e2e8f0;border-radius:12px;margin-bottom:24px;">
+  <tr><td style="padding:20px 24px;">
+    <table width="100%" cellpadding="0" cellspacing="0">
+      <tr>
+        <td style="padding:8px 0;color:#64748b;font-size:13px;font-weight:600;">Тур</td>
+        <td style="padding:8px 0;color:#1e293b;font-size:15px;font-weight:700;text-align:right;">{{.TourTitle}}</td>
+      </tr>
+      {{if .Date}}
+      <tr>
+        <td style="padding:8px 0;border-top:1px solid #e2e8f0;color:#64748b;font-size:13px;font-weight:600;">Відправлення</td>
+        <td style="padding:8px 0;border-top:1px solid #e2e8f0;color:#1e293b;font-size:15px;font-weight:700;text-align:right;">{{.Date}}</td>
+      </tr>
+      {{end}}
+      <tr>
+        <td style="padding:8px 0;border-top:1px solid #e2e8f0;color:#64748b;font-size:13px;font-weight:600;">Бронювання</td>
+        <td style="padding:8px 0;border-top:1px solid #e2e8f0;color:#1e293b;font-size:15px;font-weight:700;text-align:right;">#{{.BookingID}}</td>
+      </tr>
+      <tr>
+        <td style="padding:8px 0;border-top:1px solid #e2e8f0;color:#64748b;font-size:13px;font-weight:600;">Кількість</td>
+        <td style="padding:8px 0;border-top:1px solid #e2e8f0;color:#1e293b;font-size:15px;font-weight:700;text-align:right;">{{.Seats}} {{.SeatsWord}}</td>
+      </tr>
+    </table>
+  </td></tr>
+  </table>
+
+  {{if .ButtonURL}}
+  <table width="100%" cellpadding="0" cellspacing="0" style="margin-bottom:16px;">
+  <tr><td align="center" style="padding:8px 0 4px;">
+    <a href="{{.ButtonURL}}" style="display:inline-block;background:linear-gradient(135deg,#6366f1,#8b5cf6);color:#fff;text-decoration:none;font-weight:700;font-size:15px;padding:14px 32px;border-radius:10px;box-shadow:0 4px 12px rgba(99,102,241,0.3);">
+      {{.ButtonText}}
+    </a>
+  </td></tr>
+  </table>
+  {{end}}
+
+  {{if .Note}}
+  <p style="color:#94a3b8;font-size:12px;line-height:1.6;margin:0;text-align:center;">{{.Note}}</p>
+  {{end}}
+</td></tr>
+
+<tr><td style="background:#f8fafc;border-top:1px solid #e2e8f0;padding:24px 40px;text-align:center;">
+  <p style="color:#94a3b8;font-size:13px;margin:0 0 4px;">© 2026 OpenWorld — Ваш провідник у світ подорожей</p>
+  <p style="color:#94a3b8;font-size:12px;margin:0;">Цей лист відправлено автоматично.</p>
+</td></tr>
+
+</table></td></tr></table></body></html>`
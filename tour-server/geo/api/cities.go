@@ -0,0 +1,289 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tour-server/geo"
+	"tour-server/geo/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CityRequest struct {
+	CountryID *uint    `json:"country_id"`
+	Name      *string  `json:"name"`
+	NameEn    *string  `json:"name_en"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// CityItem keeps the id/name/country fields the old GET /admin/locations
+// returned, so the tour editor's location pickers work unchanged.
+type CityItem struct {
+	models.City
+	Country    string  `json:"country" gorm:"column:country"`
+	CountryISO *string `json:"country_iso" gorm:"column:country_iso"`
+	RegionID   *uint   `json:"region_id" gorm:"column:region_id"`
+	DatesCount int64   `json:"dates_count" gorm:"column:dates_count"`
+}
+
+// validateCity trims and checks the fields that are present.
+func validateCity(req *CityRequest) string {
+	var msg string
+	if req.Name, msg = validateName(req.Name, "name"); msg != "" {
+		return msg
+	}
+	if req.CountryID != nil && *req.CountryID == 0 {
+		return "country_id is required"
+	}
+	if (req.Latitude != nil || req.Longitude != nil) && !geo.ValidCoordinates(req.Latitude, req.Longitude) {
+		return "latitude and longitude must be set together and in range"
+	}
+	return ""
+}
+
+// GET /admin/cities?country_id=1 (also served as GET /admin/locations)
+func GetAdminCities(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		where := "TRUE"
+		var args []interface{}
+		if countryID, err := strconv.Atoi(c.QueryParam("country_id")); err == nil && countryID > 0 {
+			where = "ci.country_id = ?"
+			args = append(args, countryID)
+		}
+
+		var items []CityItem
+		err := db.Raw(`
+			SELECT ci.*, co.name AS country, co.iso_code AS country_iso, co.region_id,
+				(SELECT COUNT(*) FROM tour_dates td
+				 WHERE td.from_location_id = ci.id OR td.to_location_id = ci.id) AS dates_count
+			FROM cities ci
+			JOIN countries co ON co.id = ci.country_id
+			WHERE `+where+`
+			ORDER BY co.name, ci.name
+		`, args...).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch locations",
+			})
+		}
+
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+// GET /departure-cities
+// Cities that upcoming departures of active tours leave from, for the
+// search page's "from" filter.
+func GetDepartureCities(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var items []struct {
+			ID         uint   `json:"id" gorm:"column:id"`
+			Name       string `json:"name" gorm:"column:name"`
+			NameEn     string `json:"name_en" gorm:"column:name_en"`
+			Country    string `json:"country" gorm:"column:country"`
+			ToursCount int64  `json:"tours_count" gorm:"column:tours_count"`
+		}
+		err := db.Raw(`
+			SELECT ci.id, ci.name, ci.name_en, co.name AS country,
+				COUNT(DISTINCT t.id) AS tours_count
+			FROM cities ci
+			JOIN countries co ON co.id = ci.country_id
+			JOIN tour_dates td ON td.from_location_id = ci.id
+				AND NOT td.is_retired AND td.date_from > NOW()
+			JOIN tours t ON t.id = td.tour_id
+				AND t.status_id = (SELECT id FROM statuses WHERE name = 'active')
+			GROUP BY ci.id, co.name
+			ORDER BY tours_count DESC, ci.name
+		`).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch departure cities",
+			})
+		}
+
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+// cityConflict reports whether the country already has a city with this name.
+func cityConflict(db *gorm.DB, countryID uint, name string, id int) bool {
+	var exists int64
+	db.Table("cities").Where("country_id = ? AND name = ? AND id <> ?", countryID, name, id).Count(&exists)
+	return exists > 0
+}
+
+// POST /admin/cities
+func CreateCity(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CityRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.Name == nil || req.CountryID == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "name and country_id are required",
+			})
+		}
+		if msg := validateCity(&req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		var countryExists int64
+		db.Table("countries").Where("id = ?", *req.CountryID).Count(&countryExists)
+		if countryExists == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Unknown country",
+			})
+		}
+		if cityConflict(db, *req.CountryID, *req.Name, 0) {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "City with this name already exists in the country",
+			})
+		}
+
+		city := models.City{
+			CountryID: *req.CountryID,
+			Name:      *req.Name,
+			Latitude:  req.Latitude,
+			Longitude: req.Longitude,
+		}
+		if req.NameEn != nil {
+			city.NameEn = strings.TrimSpace(*req.NameEn)
+		}
+		if err := db.Create(&city).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create city",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message": "City created",
+			"city":    city,
+		})
+	}
+}
+
+// PUT /admin/cities/:id
+func UpdateCity(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid city ID",
+			})
+		}
+
+		var req CityRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if msg := validateCity(&req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		var current models.City
+		db.Raw("SELECT * FROM cities WHERE id = ?", id).Scan(&current)
+		if current.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "City not found",
+			})
+		}
+
+		updates := map[string]interface{}{"updated_at": time.Now()}
+		countryID, name := current.CountryID, current.Name
+		if req.CountryID != nil {
+			var countryExists int64
+			db.Table("countries").Where("id = ?", *req.CountryID).Count(&countryExists)
+			if countryExists == 0 {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Unknown country",
+				})
+			}
+			countryID = *req.CountryID
+			updates["country_id"] = countryID
+		}
+		if req.Name != nil {
+			name = *req.Name
+			updates["name"] = name
+		}
+		if cityConflict(db, countryID, name, id) {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "City with this name already exists in the country",
+			})
+		}
+		if req.NameEn != nil {
+			updates["name_en"] = strings.TrimSpace(*req.NameEn)
+		}
+		if req.Latitude != nil {
+			updates["latitude"] = *req.Latitude
+			updates["longitude"] = *req.Longitude
+		}
+
+		if err := db.Table("cities").Where("id = ?", id).Updates(updates).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update city",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "City updated",
+		})
+	}
+}
+
+// DELETE /admin/cities/:id
+// Cities used by any departure can't be deleted.
+func DeleteCity(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid city ID",
+			})
+		}
+
+		var used int64
+		db.Table("tour_dates").Where("from_location_id = ? OR to_location_id = ?", id, id).Count(&used)
+		if used > 0 {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "City is used by tour departures",
+			})
+		}
+
+		db.Table("departure_series").Where("from_location_id = ? OR to_location_id = ?", id, id).Count(&used)
+		if used > 0 {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "City is used by departure series",
+			})
+		}
+
+		result := db.Exec("DELETE FROM cities WHERE id = ?", id)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete city",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "City not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "City deleted",
+		})
+	}
+}
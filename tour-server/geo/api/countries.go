@@ -0,0 +1,254 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tour-server/geo"
+	"tour-server/geo/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CountryRequest struct {
+	RegionID  *uint    `json:"region_id"` // 0 unassigns
+	ISOCode   *string  `json:"iso_code"`
+	Name      *string  `json:"name"`
+	NameEn    *string  `json:"name_en"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+type CountryItem struct {
+	models.Country
+	RegionName  string `json:"region_name" gorm:"column:region_name"`
+	CitiesCount int64  `json:"cities_count" gorm:"column:cities_count"`
+}
+
+// validateCountry trims and checks the fields that are present.
+func validateCountry(req *CountryRequest) string {
+	var msg string
+	if req.Name, msg = validateName(req.Name, "name"); msg != "" {
+		return msg
+	}
+	if req.ISOCode != nil {
+		code := strings.ToUpper(strings.TrimSpace(*req.ISOCode))
+		if !geo.ValidISOCode(code) {
+			return "iso_code must be a two-letter ISO 3166-1 code"
+		}
+		req.ISOCode = &code
+	}
+	if (req.Latitude != nil || req.Longitude != nil) && !geo.ValidCoordinates(req.Latitude, req.Longitude) {
+		return "latitude and longitude must be set together and in range"
+	}
+	return ""
+}
+
+// countryConflict returns an error message if another country already
+// uses the name or ISO code.
+func countryConflict(db *gorm.DB, req CountryRequest, id int) string {
+	var exists int64
+	if req.Name != nil {
+		db.Table("countries").Where("name = ? AND id <> ?", *req.Name, id).Count(&exists)
+		if exists > 0 {
+			return "Country with this name already exists"
+		}
+	}
+	if req.ISOCode != nil {
+		db.Table("countries").Where("iso_code = ? AND id <> ?", *req.ISOCode, id).Count(&exists)
+		if exists > 0 {
+			return "Country with this ISO code already exists"
+		}
+	}
+	return ""
+}
+
+func regionOrNil(id *uint) interface{} {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return *id
+}
+
+// GET /admin/countries?region_id=2
+func GetAdminCountries(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		where := "TRUE"
+		var args []interface{}
+		if regionID, err := strconv.Atoi(c.QueryParam("region_id")); err == nil && regionID > 0 {
+			where = "co.region_id = ?"
+			args = append(args, regionID)
+		}
+
+		var items []CountryItem
+		err := db.Raw(`
+			SELECT co.*, COALESCE(r.name, '') AS region_name, COUNT(ci.id) AS cities_count
+			FROM countries co
+			LEFT JOIN regions r ON r.id = co.region_id
+			LEFT JOIN cities ci ON ci.country_id = co.id
+			WHERE `+where+`
+			GROUP BY co.id, r.name
+			ORDER BY co.name
+		`, args...).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch countries",
+			})
+		}
+
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+// POST /admin/countries
+func CreateCountry(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CountryRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.Name == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "name is required",
+			})
+		}
+		if msg := validateCountry(&req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+		if msg := countryConflict(db, req, 0); msg != "" {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": msg,
+			})
+		}
+
+		country := models.Country{
+			ISOCode:   req.ISOCode,
+			Name:      *req.Name,
+			Latitude:  req.Latitude,
+			Longitude: req.Longitude,
+		}
+		if req.RegionID != nil && *req.RegionID != 0 {
+			country.RegionID = req.RegionID
+		}
+		if req.NameEn != nil {
+			country.NameEn = strings.TrimSpace(*req.NameEn)
+		}
+		if err := db.Create(&country).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create country",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message": "Country created",
+			"country": country,
+		})
+	}
+}
+
+// PUT /admin/countries/:id
+func UpdateCountry(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid country ID",
+			})
+		}
+
+		var req CountryRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if msg := validateCountry(&req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+		if msg := countryConflict(db, req, id); msg != "" {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": msg,
+			})
+		}
+
+		updates := map[string]interface{}{"updated_at": time.Now()}
+		if req.RegionID != nil {
+			updates["region_id"] = regionOrNil(req.RegionID)
+		}
+		if req.ISOCode != nil {
+			updates["iso_code"] = *req.ISOCode
+		}
+		if req.Name != nil {
+			updates["name"] = *req.Name
+		}
+		if req.NameEn != nil {
+			updates["name_en"] = strings.TrimSpace(*req.NameEn)
+		}
+		if req.Latitude != nil {
+			updates["latitude"] = *req.Latitude
+			updates["longitude"] = *req.Longitude
+		}
+
+		result := db.Table("countries").Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update country",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Country not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Country updated",
+		})
+	}
+}
+
+// DELETE /admin/countries/:id
+// Only countries without cities can be deleted.
+func DeleteCountry(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid country ID",
+			})
+		}
+
+		var cities int64
+		db.Table("cities").Where("country_id = ?", id).Count(&cities)
+		if cities > 0 {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "Country still has cities",
+			})
+		}
+
+		result := db.Exec("DELETE FROM countries WHERE id = ?", id)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete country",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Country not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Country deleted",
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestValidateCountry(t *testing.T) {
+	name, iso := " Франція ", "fr"
+	req := CountryRequest{Name: &name, ISOCode: &iso}
+	if msg := validateCountry(&req); msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if *req.Name != "Франція" || *req.ISOCode != "FR" {
+		t.Errorf("expected normalized fields, got %q %q", *req.Name, *req.ISOCode)
+	}
+
+	bad := "FRA"
+	if msg := validateCountry(&CountryRequest{ISOCode: &bad}); msg == "" {
+		t.Error("expected error for a three-letter code")
+	}
+	lat := 46.2
+	if msg := validateCountry(&CountryRequest{Latitude: &lat}); msg == "" {
+		t.Error("expected error for latitude without longitude")
+	}
+}
+
+func TestCreateCity_MissingCountry(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cities", strings.NewReader(`{"name": "Париж"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := CreateCity(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestUpdateCity_InvalidCoordinates(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/cities/1", strings.NewReader(`{"latitude": 120, "longitude": 30}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := UpdateCity(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestDeleteRegion_InvalidID(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/regions/abc", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+
+	handler := DeleteRegion(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
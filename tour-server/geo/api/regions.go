@@ -0,0 +1,221 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tour-server/geo/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type RegionRequest struct {
+	Name      *string `json:"name"`
+	NameEn    *string `json:"name_en"`
+	SortOrder *int    `json:"sort_order"`
+}
+
+type RegionCountry struct {
+	ID         uint    `json:"id" gorm:"column:id"`
+	RegionID   uint    `json:"-" gorm:"column:region_id"`
+	ISOCode    *string `json:"iso_code" gorm:"column:iso_code"`
+	Name       string  `json:"name" gorm:"column:name"`
+	NameEn     string  `json:"name_en" gorm:"column:name_en"`
+	ToursCount int64   `json:"tours_count" gorm:"column:tours_count"`
+}
+
+type RegionTree struct {
+	models.Region
+	Countries []RegionCountry `json:"countries" gorm:"-"`
+}
+
+// validateName trims a required name field if present.
+func validateName(name *string, field string) (*string, string) {
+	if name == nil {
+		return nil, ""
+	}
+	n := strings.TrimSpace(*name)
+	if n == "" || len(n) > 100 {
+		return nil, field + " is required (max 100 characters)"
+	}
+	return &n, ""
+}
+
+// GET /regions
+// Regions with their countries and the number of active tours going to
+// each, for the search filter.
+func GetRegions(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var regions []RegionTree
+		if err := db.Raw("SELECT * FROM regions ORDER BY sort_order, id").Scan(&regions).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch regions",
+			})
+		}
+
+		var countries []RegionCountry
+		err := db.Raw(`
+			SELECT co.id, co.region_id, co.iso_code, co.name, co.name_en,
+				COUNT(DISTINCT t.id) AS tours_count
+			FROM countries co
+			LEFT JOIN cities ci ON ci.country_id = co.id
+			LEFT JOIN tour_dates td ON td.to_location_id = ci.id AND NOT td.is_retired
+			LEFT JOIN tours t ON t.id = td.tour_id
+				AND t.status_id = (SELECT id FROM statuses WHERE name = 'active')
+			WHERE co.region_id IS NOT NULL
+			GROUP BY co.id
+			ORDER BY co.name
+		`).Scan(&countries).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch countries",
+			})
+		}
+
+		byRegion := map[uint][]RegionCountry{}
+		for _, co := range countries {
+			byRegion[co.RegionID] = append(byRegion[co.RegionID], co)
+		}
+		for i := range regions {
+			regions[i].Countries = byRegion[regions[i].ID]
+			if regions[i].Countries == nil {
+				regions[i].Countries = []RegionCountry{}
+			}
+		}
+
+		return c.JSON(http.StatusOK, regions)
+	}
+}
+
+// POST /admin/regions
+func CreateRegion(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req RegionRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.Name == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "name is required",
+			})
+		}
+		name, msg := validateName(req.Name, "name")
+		if msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		region := models.Region{Name: *name}
+		if req.NameEn != nil {
+			region.NameEn = strings.TrimSpace(*req.NameEn)
+		}
+		if req.SortOrder != nil {
+			region.SortOrder = *req.SortOrder
+		}
+		if err := db.Create(&region).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create region",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message": "Region created",
+			"region":  region,
+		})
+	}
+}
+
+// PUT /admin/regions/:id
+func UpdateRegion(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid region ID",
+			})
+		}
+
+		var req RegionRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		name, msg := validateName(req.Name, "name")
+		if msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		updates := map[string]interface{}{"updated_at": time.Now()}
+		if name != nil {
+			updates["name"] = *name
+		}
+		if req.NameEn != nil {
+			updates["name_en"] = strings.TrimSpace(*req.NameEn)
+		}
+		if req.SortOrder != nil {
+			updates["sort_order"] = *req.SortOrder
+		}
+
+		result := db.Table("regions").Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update region",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Region not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Region updated",
+		})
+	}
+}
+
+// DELETE /admin/regions/:id
+// Only empty regions can be deleted; move their countries first.
+func DeleteRegion(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid region ID",
+			})
+		}
+
+		var countries int64
+		db.Table("countries").Where("region_id = ?", id).Count(&countries)
+		if countries > 0 {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "Region still has countries",
+			})
+		}
+
+		result := db.Exec("DELETE FROM regions WHERE id = ?", id)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete region",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Region not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Region deleted",
+		})
+	}
+}
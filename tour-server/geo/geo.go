@@ -0,0 +1,21 @@
+// Package geo holds the regions → countries → cities hierarchy that tour
+// departures and destinations point at.
+package geo
+
+import "regexp"
+
+var isoRe = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// ValidISOCode reports whether s is an upper-case ISO 3166-1 alpha-2 code.
+func ValidISOCode(s string) bool {
+	return isoRe.MatchString(s)
+}
+
+// ValidCoordinates reports whether lat/lng are both set and in range, or
+// both unset.
+func ValidCoordinates(lat, lng *float64) bool {
+	if lat == nil || lng == nil {
+		return lat == nil && lng == nil
+	}
+	return *lat >= -90 && *lat <= 90 && *lng >= -180 && *lng <= 180
+}
@@ -0,0 +1,33 @@
+package geo
+
+import "testing"
+
+func TestValidISOCode(t *testing.T) {
+	for _, s := range []string{"UA", "FR", "US"} {
+		if !ValidISOCode(s) {
+			t.Errorf("expected %q to be valid", s)
+		}
+	}
+	for _, s := range []string{"", "ua", "UKR", "U1"} {
+		if ValidISOCode(s) {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestValidCoordinates(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	if !ValidCoordinates(nil, nil) {
+		t.Error("expected unset coordinates to be valid")
+	}
+	if !ValidCoordinates(f(50.45), f(30.52)) {
+		t.Error("expected Kyiv to be valid")
+	}
+	if ValidCoordinates(f(50.45), nil) {
+		t.Error("expected latitude without longitude to be invalid")
+	}
+	if ValidCoordinates(f(91), f(0)) || ValidCoordinates(f(0), f(-181)) {
+		t.Error("expected out-of-range coordinates to be invalid")
+	}
+}
@@ -0,0 +1,131 @@
+-- Migration: managed geography (regions → countries → cities)
+-- Replaces the free-text locations(name, country) table and the region map
+-- hardcoded in search. The old locations table becomes cities (ids and the
+-- tour_dates / itinerary foreign keys are kept), and a read-only
+-- `locations` view keeps the id/name/country shape for existing queries.
+-- Run after migration_catchup.sql. Later migrations that reference a city
+-- (touritinerary, tourdate/migration_series) point at cities(id) and must
+-- run after this one; a view can't be a foreign key target.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS regions (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    name_en     VARCHAR(100) NOT NULL DEFAULT '',
+    sort_order  INTEGER NOT NULL DEFAULT 0,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS countries (
+    id          SERIAL PRIMARY KEY,
+    region_id   INTEGER REFERENCES regions(id) ON DELETE SET NULL,
+    iso_code    CHAR(2) UNIQUE,                  -- ISO 3166-1 alpha-2
+    name        VARCHAR(100) NOT NULL UNIQUE,
+    name_en     VARCHAR(100) NOT NULL DEFAULT '',
+    latitude    DOUBLE PRECISION,
+    longitude   DOUBLE PRECISION,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_countries_region ON countries(region_id);
+
+-- Region ids match the ones the search page already sends (region=1..6).
+INSERT INTO regions (id, name, name_en, sort_order) VALUES
+    (1, 'Україна', 'Ukraine', 1),
+    (2, 'Європа', 'Europe', 2),
+    (3, 'Азія', 'Asia', 3),
+    (4, 'Америка', 'Americas', 4),
+    (5, 'Близький Схід', 'Middle East', 5),
+    (6, 'Океанія', 'Oceania', 6)
+ON CONFLICT (id) DO NOTHING;
+SELECT setval('regions_id_seq', GREATEST((SELECT MAX(id) FROM regions), 1));
+
+INSERT INTO countries (region_id, iso_code, name, name_en, latitude, longitude) VALUES
+    (1, 'UA', 'Україна', 'Ukraine', 48.38, 31.17),
+    (2, 'FR', 'Франція', 'France', 46.23, 2.21),
+    (2, 'IT', 'Італія', 'Italy', 41.87, 12.57),
+    (2, 'ES', 'Іспанія', 'Spain', 40.46, -3.75),
+    (2, 'DE', 'Німеччина', 'Germany', 51.17, 10.45),
+    (2, 'PL', 'Польща', 'Poland', 51.92, 19.15),
+    (2, 'NL', 'Нідерланди', 'Netherlands', 52.13, 5.29),
+    (2, 'GR', 'Греція', 'Greece', 39.07, 21.82),
+    (3, 'CN', 'Китай', 'China', 35.86, 104.20),
+    (3, 'JP', 'Японія', 'Japan', 36.20, 138.25),
+    (3, 'TH', 'Таїланд', 'Thailand', 15.87, 100.99),
+    (3, 'TW', 'Тайвань', 'Taiwan', 23.70, 120.96),
+    (3, 'IN', 'Індія', 'India', 20.59, 78.96),
+    (3, 'SG', 'Сінгапур', 'Singapore', 1.35, 103.82),
+    (3, 'MV', 'Мальдіви', 'Maldives', 3.20, 73.22),
+    (4, 'US', 'США', 'United States', 37.09, -95.71),
+    (4, 'CA', 'Канада', 'Canada', 56.13, -106.35),
+    (4, 'MX', 'Мексика', 'Mexico', 23.63, -102.55),
+    (4, 'BR', 'Бразилія', 'Brazil', -14.24, -51.93),
+    (5, 'AE', 'ОАЕ', 'United Arab Emirates', 23.42, 53.85),
+    (5, 'TR', 'Туреччина', 'Turkey', 38.96, 35.24),
+    (5, 'EG', 'Єгипет', 'Egypt', 26.82, 30.80),
+    (5, 'IL', 'Ізраїль', 'Israel', 31.05, 34.85),
+    (6, 'AU', 'Австралія', 'Australia', -25.27, 133.78),
+    (6, 'NZ', 'Нова Зеландія', 'New Zealand', -40.90, 174.89)
+ON CONFLICT (name) DO NOTHING;
+
+-- locations → cities, once
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_class WHERE relname = 'locations' AND relkind = 'r') THEN
+        ALTER TABLE locations RENAME TO cities;
+        ALTER SEQUENCE IF EXISTS locations_id_seq RENAME TO cities_id_seq;
+
+        ALTER TABLE cities
+            ADD COLUMN country_id  INTEGER REFERENCES countries(id) ON DELETE RESTRICT,
+            ADD COLUMN name_en     VARCHAR(100) NOT NULL DEFAULT '',
+            ADD COLUMN latitude    DOUBLE PRECISION,
+            ADD COLUMN longitude   DOUBLE PRECISION,
+            ADD COLUMN created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+            ADD COLUMN updated_at  TIMESTAMP NOT NULL DEFAULT NOW();
+
+        -- Countries typed in free text that the seed above doesn't know
+        -- get a bare row (no region / ISO code) for an admin to complete.
+        INSERT INTO countries (name)
+        SELECT DISTINCT ci.country FROM cities ci
+        WHERE NOT EXISTS (
+            SELECT 1 FROM countries co WHERE co.name = ci.country OR co.name_en = ci.country
+        );
+
+        UPDATE cities ci SET country_id = co.id
+        FROM countries co
+        WHERE co.name = ci.country OR co.name_en = ci.country;
+
+        ALTER TABLE cities ALTER COLUMN country_id SET NOT NULL;
+        ALTER TABLE cities DROP COLUMN country;
+
+        -- City names only need to be unique within a country
+        FOR r IN
+            SELECT conname FROM pg_constraint
+            WHERE conrelid = 'cities'::regclass AND contype = 'u'
+        LOOP
+            EXECUTE format('ALTER TABLE cities DROP CONSTRAINT %I', r.conname);
+        END LOOP;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cities_country_name ON cities(country_id, name);
+
+UPDATE cities SET name_en = 'Kyiv',    latitude = 50.45, longitude = 30.52  WHERE name = 'Київ'   AND latitude IS NULL;
+UPDATE cities SET name_en = 'Lviv',    latitude = 49.84, longitude = 24.03  WHERE name = 'Львів'  AND latitude IS NULL;
+UPDATE cities SET name_en = 'Boston',  latitude = 42.36, longitude = -71.06 WHERE name = 'Бостон' AND latitude IS NULL;
+UPDATE cities SET name_en = 'Dubai',   latitude = 25.20, longitude = 55.27  WHERE name = 'Дубай'  AND latitude IS NULL;
+UPDATE cities SET name_en = 'Cairo',   latitude = 30.04, longitude = 31.24  WHERE name = 'Каїр'   AND latitude IS NULL;
+UPDATE cities SET name_en = 'Malé',    latitude = 4.18,  longitude = 73.51  WHERE name = 'Мале'   AND latitude IS NULL;
+UPDATE cities SET name_en = 'Taipei',  latitude = 25.03, longitude = 121.57 WHERE name = 'Тайбей' AND latitude IS NULL;
+
+-- Legacy read shape used by tour, booking and search queries.
+CREATE OR REPLACE VIEW locations AS
+SELECT ci.id, ci.name, co.name AS country, ci.country_id
+FROM cities ci
+JOIN countries co ON co.id = ci.country_id;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Migration: geo search
+-- Great-circle distance in plain SQL (haversine, no PostGIS), coordinates
+-- on the locations view, and an index for bounding-box prefilters.
+-- Run after geo/migration.sql.
+
+CREATE OR REPLACE FUNCTION geo_distance_km(lat1 DOUBLE PRECISION, lng1 DOUBLE PRECISION,
+                                           lat2 DOUBLE PRECISION, lng2 DOUBLE PRECISION)
+RETURNS DOUBLE PRECISION
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT 6371.0 * 2 * ASIN(SQRT(
+        POWER(SIN(RADIANS(lat2 - lat1) / 2), 2) +
+        COS(RADIANS(lat1)) * COS(RADIANS(lat2)) * POWER(SIN(RADIANS(lng2 - lng1) / 2), 2)
+    ))
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_cities_coordinates ON cities(latitude, longitude)
+    WHERE latitude IS NOT NULL;
+
+CREATE OR REPLACE VIEW locations AS
+SELECT ci.id, ci.name, co.name AS country, ci.country_id, ci.latitude, ci.longitude
+FROM cities ci
+JOIN countries co ON co.id = ci.country_id;
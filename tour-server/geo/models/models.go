@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+type Region struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	NameEn    string    `json:"name_en"`
+	SortOrder int       `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Region) TableName() string {
+	return "regions"
+}
+
+type Country struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	RegionID  *uint     `json:"region_id"`
+	ISOCode   *string   `json:"iso_code" gorm:"column:iso_code;unique"`
+	Name      string    `json:"name" gorm:"not null;unique"`
+	NameEn    string    `json:"name_en"`
+	Latitude  *float64  `json:"latitude"`
+	Longitude *float64  `json:"longitude"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Country) TableName() string {
+	return "countries"
+}
+
+type City struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CountryID uint      `json:"country_id" gorm:"not null"`
+	Name      string    `json:"name" gorm:"not null"`
+	NameEn    string    `json:"name_en"`
+	Latitude  *float64  `json:"latitude"`
+	Longitude *float64  `json:"longitude"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (City) TableName() string {
+	return "cities"
+}
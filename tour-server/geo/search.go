@@ -0,0 +1,106 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxRadiusKm caps radius searches; beyond that a bbox or no filter is
+// the better tool.
+const MaxRadiusKm = 5000
+
+const kmPerDegreeLat = 111.32
+
+// BBox is a bounding box in GeoJSON order. MinLng > MaxLng means the box
+// crosses the antimeridian.
+type BBox struct {
+	MinLng, MinLat, MaxLng, MaxLat float64
+}
+
+// ParseBBox reads "minLng,minLat,maxLng,maxLat".
+func ParseBBox(s string) (BBox, bool) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BBox{}, false
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || math.IsNaN(f) {
+			return BBox{}, false
+		}
+		v[i] = f
+	}
+	b := BBox{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3]}
+	if b.MinLat > b.MaxLat || !ValidCoordinates(&b.MinLat, &b.MinLng) || !ValidCoordinates(&b.MaxLat, &b.MaxLng) {
+		return BBox{}, false
+	}
+	return b, true
+}
+
+// Where returns a condition matching points inside the box.
+func (b BBox) Where(latCol, lngCol string) (string, []interface{}) {
+	lngOp := "AND"
+	if b.MinLng > b.MaxLng {
+		lngOp = "OR"
+	}
+	return fmt.Sprintf("(%[1]s BETWEEN ? AND ? AND (%[2]s >= ? %[3]s %[2]s <= ?))", latCol, lngCol, lngOp),
+		[]interface{}{b.MinLat, b.MaxLat, b.MinLng, b.MaxLng}
+}
+
+// Circle is a radius around a point.
+type Circle struct {
+	Lat, Lng, RadiusKm float64
+}
+
+// ParseCircle reads lat, lng and radius query values. ok is false if any
+// is missing or out of range.
+func ParseCircle(lat, lng, radius string) (Circle, bool) {
+	la, err1 := strconv.ParseFloat(lat, 64)
+	ln, err2 := strconv.ParseFloat(lng, 64)
+	r, err3 := strconv.ParseFloat(radius, 64)
+	if err1 != nil || err2 != nil || err3 != nil || !ValidCoordinates(&la, &ln) || !(r > 0 && r <= MaxRadiusKm) {
+		return Circle{}, false
+	}
+	return Circle{Lat: la, Lng: ln, RadiusKm: r}, true
+}
+
+// Bounds is the box around the circle, used as an index-friendly prefilter.
+// Near the poles it widens to all longitudes.
+func (c Circle) Bounds() BBox {
+	dLat := c.RadiusKm / kmPerDegreeLat
+	b := BBox{MinLat: math.Max(c.Lat-dLat, -90), MaxLat: math.Min(c.Lat+dLat, 90), MinLng: -180, MaxLng: 180}
+	if cos := math.Cos(c.Lat * math.Pi / 180); b.MinLat > -89 && b.MaxLat < 89 && cos > 0 {
+		if dLng := c.RadiusKm / (kmPerDegreeLat * cos); dLng < 180 {
+			b.MinLng = wrapLng(c.Lng - dLng)
+			b.MaxLng = wrapLng(c.Lng + dLng)
+		}
+	}
+	return b
+}
+
+// Where returns a condition matching points within the radius.
+func (c Circle) Where(latCol, lngCol string) (string, []interface{}) {
+	box, args := c.Bounds().Where(latCol, lngCol)
+	return box + fmt.Sprintf(" AND geo_distance_km(%s, %s, ?, ?) <= ?", latCol, lngCol),
+		append(args, c.Lat, c.Lng, c.RadiusKm)
+}
+
+// DistanceExpr is a SQL expression for the distance in km from the centre.
+// Coordinates are inlined so it can go into SELECT and ORDER BY clauses.
+func (c Circle) DistanceExpr(latCol, lngCol string) string {
+	return fmt.Sprintf("geo_distance_km(%s, %s, %s, %s)", latCol, lngCol,
+		strconv.FormatFloat(c.Lat, 'f', -1, 64), strconv.FormatFloat(c.Lng, 'f', -1, 64))
+}
+
+func wrapLng(lng float64) float64 {
+	if lng > 180 {
+		return lng - 360
+	}
+	if lng < -180 {
+		return lng + 360
+	}
+	return lng
+}
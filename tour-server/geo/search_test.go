@@ -0,0 +1,84 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBBox(t *testing.T) {
+	b, ok := ParseBBox("22.1, 48.0, 26.5, 50.5")
+	if !ok {
+		t.Fatal("expected valid bbox")
+	}
+	if b.MinLng != 22.1 || b.MaxLat != 50.5 {
+		t.Errorf("unexpected bbox %+v", b)
+	}
+
+	for _, s := range []string{"", "1,2,3", "a,b,c,d", "0,50,10,40", "0,-91,10,10", "0,0,181,10"} {
+		if _, ok := ParseBBox(s); ok {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestBBoxWhere_Antimeridian(t *testing.T) {
+	cond, args := BBox{MinLng: 170, MinLat: -50, MaxLng: -170, MaxLat: -30}.Where("lat", "lng")
+	if !strings.Contains(cond, "lng >= ? OR lng <= ?") {
+		t.Errorf("expected wrapped longitude condition, got %s", cond)
+	}
+	if len(args) != 4 {
+		t.Errorf("expected 4 args, got %d", len(args))
+	}
+
+	cond, _ = BBox{MinLng: 20, MinLat: 45, MaxLng: 30, MaxLat: 52}.Where("lat", "lng")
+	if !strings.Contains(cond, "lng >= ? AND lng <= ?") {
+		t.Errorf("expected plain longitude condition, got %s", cond)
+	}
+}
+
+func TestParseCircle(t *testing.T) {
+	if _, ok := ParseCircle("49.84", "24.03", "200"); !ok {
+		t.Error("expected Lviv + 200 km to be valid")
+	}
+	bad := [][3]string{
+		{"", "24.03", "200"},
+		{"49.84", "24.03", "0"},
+		{"49.84", "24.03", "6000"},
+		{"95", "24.03", "200"},
+	}
+	for _, b := range bad {
+		if _, ok := ParseCircle(b[0], b[1], b[2]); ok {
+			t.Errorf("expected %v to be invalid", b)
+		}
+	}
+}
+
+func TestCircleBounds(t *testing.T) {
+	// 200 km around Lviv: ~1.8° of latitude, ~2.8° of longitude at 49.8°N.
+	b := Circle{Lat: 49.84, Lng: 24.03, RadiusKm: 200}.Bounds()
+	if b.MinLat > 48.05 || b.MaxLat < 51.63 {
+		t.Errorf("latitude span too small: %+v", b)
+	}
+	if b.MinLng > 21.25 || b.MaxLng < 26.8 {
+		t.Errorf("longitude span too small: %+v", b)
+	}
+
+	// Near the pole every longitude is in range.
+	b = Circle{Lat: 89.5, Lng: 0, RadiusKm: 100}.Bounds()
+	if b.MinLng != -180 || b.MaxLng != 180 {
+		t.Errorf("expected full longitude range near the pole, got %+v", b)
+	}
+
+	// Crossing the antimeridian wraps.
+	b = Circle{Lat: -17.7, Lng: 178.4, RadiusKm: 300}.Bounds()
+	if b.MinLng <= b.MaxLng {
+		t.Errorf("expected wrapped box, got %+v", b)
+	}
+}
+
+func TestCircleDistanceExpr(t *testing.T) {
+	got := Circle{Lat: 49.84, Lng: 24.03}.DistanceExpr("ci.latitude", "ci.longitude")
+	if got != "geo_distance_km(ci.latitude, ci.longitude, 49.84, 24.03)" {
+		t.Errorf("got %s", got)
+	}
+}
@@ -8,6 +8,10 @@ import (
 	"time"
 	"tour-server/email"
 	"tour-server/liqpay"
+	"tour-server/notify"
+	"tour-server/telegram"
+	"tour-server/touritinerary"
+	"tour-server/webhooks"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -67,11 +71,13 @@ func LiqPayCallback(db *gorm.DB) echo.HandlerFunc {
 				orderID,
 			)
 			log.Printf("Payment failed: order=%s", orderID)
+			alertPaymentFailed(db, orderID)
 
 		case "reversed":
-			if err := cancelBookingByOrder(db, orderID); err != nil {
+			transitioned, err := cancelBookingByOrder(db, orderID)
+			if err != nil {
 				log.Printf("LiqPay callback: cancelBooking error: %v", err)
-			} else {
+			} else if transitioned {
 				log.Printf("Booking cancelled (reversed): order=%s", orderID)
 				// Send cancellation email for reversed payment
 				sendReversalEmail(db, orderID)
@@ -90,6 +96,8 @@ func LiqPayCallback(db *gorm.DB) echo.HandlerFunc {
 // so no seat adjustment needed for pending→confirmed.
 // The bool result reports whether this call performed the transition
 // (false if the booking was already paid) so the email is sent once.
+// A payment that arrives for a cancelled booking doesn't revive it:
+// the booking stays cancelled and staff are alerted to refund it.
 func confirmBooking(db *gorm.DB, orderID, paymentID string) (bool, error) {
 	tx := db.Begin()
 	if tx.Error != nil {
@@ -117,16 +125,24 @@ func confirmBooking(db *gorm.DB, orderID, paymentID string) (bool, error) {
 	}
 
 	now := time.Now()
-	if err := tx.Exec(`
+	result := tx.Exec(`
 		UPDATE bookings
 		SET status = 'confirmed',
 			payment_status = 'paid',
 			liqpay_payment_id = ?,
 			paid_at = ?
-		WHERE liqpay_order_id = ?
-	`, paymentID, now, orderID).Error; err != nil {
+		WHERE liqpay_order_id = ? AND status <> 'cancelled'
+	`, paymentID, now, orderID)
+	if result.Error != nil {
 		tx.Rollback()
-		return false, err
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		log.Printf("LiqPay callback: payment for cancelled booking #%d, needs refund: order=%s payment_id=%s",
+			booking.ID, orderID, paymentID)
+		telegram.AlertBooking(db, telegram.EventPaidAfterCancel, booking.ID)
+		return false, nil
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -137,24 +153,35 @@ func confirmBooking(db *gorm.DB, orderID, paymentID string) (bool, error) {
 
 // cancelBookingByOrder handles payment reversal:
 // restores seats and sets status=cancelled, payment_status=reversed.
-func cancelBookingByOrder(db *gorm.DB, orderID string) error {
+// Like confirmBooking, the bool result is false when the booking was
+// already reversed — e.g. a refund we issued ourselves when cancelling a
+// departure — so the customer isn't emailed twice.
+func cancelBookingByOrder(db *gorm.DB, orderID string) (bool, error) {
 	tx := db.Begin()
 	if tx.Error != nil {
-		return tx.Error
+		return false, tx.Error
 	}
 
 	var booking struct {
-		ID         uint   `gorm:"column:id"`
-		TourDateID uint   `gorm:"column:tour_date_id"`
-		Seats      uint   `gorm:"column:seats"`
-		Status     string `gorm:"column:status"`
+		ID            uint   `gorm:"column:id"`
+		TourDateID    uint   `gorm:"column:tour_date_id"`
+		Seats         uint   `gorm:"column:seats"`
+		Status        string `gorm:"column:status"`
+		PaymentStatus string `gorm:"column:payment_status"`
 	}
 	if err := tx.Raw(
-		"SELECT id, tour_date_id, seats, status FROM bookings WHERE liqpay_order_id = ?",
+		"SELECT id, tour_date_id, seats, status, payment_status FROM bookings WHERE liqpay_order_id = ?",
 		orderID,
 	).Scan(&booking).Error; err != nil || booking.ID == 0 {
 		tx.Rollback()
-		return fmt.Errorf("booking not found for order %s", orderID)
+		return false, fmt.Errorf("booking not found for order %s", orderID)
+	}
+
+	// Idempotency — already reversed, skip
+	if booking.PaymentStatus == "reversed" {
+		tx.Rollback()
+		log.Printf("Booking already reversed: order=%s", orderID)
+		return false, nil
 	}
 
 	// Only restore seats if booking was active
@@ -164,25 +191,46 @@ func cancelBookingByOrder(db *gorm.DB, orderID string) error {
 			booking.Seats, booking.TourDateID,
 		).Error; err != nil {
 			tx.Rollback()
-			return err
+			return false, err
 		}
 	}
 
-	if err := tx.Exec(`
+	// Conditional, so a departure cancellation that claimed the booking
+	// while we were reading it wins and no second email goes out.
+	res := tx.Exec(`
 		UPDATE bookings
 		SET status = 'cancelled',
 			payment_status = 'reversed'
-		WHERE liqpay_order_id = ?
-	`, orderID).Error; err != nil {
+		WHERE liqpay_order_id = ? AND payment_status IS DISTINCT FROM 'reversed'
+	`, orderID)
+	if res.Error != nil {
+		tx.Rollback()
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
 		tx.Rollback()
-		return err
+		log.Printf("Booking already reversed: order=%s", orderID)
+		return false, nil
 	}
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // ── Email helpers ─────────────────────────────────────────────────────────────
 
+// alertPaymentFailed tells staff and partner webhooks a payment attempt
+// failed. The customer gets no email — they can simply retry from the
+// payment page.
+func alertPaymentFailed(db *gorm.DB, orderID string) {
+	var bookingID uint
+	db.Raw("SELECT id FROM bookings WHERE liqpay_order_id = ?", orderID).Scan(&bookingID)
+	telegram.AlertBooking(db, telegram.EventPaymentFailed, bookingID)
+	webhooks.PublishBooking(db, webhooks.EventPaymentFailed, bookingID)
+}
+
 func sendPaymentEmail(db *gorm.DB, orderID string) {
 	var info struct {
 		ID            uint    `gorm:"column:id"`
@@ -202,18 +250,30 @@ func sendPaymentEmail(db *gorm.DB, orderID string) {
 		WHERE b.liqpay_order_id = ?
 	`, orderID).Scan(&info).Error
 
-	if err != nil || info.ID == 0 || info.CustomerEmail == "" {
+	if err != nil || info.ID == 0 {
 		return
 	}
 
-	email.NotifyPaymentReceived(info.CustomerEmail, email.BookingNotification{
+	notify.NotifyBooking(db, notify.EventBookingConfirmed, info.ID)
+	telegram.AlertBooking(db, telegram.EventPaymentReceived, info.ID)
+	webhooks.PublishBooking(db, webhooks.EventBookingConfirmed, info.ID)
+
+	if info.CustomerEmail == "" {
+		return
+	}
+
+	notif := email.BookingNotification{
 		CustomerName: info.CustomerName,
 		TourTitle:    info.TourTitle,
 		Seats:        int(info.Seats),
 		TotalPrice:   info.TotalPrice,
 		BookingID:    info.ID,
 		Status:       "paid",
-	})
+	}
+	if days, err := touritinerary.LoadForBooking(db, info.ID); err == nil {
+		notif.Itinerary = touritinerary.EmailDays(days)
+	}
+	email.NotifyPaymentReceived(info.CustomerEmail, notif)
 	log.Printf("Payment email queued: booking #%d → %s", info.ID, info.CustomerEmail)
 }
 
@@ -236,7 +296,15 @@ func sendReversalEmail(db *gorm.DB, orderID string) {
 		WHERE b.liqpay_order_id = ?
 	`, orderID).Scan(&info).Error
 
-	if err != nil || info.ID == 0 || info.CustomerEmail == "" {
+	if err != nil || info.ID == 0 {
+		return
+	}
+
+	notify.NotifyBooking(db, notify.EventBookingCancelled, info.ID)
+	telegram.AlertBooking(db, telegram.EventPaymentReversed, info.ID)
+	webhooks.PublishBooking(db, webhooks.EventBookingCancelled, info.ID)
+
+	if info.CustomerEmail == "" {
 		return
 	}
 
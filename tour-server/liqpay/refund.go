@@ -0,0 +1,59 @@
+package liqpay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// APIURL is the LiqPay server-to-server endpoint. Overridden in tests.
+var APIURL = "https://www.liqpay.ua/api/request"
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Refund returns amount (UAH) of the payment made under orderID.
+// LiqPay answers synchronously; any status other than "reversed" is
+// reported as an error carrying LiqPay's description.
+func Refund(orderID string, amount float64) error {
+	params := Params{
+		"public_key": os.Getenv("LIQPAY_PUBLIC_KEY"),
+		"version":    "3",
+		"action":     "refund",
+		"order_id":   orderID,
+		"amount":     fmt.Sprintf("%.2f", amount),
+	}
+	data, err := Encode(params)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"data":      {data},
+		"signature": {Sign(data, os.Getenv("LIQPAY_PRIVATE_KEY"))},
+	}
+	resp, err := httpClient.Post(APIURL, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("liqpay refund: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Status      string `json:"status"`
+		ErrCode     string `json:"err_code"`
+		Description string `json:"err_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("liqpay refund: bad response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if out.Status != "reversed" {
+		if out.Description == "" {
+			out.Description = out.ErrCode
+		}
+		return fmt.Errorf("liqpay refund: status %q: %s", out.Status, out.Description)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package liqpay
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func withLiqPayServer(t *testing.T, h http.HandlerFunc) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	old := APIURL
+	APIURL = srv.URL
+	t.Cleanup(func() {
+		APIURL = old
+		srv.Close()
+	})
+}
+
+func TestRefundSendsSignedRequest(t *testing.T) {
+	t.Setenv("LIQPAY_PUBLIC_KEY", "pub")
+	t.Setenv("LIQPAY_PRIVATE_KEY", "priv")
+
+	var got map[string]interface{}
+	withLiqPayServer(t, func(w http.ResponseWriter, r *http.Request) {
+		data, sig := r.FormValue("data"), r.FormValue("signature")
+		if !Verify(data, sig, "priv") {
+			t.Error("request signature does not verify")
+		}
+		got, _ = Decode(data)
+		w.Write([]byte(`{"status":"reversed"}`))
+	})
+
+	if err := Refund("booking-7-1700000000", 1500); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if got["action"] != "refund" || got["order_id"] != "booking-7-1700000000" || got["amount"] != "1500.00" {
+		t.Errorf("unexpected request params: %v", got)
+	}
+}
+
+func TestRefundReportsFailure(t *testing.T) {
+	withLiqPayServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"error","err_code":"payment_not_found","err_description":"Payment not found"}`))
+	})
+
+	err := Refund("booking-7-1", 10)
+	if err == nil {
+		t.Fatal("expected error for non-reversed status")
+	}
+}
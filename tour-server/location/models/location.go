@@ -1,7 +1,12 @@
 package models
 
+// Location is the read-only `locations` view over cities and countries
+// (see geo/migration.sql); edit cities through geo/api.
 type Location struct {
-	ID      uint   `json:"id" gorm:"primaryKey"`
-	Name    string `json:"name" gorm:"not null;unique"`
-	Country string `json:"country" gorm:"not null"`
+	ID        uint     `json:"id" gorm:"primaryKey"`
+	Name      string   `json:"name" gorm:"not null;unique"`
+	Country   string   `json:"country" gorm:"not null"`
+	CountryID uint     `json:"country_id"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
 }
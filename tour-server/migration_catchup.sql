@@ -119,15 +119,20 @@ INSERT INTO statuses (name) VALUES
 ON CONFLICT (name) DO NOTHING;
 
 -- 3.2 locations
-INSERT INTO locations (name, country) VALUES
-    ('Київ',     'Україна'),
-    ('Львів',    'Україна'),
-    ('Бостон',   'США'),
-    ('Дубай',    'ОАЕ'),
-    ('Каїр',     'Єгипет'),
-    ('Мале',     'Мальдіви'),
-    ('Тайбей',   'Тайвань')
-ON CONFLICT (name) DO NOTHING;
+-- Skipped once geo/migration.sql has turned locations into a view over cities.
+DO $$ BEGIN
+    IF EXISTS (SELECT 1 FROM pg_class WHERE relname = 'locations' AND relkind = 'r') THEN
+        INSERT INTO locations (name, country) VALUES
+            ('Київ',     'Україна'),
+            ('Львів',    'Україна'),
+            ('Бостон',   'США'),
+            ('Дубай',    'ОАЕ'),
+            ('Каїр',     'Єгипет'),
+            ('Мале',     'Мальдіви'),
+            ('Тайбей',   'Тайвань')
+        ON CONFLICT (name) DO NOTHING;
+    END IF;
+END $$;
 
 -- 3.3 demo users (admin / user) — passwords: admin123 / user123
 INSERT INTO tour_users (email, password_hash, name, phone, role, is_verified)
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"tour-server/email"
+	"tour-server/moderation"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// maxBulk caps how many comments one approve/reject call touches.
+const maxBulk = 100
+
+type AdminComment struct {
+	ID              uint           `json:"id" gorm:"column:id"`
+	TourID          uint           `json:"tour_id" gorm:"column:tour_id"`
+	TourTitle       string         `json:"tour_title" gorm:"column:tour_title"`
+	ParentID        *uint          `json:"parent_id" gorm:"column:parent_id"`
+	UserID          *uint          `json:"user_id" gorm:"column:user_id"`
+	AuthorName      string         `json:"author_name" gorm:"column:author_name"`
+	AuthorEmail     *string        `json:"author_email" gorm:"column:author_email"`
+	IsVerifiedBuyer bool           `json:"is_verified_buyer" gorm:"column:is_verified_buyer"`
+	Comment         string         `json:"comment" gorm:"column:comment"`
+	Rating          *int           `json:"rating" gorm:"column:rating"`
+	Status          string         `json:"status" gorm:"column:status"`
+	Flags           pq.StringArray `json:"flags" gorm:"column:moderation_flags;type:text[]"`
+	RejectionReason *string        `json:"rejection_reason" gorm:"column:rejection_reason"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"column:created_at"`
+	ModeratedAt     *time.Time     `json:"moderated_at" gorm:"column:moderated_at"`
+}
+
+// GET /admin/comments?status=pending&tour_id=&page=&limit=
+// Pending comments come oldest first, the rest newest first.
+func GetAdminComments(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		status := c.QueryParam("status")
+		if status == "" {
+			status = moderation.StatusPending
+		}
+		if status != moderation.StatusPending && status != moderation.StatusApproved && status != moderation.StatusRejected {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "status має бути pending, approved або rejected"})
+		}
+		page, _ := strconv.Atoi(c.QueryParam("page"))
+		if page <= 0 {
+			page = 1
+		}
+		limit, _ := strconv.Atoi(c.QueryParam("limit"))
+		if limit <= 0 || limit > 100 {
+			limit = 20
+		}
+
+		tourID, _ := strconv.Atoi(c.QueryParam("tour_id"))
+		filter := func(q *gorm.DB) *gorm.DB {
+			q = q.Where("tr.status = ?", status)
+			if tourID > 0 {
+				q = q.Where("tr.tour_id = ?", tourID)
+			}
+			return q
+		}
+
+		var total int64
+		if err := filter(db.Table("tour_reviews tr")).Count(&total).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch comments"})
+		}
+
+		order := "tr.created_at DESC"
+		if status == moderation.StatusPending {
+			order = "tr.created_at ASC"
+		}
+		comments := []AdminComment{}
+		err := filter(db.Table("tour_reviews tr")).Select(`
+				tr.id, tr.tour_id, t.title AS tour_title, tr.parent_id, tr.user_id,
+				COALESCE(tu.name, tr.guest_name, 'Гість') AS author_name, tu.email AS author_email,
+				tr.is_verified_buyer, tr.comment, tr.rating, tr.status, tr.moderation_flags,
+				tr.rejection_reason, tr.created_at, tr.moderated_at
+			`).
+			Joins("JOIN tours t ON t.id = tr.tour_id").
+			Joins("LEFT JOIN tour_users tu ON tu.id = tr.user_id").
+			Order(order).Limit(limit).Offset((page - 1) * limit).
+			Scan(&comments).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch comments"})
+		}
+
+		totalPages := (total + int64(limit) - 1) / int64(limit)
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"comments":   comments,
+			"pagination": map[string]interface{}{"page": page, "limit": limit, "total": total, "totalPages": totalPages},
+		})
+	}
+}
+
+type bulkRequest struct {
+	IDs    []uint `json:"ids"`
+	Reason string `json:"reason"`
+}
+
+func (r *bulkRequest) validate(needReason bool) string {
+	if len(r.IDs) == 0 {
+		return "ids обов'язкові"
+	}
+	if len(r.IDs) > maxBulk {
+		return fmt.Sprintf("Не більше %d коментарів за раз", maxBulk)
+	}
+	r.Reason = strings.TrimSpace(r.Reason)
+	if needReason && r.Reason == "" {
+		return "Вкажіть причину відхилення"
+	}
+	if len([]rune(r.Reason)) > 500 {
+		return "Причина занадто довга (максимум 500 символів)"
+	}
+	return ""
+}
+
+// POST /admin/comments/approve
+// Body: { "ids": [1, 2, 3] }
+// Approving a comment hidden by reports clears its reported flag and
+// dismisses the open reports, same as keeping it from the reports inbox.
+func ApproveComments(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req bulkRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Невірний формат запиту"})
+		}
+		if msg := req.validate(false); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		adminID, _ := c.Get("user_id").(uint)
+
+		tx := db.Begin()
+		defer tx.Rollback()
+
+		var approved []uint
+		err := tx.Raw(`
+			UPDATE tour_reviews
+			SET status = ?, rejection_reason = NULL, moderated_by = ?, moderated_at = NOW(),
+				moderation_flags = array_remove(moderation_flags, ?)
+			WHERE id IN ? AND status <> ?
+			RETURNING id
+		`, moderation.StatusApproved, adminID, moderation.FlagReported, req.IDs, moderation.StatusApproved).Scan(&approved).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to approve comments"})
+		}
+
+		if len(approved) > 0 {
+			err = tx.Exec(`
+				UPDATE comment_reports SET status = ?, resolved_by = ?, resolved_at = NOW()
+				WHERE review_id IN ? AND status = ?
+			`, moderation.ReportDismissed, adminID, approved, moderation.ReportOpen).Error
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to dismiss reports"})
+			}
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to approve comments"})
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"updated": len(approved)})
+	}
+}
+
+// POST /admin/comments/reject
+// Body: { "ids": [1, 2, 3], "reason": "Реклама" }
+// Authors with an account are emailed the reason.
+func RejectComments(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req bulkRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Невірний формат запиту"})
+		}
+		if msg := req.validate(true); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		adminID, _ := c.Get("user_id").(uint)
+
+		var rejected []uint
+		err := db.Raw(`
+			UPDATE tour_reviews
+			SET status = ?, rejection_reason = ?, moderated_by = ?, moderated_at = NOW()
+			WHERE id IN ? AND status <> ?
+			RETURNING id
+		`, moderation.StatusRejected, req.Reason, adminID, req.IDs, moderation.StatusRejected).Scan(&rejected).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to reject comments"})
+		}
+
+		notifyRejected(db, rejected, req.Reason)
+		return c.JSON(http.StatusOK, map[string]interface{}{"updated": len(rejected)})
+	}
+}
+
+// notifyRejected emails the authors of the given comments; guests left
+// no address and are skipped.
+func notifyRejected(db *gorm.DB, ids []uint, reason string) {
+	if len(ids) == 0 {
+		return
+	}
+	var rows []struct {
+		TourID    uint   `gorm:"column:tour_id"`
+		TourTitle string `gorm:"column:tour_title"`
+		Name      string `gorm:"column:name"`
+		Email     string `gorm:"column:email"`
+		Comment   string `gorm:"column:comment"`
+	}
+	err := db.Raw(`
+		SELECT tr.tour_id, t.title AS tour_title, tu.name, tu.email, tr.comment
+		FROM tour_reviews tr
+		JOIN tours t ON t.id = tr.tour_id
+		JOIN tour_users tu ON tu.id = tr.user_id
+		WHERE tr.id IN ? AND tu.email <> ''
+	`, ids).Scan(&rows).Error
+	if err != nil {
+		log.Printf("Failed to load rejected comment authors: %v", err)
+		return
+	}
+	for _, r := range rows {
+		email.NotifyCommentRejected(r.Email, email.CommentRejection{
+			AuthorName: r.Name,
+			TourTitle:  r.TourTitle,
+			Comment:    r.Comment,
+			Reason:     reason,
+			TourURL:    tourURL(r.TourID),
+		})
+	}
+}
+
+func tourURL(tourID uint) string {
+	base := os.Getenv("FRONTEND_URL")
+	if base == "" {
+		base = "https://openworld.local"
+	}
+	return fmt.Sprintf("%s/TourDetails/%d", base, tourID)
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tour-server/moderation"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// defaultRemovalReason is emailed when an admin removes a reported
+// comment without giving a reason.
+const defaultRemovalReason = "Порушення правил спільноти"
+
+type CommentReport struct {
+	CommentID uint      `json:"-" gorm:"column:review_id"`
+	Reason    string    `json:"reason" gorm:"column:reason"`
+	Details   *string   `json:"details" gorm:"column:details"`
+	Reporter  string    `json:"reporter" gorm:"column:reporter"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// ReportedComment is one inbox entry: a comment, where it was posted and
+// the reports against it.
+type ReportedComment struct {
+	CommentID      uint            `json:"comment_id" gorm:"column:id"`
+	TourID         uint            `json:"tour_id" gorm:"column:tour_id"`
+	TourTitle      string          `json:"tour_title" gorm:"column:tour_title"`
+	ParentComment  *string         `json:"parent_comment" gorm:"column:parent_comment"` // replies only
+	AuthorName     string          `json:"author_name" gorm:"column:author_name"`
+	Comment        string          `json:"comment" gorm:"column:comment"`
+	Status         string          `json:"status" gorm:"column:status"`
+	Flags          pq.StringArray  `json:"flags" gorm:"column:moderation_flags;type:text[]"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"column:created_at"`
+	ReportCount    int             `json:"report_count" gorm:"column:report_count"`
+	LastReportedAt time.Time       `json:"last_reported_at" gorm:"column:last_reported_at"`
+	Reports        []CommentReport `json:"reports" gorm:"-"`
+}
+
+// GET /admin/comment-reports?status=open&page=&limit=
+// Reported comments, most reported first, with every report of the given
+// status.
+func GetCommentReports(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		status := c.QueryParam("status")
+		if status == "" {
+			status = moderation.ReportOpen
+		}
+		if status != moderation.ReportOpen && status != moderation.ReportResolved && status != moderation.ReportDismissed {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "status має бути open, resolved або dismissed"})
+		}
+		page, _ := strconv.Atoi(c.QueryParam("page"))
+		if page <= 0 {
+			page = 1
+		}
+		limit, _ := strconv.Atoi(c.QueryParam("limit"))
+		if limit <= 0 || limit > 100 {
+			limit = 20
+		}
+
+		var total int64
+		if err := db.Raw(`
+			SELECT COUNT(DISTINCT review_id) FROM comment_reports WHERE status = ?
+		`, status).Scan(&total).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch reports"})
+		}
+
+		comments := []ReportedComment{}
+		err := db.Raw(`
+			SELECT tr.id, tr.tour_id, t.title AS tour_title, p.comment AS parent_comment,
+				COALESCE(tu.name, tr.guest_name, 'Гість') AS author_name,
+				tr.comment, tr.status, tr.moderation_flags, tr.created_at,
+				r.report_count, r.last_reported_at
+			FROM (
+				SELECT review_id, COUNT(*) AS report_count, MAX(created_at) AS last_reported_at
+				FROM comment_reports WHERE status = ?
+				GROUP BY review_id
+			) r
+			JOIN tour_reviews tr ON tr.id = r.review_id
+			JOIN tours t ON t.id = tr.tour_id
+			LEFT JOIN tour_reviews p ON p.id = tr.parent_id
+			LEFT JOIN tour_users tu ON tu.id = tr.user_id
+			ORDER BY r.report_count DESC, r.last_reported_at DESC
+			LIMIT ? OFFSET ?
+		`, status, limit, (page-1)*limit).Scan(&comments).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch reports"})
+		}
+
+		if len(comments) > 0 {
+			ids := make([]uint, len(comments))
+			for i, rc := range comments {
+				ids[i] = rc.CommentID
+			}
+			var reports []CommentReport
+			err := db.Raw(`
+				SELECT cr.review_id, cr.reason, cr.details,
+					COALESCE(u.name, 'Гість') AS reporter, cr.created_at
+				FROM comment_reports cr
+				LEFT JOIN tour_users u ON u.id = cr.user_id
+				WHERE cr.review_id IN ? AND cr.status = ?
+				ORDER BY cr.created_at
+			`, ids, status).Scan(&reports).Error
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch reports"})
+			}
+			byComment := make(map[uint][]CommentReport, len(comments))
+			for _, r := range reports {
+				byComment[r.CommentID] = append(byComment[r.CommentID], r)
+			}
+			for i := range comments {
+				comments[i].Reports = byComment[comments[i].CommentID]
+			}
+		}
+
+		totalPages := (total + int64(limit) - 1) / int64(limit)
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"comments":   comments,
+			"pagination": map[string]interface{}{"page": page, "limit": limit, "total": total, "totalPages": totalPages},
+		})
+	}
+}
+
+// Resolve actions
+const (
+	actionRemove = "remove" // reject the comment, reports resolved
+	actionKeep   = "keep"   // publish the comment, reports dismissed
+)
+
+type resolveRequest struct {
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"` // emailed to the author on remove
+}
+
+// POST /admin/comment-reports/:id/resolve — :id is the comment
+// Body: { "action": "remove" | "keep", "reason": "..." }
+func ResolveCommentReports(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		commentID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || commentID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Невірний ID коментаря"})
+		}
+		var req resolveRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Невірний формат запиту"})
+		}
+		if req.Action != actionRemove && req.Action != actionKeep {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "action має бути remove або keep"})
+		}
+		req.Reason = strings.TrimSpace(req.Reason)
+		if len([]rune(req.Reason)) > 500 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Причина занадто довга (максимум 500 символів)"})
+		}
+		if req.Reason == "" {
+			req.Reason = defaultRemovalReason
+		}
+		adminID, _ := c.Get("user_id").(uint)
+
+		reportStatus, commentStatus := moderation.ReportDismissed, moderation.StatusApproved
+		if req.Action == actionRemove {
+			reportStatus, commentStatus = moderation.ReportResolved, moderation.StatusRejected
+		}
+
+		tx := db.Begin()
+		defer tx.Rollback()
+
+		res := tx.Exec(`
+			UPDATE comment_reports SET status = ?, resolved_by = ?, resolved_at = NOW()
+			WHERE review_id = ? AND status = ?
+		`, reportStatus, adminID, commentID, moderation.ReportOpen)
+		if res.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to resolve reports"})
+		}
+		if res.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Відкритих скарг на цей коментар немає"})
+		}
+
+		var reason interface{}
+		if req.Action == actionRemove {
+			reason = req.Reason
+		}
+		var changed []uint
+		err = tx.Raw(`
+			UPDATE tour_reviews
+			SET status = ?, rejection_reason = ?, moderated_by = ?, moderated_at = NOW(),
+				moderation_flags = array_remove(moderation_flags, ?)
+			WHERE id = ? AND status <> ?
+			RETURNING id
+		`, commentStatus, reason, adminID, moderation.FlagReported, commentID, commentStatus).Scan(&changed).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update comment"})
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to resolve reports"})
+		}
+
+		if req.Action == actionRemove {
+			notifyRejected(db, changed, req.Reason)
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message":        "Скарги опрацьовано",
+			"reports":        res.RowsAffected,
+			"comment_status": commentStatus,
+		})
+	}
+}
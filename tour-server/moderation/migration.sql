@@ -0,0 +1,40 @@
+-- Migration: comment moderation
+-- Comments (tour_reviews) get a moderation status. Existing comments stay
+-- published. New ones are pre-screened (moderation.Screen) and either
+-- approved straight away or left pending for /admin/comments.
+-- Run after tourratings/migration.sql: only approved comments count
+-- towards the tour rating.
+
+ALTER TABLE tour_reviews
+    ADD COLUMN IF NOT EXISTS status            VARCHAR(10) NOT NULL DEFAULT 'approved'
+        CHECK (status IN ('pending', 'approved', 'rejected')),
+    ADD COLUMN IF NOT EXISTS moderation_flags  TEXT[] NOT NULL DEFAULT '{}', -- why screening held it
+    ADD COLUMN IF NOT EXISTS rejection_reason  TEXT,
+    ADD COLUMN IF NOT EXISTS moderated_by      INTEGER REFERENCES tour_users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS moderated_at      TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tour_reviews_status ON tour_reviews(status, created_at);
+
+CREATE OR REPLACE VIEW tour_rating_votes AS
+SELECT tour_id, rating::numeric AS rating
+FROM tour_ratings
+UNION ALL
+SELECT tour_id, rating
+FROM (
+    SELECT DISTINCT ON (r.tour_id, COALESCE(r.user_id, -r.id))
+        r.tour_id, r.rating::numeric AS rating
+    FROM tour_reviews r
+    WHERE r.parent_id IS NULL
+      AND r.rating IS NOT NULL
+      AND r.status = 'approved'
+      AND NOT EXISTS (
+          SELECT 1 FROM tour_ratings tr
+          WHERE tr.tour_id = r.tour_id AND tr.user_id = r.user_id
+      )
+    ORDER BY r.tour_id, COALESCE(r.user_id, -r.id), r.updated_at DESC
+) rv;
+
+DROP TRIGGER IF EXISTS trg_tour_rating ON tour_reviews;
+CREATE TRIGGER trg_tour_rating
+    AFTER INSERT OR UPDATE OF tour_id, user_id, parent_id, rating, status OR DELETE ON tour_reviews
+    FOR EACH ROW EXECUTE FUNCTION tour_rating_sync();
@@ -0,0 +1,31 @@
+-- Migration: reader reports on comments
+-- Signed-in users and guests (X-Guest-Token) can report a comment once
+-- each. A comment with moderation.report_threshold open reports from
+-- signed-in users goes back to pending (flag 'reported'), which hides it
+-- until an admin resolves the reports in /admin/comment-reports. Guest
+-- reports only show up there. Run after migration.sql.
+
+CREATE TABLE IF NOT EXISTS comment_reports (
+    id           SERIAL PRIMARY KEY,
+    review_id    INTEGER NOT NULL REFERENCES tour_reviews(id) ON DELETE CASCADE,
+    user_id      INTEGER REFERENCES tour_users(id) ON DELETE CASCADE,
+    guest_token  VARCHAR(128),
+    reason       VARCHAR(20) NOT NULL
+        CHECK (reason IN ('spam', 'abuse', 'hate', 'off_topic', 'misleading', 'other')),
+    details      TEXT,
+    status       VARCHAR(10) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'resolved', 'dismissed')),
+    resolved_by  INTEGER REFERENCES tour_users(id) ON DELETE SET NULL,
+    resolved_at  TIMESTAMP,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (
+        (user_id IS NOT NULL AND guest_token IS NULL) OR
+        (user_id IS NULL AND guest_token IS NOT NULL)
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_comment_reports_user
+    ON comment_reports(review_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_comment_reports_guest
+    ON comment_reports(review_id, guest_token) WHERE guest_token IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_comment_reports_status ON comment_reports(status, created_at);
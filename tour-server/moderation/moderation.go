@@ -0,0 +1,192 @@
+// Package moderation pre-screens tour comments (tour_reviews) and decides
+// whether they go live or wait for a moderator (see migration.sql). The
+// policy comes from the moderation section of config.yaml.
+package moderation
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"tour-server/config"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Statuses
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Flags say why screening held a comment.
+const (
+	FlagLinks       = "links"
+	FlagBannedWords = "banned_words"
+	FlagDuplicate   = "duplicate"
+	FlagNewAccount  = "new_account"
+)
+
+// Auto-approve policies
+const (
+	AutoApproveAll      = "all"
+	AutoApproveUsers    = "users"
+	AutoApproveVerified = "verified"
+	AutoApproveNone     = "none"
+)
+
+// minDuplicateLen: shorter texts ("Супер!") are too common to call
+// duplicates.
+const minDuplicateLen = 20
+
+// duplicateWindow is how far back duplicate text is looked for.
+const duplicateWindow = "30 days"
+
+var linkRegex = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|info|biz|io|ru|ua|xyz|top|site|online|shop)\b`)
+
+type Policy struct {
+	AutoApprove   string
+	MaxLinks      int
+	BannedWords   []string // lowercase
+	NewAccountAge time.Duration
+}
+
+// CurrentPolicy reads the policy from config; auto-approve defaults to
+// verified buyers.
+func CurrentPolicy() Policy {
+	cfg := config.GetConfig().Moderation
+	p := Policy{
+		AutoApprove:   cfg.AutoApprove,
+		MaxLinks:      cfg.MaxLinks,
+		NewAccountAge: time.Duration(cfg.NewAccountHours) * time.Hour,
+	}
+	if p.AutoApprove == "" {
+		p.AutoApprove = AutoApproveVerified
+	}
+	for _, w := range cfg.BannedWords {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			p.BannedWords = append(p.BannedWords, w)
+		}
+	}
+	return p
+}
+
+// Submission is a new or edited comment.
+type Submission struct {
+	Text          string
+	UserID        *uint // nil for guests
+	VerifiedBuyer bool
+	// Set by Screen
+	AccountCreated *time.Time
+	Duplicate      bool
+}
+
+// Decide returns the status for a screened submission and the flags that
+// held it, if any.
+func (p Policy) Decide(s Submission, now time.Time) (string, []string) {
+	flags := []string{}
+	if len(linkRegex.FindAllString(s.Text, -1)) > p.MaxLinks {
+		flags = append(flags, FlagLinks)
+	}
+	if p.hasBannedWord(s.Text) {
+		flags = append(flags, FlagBannedWords)
+	}
+	if s.Duplicate {
+		flags = append(flags, FlagDuplicate)
+	}
+	if s.UserID != nil && !s.VerifiedBuyer && p.NewAccountAge > 0 &&
+		s.AccountCreated != nil && now.Sub(*s.AccountCreated) < p.NewAccountAge {
+		flags = append(flags, FlagNewAccount)
+	}
+	if len(flags) > 0 {
+		return StatusPending, flags
+	}
+
+	switch p.AutoApprove {
+	case AutoApproveAll:
+		return StatusApproved, flags
+	case AutoApproveUsers:
+		if s.UserID != nil {
+			return StatusApproved, flags
+		}
+	case AutoApproveVerified:
+		if s.VerifiedBuyer {
+			return StatusApproved, flags
+		}
+	}
+	return StatusPending, flags
+}
+
+// hasBannedWord matches banned words against whole words; words of four
+// letters or more also match as a prefix, to catch inflected forms.
+func (p Policy) hasBannedWord(text string) bool {
+	if len(p.BannedWords) == 0 {
+		return false
+	}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		for _, b := range p.BannedWords {
+			if w == b || (utf8.RuneCountInString(b) >= 4 && strings.HasPrefix(w, b)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Screen checks a comment against the current policy. excludeID is the
+// comment being edited (0 for a new one), so it isn't its own duplicate.
+func Screen(db *gorm.DB, s Submission, excludeID uint) (string, []string) {
+	if s.UserID != nil {
+		var created time.Time
+		if db.Raw("SELECT created_at FROM tour_users WHERE id = ?", *s.UserID).Scan(&created).Error == nil && !created.IsZero() {
+			s.AccountCreated = &created
+		}
+	}
+
+	if text := normalize(s.Text); utf8.RuneCountInString(text) >= minDuplicateLen {
+		var n int64
+		db.Raw(`
+			SELECT COUNT(*) FROM tour_reviews
+			WHERE id <> ? AND created_at > NOW() - INTERVAL '`+duplicateWindow+`'
+			  AND LOWER(regexp_replace(BTRIM(comment), '\s+', ' ', 'g')) = ?
+		`, excludeID, text).Scan(&n)
+		s.Duplicate = n > 0
+	}
+
+	return CurrentPolicy().Decide(s, time.Now())
+}
+
+// EditStatus decides the status and flags of an edited comment from its
+// state before the edit and the screening of the new text. A clean edit
+// keeps an approved comment live; anything flagged goes to the queue.
+// Editing never publishes a comment: pending ones stay in the queue and
+// rejected ones go back to it for a moderator to look at again. A comment
+// hidden by reader reports keeps its reported flag until the reports are
+// resolved.
+func EditStatus(current string, currentFlags []string, screened string, flags []string) (string, []string) {
+	clean := len(flags) == 0
+	for _, f := range currentFlags {
+		if f == FlagReported {
+			flags = append(flags, FlagReported)
+			break
+		}
+	}
+	switch {
+	case current == StatusRejected || current == StatusPending:
+		return StatusPending, flags
+	case current == StatusApproved && clean:
+		return StatusApproved, flags
+	}
+	return screened, flags
+}
+
+// normalize lowercases and collapses whitespace, as the duplicate check
+// does in SQL.
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
@@ -0,0 +1,90 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecide(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	uid := uint(7)
+	old := now.AddDate(-1, 0, 0)
+	fresh := now.Add(-time.Hour)
+	p := Policy{AutoApprove: AutoApproveVerified, MaxLinks: 0, BannedWords: []string{"казино"}, NewAccountAge: 24 * time.Hour}
+
+	tests := []struct {
+		name   string
+		s      Submission
+		status string
+		flags  []string
+	}{
+		{"verified buyer", Submission{Text: "Чудовий тур!", UserID: &uid, VerifiedBuyer: true, AccountCreated: &old}, StatusApproved, []string{}},
+		{"user waits", Submission{Text: "Чудовий тур!", UserID: &uid, AccountCreated: &old}, StatusPending, []string{}},
+		{"guest waits", Submission{Text: "Чудовий тур!"}, StatusPending, []string{}},
+		{"link", Submission{Text: "Дивіться deals.com", UserID: &uid, VerifiedBuyer: true}, StatusPending, []string{FlagLinks}},
+		{"banned word inflected", Submission{Text: "Найкраще КАЗИНОчко тут", VerifiedBuyer: true}, StatusPending, []string{FlagBannedWords}},
+		{"duplicate", Submission{Text: "Текст", VerifiedBuyer: true, Duplicate: true}, StatusPending, []string{FlagDuplicate}},
+		{"new account", Submission{Text: "Привіт", UserID: &uid, AccountCreated: &fresh}, StatusPending, []string{FlagNewAccount}},
+	}
+	for _, tt := range tests {
+		status, flags := p.Decide(tt.s, now)
+		if status != tt.status || !reflect.DeepEqual(flags, tt.flags) {
+			t.Errorf("%s: got %s %v, want %s %v", tt.name, status, flags, tt.status, tt.flags)
+		}
+	}
+
+	p.AutoApprove = AutoApproveUsers
+	if status, _ := p.Decide(Submission{Text: "Чудово", UserID: &uid, AccountCreated: &old}, now); status != StatusApproved {
+		t.Errorf("users policy: expected approved, got %s", status)
+	}
+	if status, _ := p.Decide(Submission{Text: "Чудово"}, now); status != StatusPending {
+		t.Errorf("users policy: expected guests to wait, got %s", status)
+	}
+}
+
+func TestBannedWordsWholeWords(t *testing.T) {
+	p := Policy{BannedWords: []string{"sex"}}
+	if p.hasBannedWord("Sussex coast") {
+		t.Error("short banned words should only match whole words")
+	}
+	if !p.hasBannedWord("free SEX here") {
+		t.Error("expected a whole-word match")
+	}
+}
+
+func TestValidReason(t *testing.T) {
+	for _, r := range []string{ReasonSpam, ReasonAbuse, ReasonHate, ReasonOffTopic, ReasonMisleading, ReasonOther} {
+		if !ValidReason(r) {
+			t.Errorf("%q should be valid", r)
+		}
+	}
+	if ValidReason("boring") || ValidReason("") {
+		t.Error("unknown reasons should be invalid")
+	}
+}
+
+func TestEditStatus(t *testing.T) {
+	cases := []struct {
+		name, current string
+		currentFlags  []string
+		screened      string
+		flags         []string
+		want          string
+		wantFlags     []string
+	}{
+		{"clean edit of approved", StatusApproved, nil, StatusPending, []string{}, StatusApproved, []string{}},
+		{"flagged edit of approved", StatusApproved, nil, StatusPending, []string{FlagLinks}, StatusPending, []string{FlagLinks}},
+		{"clean edit of rejected", StatusRejected, nil, StatusApproved, []string{}, StatusPending, []string{}},
+		{"flagged edit of rejected", StatusRejected, nil, StatusPending, []string{FlagBannedWords}, StatusPending, []string{FlagBannedWords}},
+		{"clean edit of pending", StatusPending, []string{FlagNewAccount}, StatusApproved, []string{}, StatusPending, []string{}},
+		{"clean edit of reported", StatusPending, []string{FlagReported}, StatusApproved, []string{}, StatusPending, []string{FlagReported}},
+		{"flagged edit of reported", StatusPending, []string{FlagLinks, FlagReported}, StatusPending, []string{FlagLinks}, StatusPending, []string{FlagLinks, FlagReported}},
+	}
+	for _, c := range cases {
+		got, flags := EditStatus(c.current, c.currentFlags, c.screened, c.flags)
+		if got != c.want || !reflect.DeepEqual(flags, c.wantFlags) {
+			t.Errorf("%s: got %s %v, want %s %v", c.name, got, flags, c.want, c.wantFlags)
+		}
+	}
+}
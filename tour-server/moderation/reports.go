@@ -0,0 +1,64 @@
+package moderation
+
+import (
+	"tour-server/config"
+
+	"gorm.io/gorm"
+)
+
+// FlagReported marks a comment hidden by reader reports.
+const FlagReported = "reported"
+
+// Report reasons
+const (
+	ReasonSpam       = "spam"
+	ReasonAbuse      = "abuse"
+	ReasonHate       = "hate"
+	ReasonOffTopic   = "off_topic"
+	ReasonMisleading = "misleading"
+	ReasonOther      = "other"
+)
+
+// Report statuses
+const (
+	ReportOpen      = "open"
+	ReportResolved  = "resolved"  // the comment was removed
+	ReportDismissed = "dismissed" // the comment stays
+)
+
+// defaultReportThreshold applies when config sets none.
+const defaultReportThreshold = 3
+
+// ValidReason reports whether r is a known report reason.
+func ValidReason(r string) bool {
+	switch r {
+	case ReasonSpam, ReasonAbuse, ReasonHate, ReasonOffTopic, ReasonMisleading, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportThreshold is how many open reports from signed-in users hide a
+// comment.
+func ReportThreshold() int {
+	if n := config.GetConfig().Moderation.ReportThreshold; n > 0 {
+		return n
+	}
+	return defaultReportThreshold
+}
+
+// HideIfReported sends an approved comment back to the moderation queue
+// once it has ReportThreshold open reports from signed-in users. Guest
+// reports are keyed by a token the client makes up, so they only show
+// up in the admin inbox and never hide a comment on their own. Returns
+// whether it was hidden by this call.
+func HideIfReported(db *gorm.DB, commentID uint) (bool, error) {
+	res := db.Exec(`
+		UPDATE tour_reviews
+		SET status = ?, moderation_flags = array_append(moderation_flags, ?)
+		WHERE id = ? AND status = ?
+		  AND (SELECT COUNT(*) FROM comment_reports
+		       WHERE review_id = ? AND status = ? AND user_id IS NOT NULL) >= ?
+	`, StatusPending, FlagReported, commentID, StatusApproved, commentID, ReportOpen, ReportThreshold())
+	return res.RowsAffected > 0, res.Error
+}
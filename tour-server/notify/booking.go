@@ -0,0 +1,164 @@
+package notify
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BookingEvent names a booking lifecycle event that can trigger an SMS.
+type BookingEvent string
+
+const (
+	EventBookingCreated   BookingEvent = "booking_created"
+	EventBookingConfirmed BookingEvent = "booking_confirmed"
+	EventBookingCancelled BookingEvent = "booking_cancelled"
+	EventBookingReminder  BookingEvent = "booking_reminder"
+)
+
+// BookingSMS holds the data used to render a booking SMS.
+type BookingSMS struct {
+	BookingID    uint
+	CustomerName string
+	TourTitle    string
+	DateFrom     time.Time
+	Seats        int
+	TotalPrice   float64
+}
+
+// NotifyBooking sends the SMS for event to the booking's customer, if they
+// opted in to SMS. Runs in a goroutine — fire and forget with logging, the
+// same contract as email.SendAsync.
+func NotifyBooking(db *gorm.DB, event BookingEvent, bookingID uint) {
+	if !IsConfigured() || db == nil || bookingID == 0 {
+		return
+	}
+	go func() {
+		if err := notifyBooking(db, event, bookingID); err != nil {
+			log.Printf("SMS error: %v", err)
+		}
+	}()
+}
+
+func notifyBooking(db *gorm.DB, event BookingEvent, bookingID uint) error {
+	var row struct {
+		ID               uint      `gorm:"column:id"`
+		CustomerName     string    `gorm:"column:customer_name"`
+		CustomerPhone    string    `gorm:"column:customer_phone"`
+		SMSNotifications bool      `gorm:"column:sms_notifications"`
+		Seats            int       `gorm:"column:seats"`
+		TotalPrice       float64   `gorm:"column:total_price"`
+		TourTitle        string    `gorm:"column:tour_title"`
+		DateFrom         time.Time `gorm:"column:date_from"`
+	}
+	err := db.Raw(`
+		SELECT b.id, b.customer_name, b.customer_phone,
+			COALESCE(b.sms_notifications, FALSE) AS sms_notifications,
+			b.seats, b.total_price,
+			t.title AS tour_title, td.date_from
+		FROM bookings b
+		JOIN tour_dates td ON b.tour_date_id = td.id
+		JOIN tours t ON td.tour_id = t.id
+		WHERE b.id = ?
+	`, bookingID).Scan(&row).Error
+	if err != nil || row.ID == 0 {
+		return fmt.Errorf("sms: booking #%d not found", bookingID)
+	}
+
+	if !row.SMSNotifications {
+		return nil
+	}
+
+	phone := NormalizePhone(row.CustomerPhone)
+	if phone == "" {
+		return fmt.Errorf("sms: booking #%d has no valid phone", bookingID)
+	}
+
+	text := BookingText(event, BookingSMS{
+		BookingID:    row.ID,
+		CustomerName: row.CustomerName,
+		TourTitle:    row.TourTitle,
+		DateFrom:     row.DateFrom,
+		Seats:        row.Seats,
+		TotalPrice:   row.TotalPrice,
+	})
+	if text == "" {
+		return fmt.Errorf("sms: no template for event %s", event)
+	}
+
+	return sendLogged(db, &row.ID, dedupeKey(event, row.ID), string(event), phone, text)
+}
+
+// dedupeKey identifies "this event for this booking" — each one is sent at
+// most once, even when both the LiqPay callback and /liqpay/confirm race to
+// report the same payment.
+func dedupeKey(event BookingEvent, bookingID uint) string {
+	return fmt.Sprintf("%s:%d", event, bookingID)
+}
+
+// sendLogged records the message in sms_log and sends it. The insert is
+// guarded by the unique dedupe_key, so a second call with the same key is a
+// silent no-op — unless the earlier attempt failed, in which case the row is
+// claimed again and the send retried.
+func sendLogged(db *gorm.DB, bookingID *uint, key, event, phone, text string) error {
+	var logID uint
+	err := db.Raw(`
+		INSERT INTO sms_log (booking_id, event, phone, body, provider, status, dedupe_key)
+		VALUES (?, ?, ?, ?, ?, 'queued', ?)
+		ON CONFLICT (dedupe_key) DO UPDATE
+		SET status = 'queued', error = NULL, phone = EXCLUDED.phone,
+		    body = EXCLUDED.body, provider = EXCLUDED.provider
+		WHERE sms_log.status = 'failed'
+		RETURNING id
+	`, bookingID, event, phone, text, gateway.Name(), key).Scan(&logID).Error
+	if err != nil {
+		return fmt.Errorf("sms: log insert failed: %w", err)
+	}
+	if logID == 0 {
+		log.Printf("SMS skipped (duplicate): %s", key)
+		return nil
+	}
+
+	providerID, sendErr := send(Message{To: phone, Text: text})
+	if sendErr != nil {
+		db.Exec(
+			"UPDATE sms_log SET status = 'failed', error = ? WHERE id = ?",
+			sendErr.Error(), logID,
+		)
+		return sendErr
+	}
+
+	db.Exec(
+		"UPDATE sms_log SET status = 'sent', provider_message_id = ?, sent_at = NOW() WHERE id = ?",
+		providerID, logID,
+	)
+	log.Printf("SMS sent to %s: %s", phone, key)
+	return nil
+}
+
+// BookingText renders the SMS body for event. SMS are billed per segment,
+// so texts are kept short and carry no links.
+func BookingText(event BookingEvent, d BookingSMS) string {
+	switch event {
+	case EventBookingCreated:
+		return fmt.Sprintf("OpenWorld: бронювання #%d (%s, %s) створено. Сума %s. Очікує оплату.",
+			d.BookingID, d.TourTitle, d.DateFrom.Format("02.01.2006"), formatPrice(d.TotalPrice))
+	case EventBookingConfirmed:
+		return fmt.Sprintf("OpenWorld: бронювання #%d (%s, %s) підтверджено. Гарної подорожі!",
+			d.BookingID, d.TourTitle, d.DateFrom.Format("02.01.2006"))
+	case EventBookingCancelled:
+		return fmt.Sprintf("OpenWorld: бронювання #%d (%s) скасовано.",
+			d.BookingID, d.TourTitle)
+	case EventBookingReminder:
+		return fmt.Sprintf("OpenWorld: нагадуємо, тур %s починається %s. Бронювання #%d, %d міс.",
+			d.TourTitle, d.DateFrom.Format("02.01.2006 о 15:04"), d.BookingID, d.Seats)
+	default:
+		return ""
+	}
+}
+
+func formatPrice(amount float64) string {
+	return fmt.Sprintf("%.0f грн", amount)
+}
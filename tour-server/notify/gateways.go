@@ -0,0 +1,182 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// ─── TurboSMS ──────────────────────────────────────────────────────────────
+
+const turboSMSURL = "https://api.turbosms.ua/message/send.json"
+
+// TurboSMSGateway sends through the TurboSMS HTTP API (https://turbosms.ua).
+type TurboSMSGateway struct {
+	Token   string
+	Sender  string // alpha-name registered in the TurboSMS cabinet
+	BaseURL string // override for tests; defaults to the production API
+}
+
+func (g *TurboSMSGateway) Name() string { return "turbosms" }
+
+func (g *TurboSMSGateway) Send(ctx context.Context, msg Message) (string, error) {
+	payload := map[string]interface{}{
+		"recipients": []string{msg.To},
+		"sms": map[string]string{
+			"sender": g.Sender,
+			"text":   msg.Text,
+		},
+	}
+
+	url := g.BaseURL
+	if url == "" {
+		url = turboSMSURL
+	}
+
+	var resp struct {
+		ResponseCode   int    `json:"response_code"`
+		ResponseStatus string `json:"response_status"`
+		ResponseResult []struct {
+			Phone          string `json:"phone"`
+			ResponseCode   int    `json:"response_code"`
+			MessageID      string `json:"message_id"`
+			ResponseStatus string `json:"response_status"`
+		} `json:"response_result"`
+	}
+	body, err := postJSON(ctx, url, g.Token, payload)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("turbosms: bad response: %w", err)
+	}
+
+	// TurboSMS answers 200 even on rejection — the verdict is in the body.
+	if resp.ResponseCode != 0 && resp.ResponseCode != 800 && resp.ResponseCode != 802 {
+		return "", fmt.Errorf("turbosms: %s (code %d)", resp.ResponseStatus, resp.ResponseCode)
+	}
+	if len(resp.ResponseResult) == 0 {
+		return "", fmt.Errorf("turbosms: empty result")
+	}
+	r := resp.ResponseResult[0]
+	if r.MessageID == "" {
+		return "", fmt.Errorf("turbosms: %s (code %d)", r.ResponseStatus, r.ResponseCode)
+	}
+	return r.MessageID, nil
+}
+
+// ─── Generic HTTP ──────────────────────────────────────────────────────────
+
+// HTTPGateway posts {"to","from","text"} as JSON to an arbitrary URL — for
+// providers fronted by our own relay or anything with a simple webhook API.
+// A 2xx response is success; an optional {"id": "..."} body is recorded.
+type HTTPGateway struct {
+	URL    string
+	Token  string // sent as "Authorization: Bearer <token>" when set
+	Sender string
+}
+
+func (g *HTTPGateway) Name() string { return "http" }
+
+func (g *HTTPGateway) Send(ctx context.Context, msg Message) (string, error) {
+	payload := map[string]string{
+		"to":   msg.To,
+		"from": g.Sender,
+		"text": msg.Text,
+	}
+
+	body, err := postJSON(ctx, g.URL, g.Token, payload)
+	if err != nil {
+		return "", err
+	}
+
+	// The ID is optional — a relay answering plain "OK" is fine.
+	var resp struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &resp)
+	return resp.ID, nil
+}
+
+// ─── Log (local development) ───────────────────────────────────────────────
+
+// LogGateway only writes messages to the server log. It's what the "fake"
+// provider uses, so nothing piles up in memory on a long-running server.
+type LogGateway struct{}
+
+func (LogGateway) Name() string { return "fake" }
+
+func (LogGateway) Send(ctx context.Context, msg Message) (string, error) {
+	log.Printf("SMS (fake) to %s: %s", msg.To, msg.Text)
+	return "", nil
+}
+
+// ─── Fake (tests) ──────────────────────────────────────────────────────────
+
+// FakeGateway records messages in memory instead of sending them.
+type FakeGateway struct {
+	mu   sync.Mutex
+	Sent []Message
+	Err  error // returned from Send when set
+}
+
+func (g *FakeGateway) Name() string { return "fake" }
+
+func (g *FakeGateway) Send(ctx context.Context, msg Message) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.Err != nil {
+		return "", g.Err
+	}
+	g.Sent = append(g.Sent, msg)
+	return fmt.Sprintf("fake-%d", len(g.Sent)), nil
+}
+
+// Messages returns a copy of everything sent so far.
+func (g *FakeGateway) Messages() []Message {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]Message, len(g.Sent))
+	copy(out, g.Sent)
+	return out
+}
+
+// ─── helpers ───────────────────────────────────────────────────────────────
+
+// postJSON sends payload as JSON and returns the response body. Non-2xx
+// statuses are errors.
+func postJSON(ctx context.Context, url, token string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("gateway returned %d: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
@@ -0,0 +1,30 @@
+-- Migration: SMS notifications.
+-- sms_notifications is the customer's opt-in — per account (default for new
+-- bookings) and per booking (what the notify package actually checks).
+-- sms_log records every outgoing SMS; dedupe_key ("<event>:<booking_id>")
+-- makes each booking event go out at most once (a failed send may be retried).
+
+ALTER TABLE tour_users
+    ADD COLUMN IF NOT EXISTS sms_notifications BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS sms_notifications BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS sms_log (
+    id                   SERIAL PRIMARY KEY,
+    booking_id           INTEGER REFERENCES bookings(id) ON DELETE SET NULL,
+    event                VARCHAR(50)  NOT NULL,
+    phone                VARCHAR(20)  NOT NULL,
+    body                 TEXT         NOT NULL,
+    provider             VARCHAR(20)  NOT NULL,
+    status               VARCHAR(10)  NOT NULL DEFAULT 'queued'
+                         CHECK (status IN ('queued', 'sent', 'failed')),
+    provider_message_id  VARCHAR(100),
+    error                TEXT,
+    dedupe_key           VARCHAR(100) NOT NULL UNIQUE,
+    created_at           TIMESTAMP    NOT NULL DEFAULT NOW(),
+    sent_at              TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sms_log_booking_id ON sms_log(booking_id);
+CREATE INDEX IF NOT EXISTS idx_sms_log_created_at ON sms_log(created_at DESC);
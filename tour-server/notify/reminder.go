@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StartReminderJob periodically sends a departure reminder to every
+// confirmed, SMS-opted-in booking whose tour starts within hoursBefore hours.
+// Each booking is reminded once — sms_log's dedupe_key guards repeats;
+// a reminder whose send failed is picked up again on the next run.
+func StartReminderJob(db *gorm.DB, hoursBefore int, interval time.Duration) {
+	if hoursBefore <= 0 {
+		hoursBefore = 48
+	}
+	go func() {
+		for {
+			sendDueReminders(db, hoursBefore)
+			time.Sleep(interval)
+		}
+	}()
+	log.Printf("SMS reminder job started: %dh before departure, every %s", hoursBefore, interval)
+}
+
+func sendDueReminders(db *gorm.DB, hoursBefore int) {
+	if !IsConfigured() {
+		return
+	}
+
+	var ids []uint
+	err := db.Raw(`
+		SELECT b.id
+		FROM bookings b
+		JOIN tour_dates td ON b.tour_date_id = td.id
+		WHERE b.status = 'confirmed'
+		  AND b.sms_notifications = TRUE
+		  AND td.date_from > NOW()
+		  AND td.date_from <= NOW() + make_interval(hours => ?)
+		  AND NOT EXISTS (
+			SELECT 1 FROM sms_log s
+			WHERE s.dedupe_key = 'booking_reminder:' || b.id
+			  AND s.status <> 'failed'
+		  )
+	`, hoursBefore).Scan(&ids).Error
+	if err != nil {
+		log.Printf("SMS reminder query failed: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		if err := notifyBooking(db, EventBookingReminder, id); err != nil {
+			log.Printf("SMS reminder error: %v", err)
+		}
+	}
+	if len(ids) > 0 {
+		log.Printf("SMS reminders processed: %d", len(ids))
+	}
+}
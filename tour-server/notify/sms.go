@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Message is a single outgoing SMS.
+type Message struct {
+	To   string // digits only, international format: 380XXXXXXXXX
+	Text string
+}
+
+// Gateway delivers SMS through a provider. Send returns the provider's
+// message ID (empty if the provider doesn't return one).
+type Gateway interface {
+	Name() string
+	Send(ctx context.Context, msg Message) (string, error)
+}
+
+var gateway Gateway
+
+// sendTimeout bounds a single provider call so a slow gateway can't pile up
+// goroutines behind booking requests.
+const sendTimeout = 15 * time.Second
+
+// Init sets the SMS gateway. Call once at startup.
+func Init(gw Gateway) {
+	gateway = gw
+	log.Printf("SMS service initialized: %s gateway", gw.Name())
+}
+
+// IsConfigured returns true if an SMS gateway has been initialized.
+func IsConfigured() bool {
+	return gateway != nil
+}
+
+// NewGateway builds the gateway named by provider ("turbosms", "http", or
+// "fake" for local development — messages are only logged).
+func NewGateway(provider, sender, token, url string) (Gateway, error) {
+	switch strings.ToLower(provider) {
+	case "turbosms":
+		if token == "" {
+			return nil, fmt.Errorf("sms: turbosms requires a token")
+		}
+		return &TurboSMSGateway{Token: token, Sender: sender}, nil
+	case "http":
+		if url == "" {
+			return nil, fmt.Errorf("sms: http gateway requires a url")
+		}
+		return &HTTPGateway{URL: url, Token: token, Sender: sender}, nil
+	case "fake":
+		return LogGateway{}, nil
+	default:
+		return nil, fmt.Errorf("sms: unknown provider %q", provider)
+	}
+}
+
+// send delivers one message through the configured gateway.
+func send(msg Message) (string, error) {
+	if !IsConfigured() {
+		return "", fmt.Errorf("sms: not configured, skipping send to %s", msg.To)
+	}
+	if msg.To == "" {
+		return "", fmt.Errorf("sms: empty recipient")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	id, err := gateway.Send(ctx, msg)
+	if err != nil {
+		return "", fmt.Errorf("sms: send to %s via %s failed: %w", msg.To, gateway.Name(), err)
+	}
+	return id, nil
+}
+
+// NormalizePhone turns a stored customer phone ("+380 95 123-45-67",
+// "0951234567") into the digits-only form gateways expect. Returns "" if
+// the number isn't a Ukrainian mobile number.
+func NormalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+
+	switch {
+	case len(digits) == 12 && strings.HasPrefix(digits, "380"):
+		return digits
+	case len(digits) == 10 && digits[0] == '0':
+		return "38" + digits
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,176 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"+380951234567", "380951234567"},
+		{"+380 95 123-45-67", "380951234567"},
+		{"(095) 123 45 67", "380951234567"},
+		{"0951234567", "380951234567"},
+		{"12345", ""},
+		{"+14155552671", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizePhone(tt.in); got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBookingText(t *testing.T) {
+	d := BookingSMS{
+		BookingID:  42,
+		TourTitle:  "Дубай",
+		DateFrom:   time.Date(2026, 7, 1, 9, 30, 0, 0, time.UTC),
+		Seats:      2,
+		TotalPrice: 90400,
+	}
+
+	events := []BookingEvent{
+		EventBookingCreated, EventBookingConfirmed,
+		EventBookingCancelled, EventBookingReminder,
+	}
+	for _, ev := range events {
+		text := BookingText(ev, d)
+		if !strings.Contains(text, "#42") {
+			t.Errorf("%s: expected booking ID in %q", ev, text)
+		}
+		if !strings.Contains(text, "Дубай") {
+			t.Errorf("%s: expected tour title in %q", ev, text)
+		}
+	}
+
+	if text := BookingText(EventBookingCreated, d); !strings.Contains(text, "90400 грн") {
+		t.Errorf("expected price in created text, got %q", text)
+	}
+	if text := BookingText("unknown", d); text != "" {
+		t.Errorf("expected empty text for unknown event, got %q", text)
+	}
+}
+
+func TestTurboSMSGateway_Send(t *testing.T) {
+	var got struct {
+		Recipients []string          `json:"recipients"`
+		SMS        map[string]string `json:"sms"`
+	}
+	var auth string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"response_code":800,"response_status":"SUCCESS_MESSAGE_ACCEPTED",
+			"response_result":[{"phone":"380951234567","response_code":0,"message_id":"abc-1","response_status":"OK"}]}`))
+	}))
+	defer srv.Close()
+
+	gw := &TurboSMSGateway{Token: "secret", Sender: "OpenWorld", BaseURL: srv.URL}
+	id, err := gw.Send(context.Background(), Message{To: "380951234567", Text: "hello"})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if id != "abc-1" {
+		t.Errorf("expected message id abc-1, got %q", id)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected bearer auth, got %q", auth)
+	}
+	if len(got.Recipients) != 1 || got.Recipients[0] != "380951234567" {
+		t.Errorf("unexpected recipients: %v", got.Recipients)
+	}
+	if got.SMS["sender"] != "OpenWorld" || got.SMS["text"] != "hello" {
+		t.Errorf("unexpected sms body: %v", got.SMS)
+	}
+}
+
+func TestTurboSMSGateway_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response_code":103,"response_status":"REQUIRED_TOKEN","response_result":null}`))
+	}))
+	defer srv.Close()
+
+	gw := &TurboSMSGateway{Token: "bad", Sender: "OpenWorld", BaseURL: srv.URL}
+	if _, err := gw.Send(context.Background(), Message{To: "380951234567", Text: "hello"}); err == nil {
+		t.Error("expected error for rejected request")
+	}
+}
+
+func TestHTTPGateway_Send(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("OK"))
+	}))
+	defer srv.Close()
+
+	gw := &HTTPGateway{URL: srv.URL, Sender: "OpenWorld"}
+	if _, err := gw.Send(context.Background(), Message{To: "380951234567", Text: "hi"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got["to"] != "380951234567" || got["from"] != "OpenWorld" || got["text"] != "hi" {
+		t.Errorf("unexpected payload: %v", got)
+	}
+}
+
+func TestHTTPGateway_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	gw := &HTTPGateway{URL: srv.URL}
+	if _, err := gw.Send(context.Background(), Message{To: "380951234567", Text: "hi"}); err == nil {
+		t.Error("expected error for 502 response")
+	}
+}
+
+func TestSend_UsesConfiguredGateway(t *testing.T) {
+	fake := &FakeGateway{}
+	Init(fake)
+	defer func() { gateway = nil }()
+
+	if _, err := send(Message{To: "380951234567", Text: "test"}); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if _, err := send(Message{To: "", Text: "test"}); err == nil {
+		t.Error("expected error for empty recipient")
+	}
+
+	msgs := fake.Messages()
+	if len(msgs) != 1 || msgs[0].Text != "test" {
+		t.Errorf("unexpected messages: %v", msgs)
+	}
+}
+
+func TestNewGateway(t *testing.T) {
+	if _, err := NewGateway("turbosms", "OpenWorld", "", ""); err == nil {
+		t.Error("expected error for turbosms without token")
+	}
+	if _, err := NewGateway("http", "OpenWorld", "", ""); err == nil {
+		t.Error("expected error for http gateway without url")
+	}
+	if _, err := NewGateway("pigeon", "", "", ""); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+	if gw, err := NewGateway("TurboSMS", "OpenWorld", "tok", ""); err != nil || gw.Name() != "turbosms" {
+		t.Errorf("expected turbosms gateway, got %v, %v", gw, err)
+	}
+	if gw, err := NewGateway("fake", "", "", ""); err != nil {
+		t.Errorf("fake gateway: %v", err)
+	} else if _, ok := gw.(LogGateway); !ok {
+		t.Errorf("expected fake provider to only log, got %T", gw)
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"tour-server/recommendations"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// parseLimit reads ?limit=, 1..maxLimit.
+func parseLimit(c echo.Context, def, maxLimit int) int {
+	if v, err := strconv.Atoi(c.QueryParam("limit")); err == nil && v > 0 && v <= maxLimit {
+		return v
+	}
+	return def
+}
+
+// GET /tours/:id/similar?limit=8
+func GetSimilarTours(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Невірний ID туру"})
+		}
+		limit := parseLimit(c, 8, 20)
+
+		var exists int64
+		db.Raw("SELECT COUNT(*) FROM tours WHERE id = ?", tourID).Scan(&exists)
+		if exists == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Тур не знайдено"})
+		}
+
+		tours, err := recommendations.Similar(db, uint(tourID), limit)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch similar tours"})
+		}
+		return c.JSON(http.StatusOK, tours)
+	}
+}
+
+// GET /recommendations?limit=12 — personal picks; popular tours until
+// the user has viewed, saved or booked something
+func GetRecommendations(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, ok := c.Get("user_id").(uint)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Необхідна авторизація"})
+		}
+		limit := parseLimit(c, 12, 50)
+
+		tours, personalized, err := recommendations.ForUser(db, userID, limit)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch recommendations"})
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"tours":        tours,
+			"personalized": personalized,
+		})
+	}
+}
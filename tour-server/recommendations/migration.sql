@@ -0,0 +1,25 @@
+-- Migration: tour recommendations
+-- Both tables are rebuilt by the recommendations batch job
+-- (recommendations.StartJob); nothing else writes to them.
+
+-- Top similar tours per tour: behaviour (people who showed interest in
+-- or booked both) blended with content (destination, categories, price,
+-- duration).
+CREATE TABLE IF NOT EXISTS tour_similarities (
+    tour_id          INTEGER NOT NULL REFERENCES tours(id) ON DELETE CASCADE,
+    similar_tour_id  INTEGER NOT NULL REFERENCES tours(id) ON DELETE CASCADE,
+    score            REAL NOT NULL,              -- 0..1
+    co_interest      INTEGER NOT NULL DEFAULT 0, -- people who viewed, saved, rated 4+ or booked both
+    co_bookings      INTEGER NOT NULL DEFAULT 0, -- people who booked both
+    computed_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tour_id, similar_tour_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tour_similarities_score ON tour_similarities(tour_id, score DESC);
+
+-- Recent popularity, the cold-start fallback.
+CREATE TABLE IF NOT EXISTS tour_popularity (
+    tour_id      INTEGER PRIMARY KEY REFERENCES tours(id) ON DELETE CASCADE,
+    score        REAL NOT NULL,
+    computed_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
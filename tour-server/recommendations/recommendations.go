@@ -0,0 +1,296 @@
+// Package recommendations computes "similar tours" and personal
+// recommendations from views, favourites, ratings and bookings plus tour
+// content (see migration.sql). A periodic batch job rebuilds the tables;
+// requests only read them. Tours nobody has interacted with fall back to
+// popular ones.
+package recommendations
+
+import (
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PerTour is how many similar tours are kept for each tour.
+const PerTour = 20
+
+// Reasons a tour was recommended
+const (
+	ReasonSimilar = "similar"
+	ReasonForYou  = "for_you"
+	ReasonPopular = "popular"
+)
+
+// Tour is a recommended tour card.
+type Tour struct {
+	ID       uint    `json:"id" gorm:"column:id"`
+	Title    string  `json:"title" gorm:"column:title"`
+	Price    float64 `json:"price" gorm:"column:price"`
+	Rating   float64 `json:"rating" gorm:"column:rating"`
+	ImageSrc string  `json:"image_src" gorm:"column:image_src"`
+	Duration int     `json:"duration" gorm:"column:duration"`
+	Location string  `json:"location" gorm:"column:location"`
+	Score    float64 `json:"score" gorm:"column:score"`
+	Reason   string  `json:"reason" gorm:"column:reason"`
+}
+
+// cardColumns reads a Tour from tour_search_index tsi.
+const cardColumns = `
+	tsi.tour_id AS id, tsi.title, tsi.price, tsi.rating,
+	COALESCE(tsi.image_src, 'no-image.jpg') AS image_src,
+	tsi.duration, tsi.destination AS location`
+
+const activeStatus = "(SELECT id FROM statuses WHERE name = 'active')"
+
+// interestSQL is one row per person and tour they showed interest in
+// over the last year. Signed-in users are 'u<id>', guests who booked are
+// 'g<email>'.
+const interestSQL = `
+	WITH interest AS (
+		SELECT actor, tour_id, bool_or(booked) AS booked
+		FROM (
+			SELECT 'u' || user_id AS actor, tour_id, FALSE AS booked
+			FROM tour_views WHERE viewed_at > NOW() - INTERVAL '1 year'
+			UNION ALL
+			SELECT 'u' || user_id, tour_id, FALSE
+			FROM tour_user_favorites WHERE created_at > NOW() - INTERVAL '1 year'
+			UNION ALL
+			SELECT 'u' || user_id, tour_id, FALSE
+			FROM tour_ratings WHERE rating >= 4 AND updated_at > NOW() - INTERVAL '1 year'
+			UNION ALL
+			SELECT COALESCE('u' || b.user_id, 'g' || LOWER(NULLIF(b.customer_email, ''))), td.tour_id, TRUE
+			FROM bookings b JOIN tour_dates td ON td.id = b.tour_date_id
+			WHERE b.status <> 'cancelled' AND b.booked_at > NOW() - INTERVAL '1 year'
+		) i
+		WHERE actor IS NOT NULL
+		GROUP BY actor, tour_id
+	)`
+
+// Compute rebuilds tour_similarities and tour_popularity. Returns how
+// many similarity rows were written.
+func Compute(db *gorm.DB) (int, error) {
+	var tours []tourFeatures
+	err := db.Raw(`
+		SELECT tsi.tour_id AS id, tsi.price, tsi.duration,
+			tsi.destination_city_id AS city_id, ci.country_id, co.region_id,
+			COALESCE(array_agg(tc.category_id) FILTER (WHERE tc.category_id IS NOT NULL), '{}') AS categories
+		FROM tour_search_index tsi
+		LEFT JOIN cities ci ON ci.id = tsi.destination_city_id
+		LEFT JOIN countries co ON co.id = ci.country_id
+		LEFT JOIN tour_categories tc ON tc.tour_id = tsi.tour_id
+		WHERE tsi.status_id = ` + activeStatus + `
+		GROUP BY tsi.tour_id, tsi.price, tsi.duration, tsi.destination_city_id, ci.country_id, co.region_id
+		ORDER BY tsi.tour_id
+	`).Scan(&tours).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var pairs []pairStats
+	err = db.Raw(interestSQL + `
+		SELECT a.tour_id, b.tour_id AS other_id,
+			COUNT(*) AS co_interest,
+			COUNT(*) FILTER (WHERE a.booked AND b.booked) AS co_bookings
+		FROM interest a
+		JOIN interest b ON b.actor = a.actor AND b.tour_id > a.tour_id
+		GROUP BY a.tour_id, b.tour_id
+	`).Scan(&pairs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var counts []struct {
+		TourID uint `gorm:"column:tour_id"`
+		People int  `gorm:"column:people"`
+	}
+	err = db.Raw(interestSQL + `
+		SELECT tour_id, COUNT(*) AS people FROM interest GROUP BY tour_id
+	`).Scan(&counts).Error
+	if err != nil {
+		return 0, err
+	}
+	interest := make(map[uint]int, len(counts))
+	for _, c := range counts {
+		interest[c.TourID] = c.People
+	}
+
+	rows := scoreAll(tours, pairs, interest, PerTour)
+	ids := make(pq.Int64Array, len(rows))
+	similar := make(pq.Int64Array, len(rows))
+	scores := make(pq.Float64Array, len(rows))
+	coInterest := make(pq.Int64Array, len(rows))
+	coBookings := make(pq.Int64Array, len(rows))
+	for i, r := range rows {
+		ids[i], similar[i], scores[i] = int64(r.TourID), int64(r.SimilarID), r.Score
+		coInterest[i], coBookings[i] = int64(r.CoInterest), int64(r.CoBookings)
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	if err := tx.Exec("DELETE FROM tour_similarities").Error; err != nil {
+		return 0, err
+	}
+	err = tx.Exec(`
+		INSERT INTO tour_similarities (tour_id, similar_tour_id, score, co_interest, co_bookings)
+		SELECT * FROM unnest(?::int[], ?::int[], ?::real[], ?::int[], ?::int[])
+	`, ids, similar, scores, coInterest, coBookings).Error
+	if err != nil {
+		return 0, err
+	}
+
+	// Popularity: bookings and favourites over 90 days, views over 30,
+	// plus the rating as a tie-breaker.
+	if err := tx.Exec("DELETE FROM tour_popularity").Error; err != nil {
+		return 0, err
+	}
+	err = tx.Exec(`
+		INSERT INTO tour_popularity (tour_id, score)
+		SELECT tsi.tour_id,
+			4 * COALESCE(b.n, 0) + 2 * COALESCE(f.n, 0) + COALESCE(v.n, 0) + tsi.rating
+		FROM tour_search_index tsi
+		LEFT JOIN (
+			SELECT td.tour_id, COUNT(*) AS n
+			FROM bookings b JOIN tour_dates td ON td.id = b.tour_date_id
+			WHERE b.status <> 'cancelled' AND b.booked_at > NOW() - INTERVAL '90 days'
+			GROUP BY td.tour_id
+		) b ON b.tour_id = tsi.tour_id
+		LEFT JOIN (
+			SELECT tour_id, COUNT(*) AS n FROM tour_user_favorites
+			WHERE created_at > NOW() - INTERVAL '90 days' GROUP BY tour_id
+		) f ON f.tour_id = tsi.tour_id
+		LEFT JOIN (
+			SELECT tour_id, COUNT(*) AS n FROM tour_views
+			WHERE viewed_at > NOW() - INTERVAL '30 days' GROUP BY tour_id
+		) v ON v.tour_id = tsi.tour_id
+		WHERE tsi.status_id = ` + activeStatus + `
+	`).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// StartJob runs Compute every interval in a goroutine.
+func StartJob(db *gorm.DB, interval time.Duration) {
+	go func() {
+		for {
+			start := time.Now()
+			if n, err := Compute(db); err != nil {
+				log.Printf("Recommendations: compute failed: %v", err)
+			} else {
+				log.Printf("Recommendations: %d similar pairs in %s", n, time.Since(start).Round(time.Millisecond))
+			}
+			time.Sleep(interval)
+		}
+	}()
+	log.Printf("Recommendations job started (every %s)", interval)
+}
+
+// Similar returns the tours most similar to tourID, topped up with
+// popular tours when the job hasn't covered it yet (e.g. a new tour).
+func Similar(db *gorm.DB, tourID uint, limit int) ([]Tour, error) {
+	tours := []Tour{}
+	err := db.Raw(`
+		SELECT `+cardColumns+`, ts.score, ? AS reason
+		FROM tour_similarities ts
+		JOIN tour_search_index tsi ON tsi.tour_id = ts.similar_tour_id
+		WHERE ts.tour_id = ? AND tsi.status_id = `+activeStatus+`
+		ORDER BY ts.score DESC, tsi.tour_id
+		LIMIT ?
+	`, ReasonSimilar, tourID, limit).Scan(&tours).Error
+	if err != nil {
+		return nil, err
+	}
+	return topUp(db, tours, []uint{tourID}, limit)
+}
+
+// ForUser recommends tours similar to the ones userID viewed, saved,
+// rated highly or booked, weighting stronger signals more. Tours the
+// user already interacted with are left out. personalized is false when
+// there was nothing to go on and only popular tours were returned.
+func ForUser(db *gorm.DB, userID uint, limit int) (tours []Tour, personalized bool, err error) {
+	tours = []Tour{}
+	err = db.Raw(`
+		WITH seeds AS (
+			SELECT tour_id, MAX(w) AS w
+			FROM (
+				SELECT tour_id, 1.0 AS w FROM tour_views WHERE user_id = @user
+				UNION ALL
+				SELECT tour_id, 2.0 FROM tour_user_favorites WHERE user_id = @user
+				UNION ALL
+				SELECT tour_id, 2.0 FROM tour_ratings WHERE user_id = @user AND rating >= 4
+				UNION ALL
+				SELECT td.tour_id, 3.0
+				FROM bookings b JOIN tour_dates td ON td.id = b.tour_date_id
+				WHERE b.user_id = @user AND b.status <> 'cancelled'
+			) s
+			GROUP BY tour_id
+		)
+		SELECT `+cardColumns+`, SUM(s.w * ts.score) AS score, @reason AS reason
+		FROM seeds s
+		JOIN tour_similarities ts ON ts.tour_id = s.tour_id
+		JOIN tour_search_index tsi ON tsi.tour_id = ts.similar_tour_id
+		WHERE tsi.status_id = `+activeStatus+`
+			AND ts.similar_tour_id NOT IN (SELECT tour_id FROM seeds)
+		GROUP BY tsi.tour_id, tsi.title, tsi.price, tsi.rating, tsi.image_src, tsi.duration, tsi.destination
+		ORDER BY score DESC, tsi.tour_id
+		LIMIT @limit
+	`, map[string]interface{}{"user": userID, "reason": ReasonForYou, "limit": limit}).Scan(&tours).Error
+	if err != nil {
+		return nil, false, err
+	}
+	personalized = len(tours) > 0
+
+	var seen []uint
+	err = db.Raw(`
+		SELECT td.tour_id FROM bookings b JOIN tour_dates td ON td.id = b.tour_date_id
+		WHERE b.user_id = ? AND b.status <> 'cancelled'
+	`, userID).Scan(&seen).Error
+	if err != nil {
+		return nil, false, err
+	}
+	tours, err = topUp(db, tours, seen, limit)
+	return tours, personalized, err
+}
+
+// Popular returns the most popular active tours not in exclude, bookable
+// ones first.
+func Popular(db *gorm.DB, exclude []uint, limit int) ([]Tour, error) {
+	ids := make(pq.Int64Array, len(exclude))
+	for i, id := range exclude {
+		ids[i] = int64(id)
+	}
+	tours := []Tour{}
+	err := db.Raw(`
+		SELECT `+cardColumns+`, COALESCE(tp.score, 0) AS score, ? AS reason
+		FROM tour_search_index tsi
+		LEFT JOIN tour_popularity tp ON tp.tour_id = tsi.tour_id
+		WHERE tsi.status_id = `+activeStatus+` AND tsi.tour_id <> ALL(?::int[])
+		ORDER BY (tsi.next_departure_at IS NOT NULL) DESC, score DESC, tsi.rating DESC, tsi.tour_id
+		LIMIT ?
+	`, ReasonPopular, ids, limit).Scan(&tours).Error
+	return tours, err
+}
+
+// topUp fills tours up to limit with popular tours, skipping exclude and
+// tours already in the list.
+func topUp(db *gorm.DB, tours []Tour, exclude []uint, limit int) ([]Tour, error) {
+	if len(tours) >= limit {
+		return tours, nil
+	}
+	for _, t := range tours {
+		exclude = append(exclude, t.ID)
+	}
+	more, err := Popular(db, exclude, limit-len(tours))
+	if err != nil {
+		return nil, err
+	}
+	return append(tours, more...), nil
+}
@@ -0,0 +1,158 @@
+package recommendations
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+// Blend of the two signals. Pairs nobody has shown interest in together
+// are ranked on content alone.
+const (
+	behaviourWeight = 0.6
+	contentWeight   = 0.4
+)
+
+// Content feature weights; they add up to 1.
+const (
+	locationWeight = 0.4
+	categoryWeight = 0.25
+	priceWeight    = 0.2
+	durationWeight = 0.15
+)
+
+// tourFeatures is what content similarity compares; active tours only.
+type tourFeatures struct {
+	ID         uint          `gorm:"column:id"`
+	Price      float64       `gorm:"column:price"`
+	Duration   int           `gorm:"column:duration"`
+	CityID     *uint         `gorm:"column:city_id"`
+	CountryID  *uint         `gorm:"column:country_id"`
+	RegionID   *uint         `gorm:"column:region_id"`
+	Categories pq.Int64Array `gorm:"column:categories"`
+}
+
+// pairStats counts the people who showed interest in (CoInterest) or
+// booked (CoBookings) both tours; TourID < OtherID.
+type pairStats struct {
+	TourID     uint `gorm:"column:tour_id"`
+	OtherID    uint `gorm:"column:other_id"`
+	CoInterest int  `gorm:"column:co_interest"`
+	CoBookings int  `gorm:"column:co_bookings"`
+}
+
+type pairKey struct{ a, b uint }
+
+// similarity is one scored row of tour_similarities.
+type similarity struct {
+	TourID     uint
+	SimilarID  uint
+	Score      float64
+	CoInterest int
+	CoBookings int
+}
+
+// contentScore compares destination, categories, price and duration;
+// 0..1.
+func contentScore(a, b tourFeatures) float64 {
+	location := 0.0
+	switch {
+	case sameID(a.CityID, b.CityID):
+		location = 1
+	case sameID(a.CountryID, b.CountryID):
+		location = 0.7
+	case sameID(a.RegionID, b.RegionID):
+		location = 0.3
+	}
+	return locationWeight*location +
+		categoryWeight*jaccard(a.Categories, b.Categories) +
+		priceWeight*closeness(a.Price, b.Price) +
+		durationWeight*closeness(float64(a.Duration), float64(b.Duration))
+}
+
+// behaviourScore is the cosine similarity of the two tours' audiences,
+// counting a shared booking three times; 0..1. interestA and interestB
+// are how many people showed interest in each tour.
+func behaviourScore(p pairStats, interestA, interestB int) float64 {
+	if interestA == 0 || interestB == 0 {
+		return 0
+	}
+	s := float64(p.CoInterest+2*p.CoBookings) / math.Sqrt(float64(interestA)*float64(interestB))
+	return math.Min(s, 1)
+}
+
+// scoreAll scores every pair of tours and keeps the best perTour
+// similar tours for each, best first.
+func scoreAll(tours []tourFeatures, pairs []pairStats, interest map[uint]int, perTour int) []similarity {
+	byPair := make(map[pairKey]pairStats, len(pairs))
+	for _, p := range pairs {
+		byPair[pairKey{p.TourID, p.OtherID}] = p
+	}
+
+	candidates := make(map[uint][]similarity, len(tours))
+	for i := range tours {
+		for j := i + 1; j < len(tours); j++ {
+			a, b := tours[i], tours[j]
+			key := pairKey{a.ID, b.ID}
+			if b.ID < a.ID {
+				key = pairKey{b.ID, a.ID}
+			}
+			p := byPair[key]
+			score := behaviourWeight*behaviourScore(p, interest[a.ID], interest[b.ID]) +
+				contentWeight*contentScore(a, b)
+			if score <= 0 {
+				continue
+			}
+			candidates[a.ID] = append(candidates[a.ID], similarity{a.ID, b.ID, score, p.CoInterest, p.CoBookings})
+			candidates[b.ID] = append(candidates[b.ID], similarity{b.ID, a.ID, score, p.CoInterest, p.CoBookings})
+		}
+	}
+
+	var out []similarity
+	for _, t := range tours {
+		list := candidates[t.ID]
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Score != list[j].Score {
+				return list[i].Score > list[j].Score
+			}
+			return list[i].SimilarID < list[j].SimilarID
+		})
+		if len(list) > perTour {
+			list = list[:perTour]
+		}
+		out = append(out, list...)
+	}
+	return out
+}
+
+func sameID(a, b *uint) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// closeness is 1 for equal values, falling to 0 as one approaches zero
+// relative to the other.
+func closeness(a, b float64) float64 {
+	hi := math.Max(a, b)
+	if hi <= 0 {
+		return 0
+	}
+	return 1 - math.Abs(a-b)/hi
+}
+
+func jaccard(a, b []int64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[int64]bool, len(a))
+	for _, v := range a {
+		set[v] = true
+	}
+	shared := 0
+	for _, v := range b {
+		if set[v] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
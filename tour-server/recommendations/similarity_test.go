@@ -0,0 +1,58 @@
+package recommendations
+
+import (
+	"math"
+	"testing"
+)
+
+func id(v uint) *uint { return &v }
+
+func TestContentScore(t *testing.T) {
+	a := tourFeatures{ID: 1, Price: 10000, Duration: 7, CityID: id(1), CountryID: id(1), RegionID: id(1), Categories: []int64{1, 2}}
+
+	same := a
+	same.ID = 2
+	if s := contentScore(a, same); math.Abs(s-1) > 1e-9 {
+		t.Errorf("identical features should score 1, got %v", s)
+	}
+
+	far := tourFeatures{ID: 3, Price: 40000, Duration: 14, CityID: id(9), CountryID: id(9), RegionID: id(9), Categories: []int64{5}}
+	sameCountry := tourFeatures{ID: 4, Price: 40000, Duration: 14, CityID: id(2), CountryID: id(1), RegionID: id(1), Categories: []int64{5}}
+	if contentScore(a, sameCountry) <= contentScore(a, far) {
+		t.Error("a tour in the same country should score higher")
+	}
+}
+
+func TestBehaviourScore(t *testing.T) {
+	if s := behaviourScore(pairStats{CoInterest: 2}, 0, 4); s != 0 {
+		t.Errorf("expected 0 without audience, got %v", s)
+	}
+	if s := behaviourScore(pairStats{CoInterest: 2}, 4, 4); s != 0.5 {
+		t.Errorf("expected 0.5, got %v", s)
+	}
+	if s := behaviourScore(pairStats{CoInterest: 4, CoBookings: 4}, 4, 4); s != 1 {
+		t.Errorf("expected score capped at 1, got %v", s)
+	}
+}
+
+func TestScoreAll(t *testing.T) {
+	tours := []tourFeatures{
+		{ID: 1, Price: 10000, Duration: 7},
+		{ID: 2, Price: 10000, Duration: 7},
+		{ID: 3, Price: 10000, Duration: 7},
+	}
+	// Content is identical; the pair 1–3 is also booked together.
+	pairs := []pairStats{{TourID: 1, OtherID: 3, CoInterest: 3, CoBookings: 1}}
+	interest := map[uint]int{1: 3, 3: 3}
+
+	rows := scoreAll(tours, pairs, interest, 1)
+	if len(rows) != 3 {
+		t.Fatalf("expected one row per tour, got %d", len(rows))
+	}
+	if rows[0].TourID != 1 || rows[0].SimilarID != 3 || rows[0].CoBookings != 1 {
+		t.Errorf("expected 1 → 3 first, got %+v", rows[0])
+	}
+	if rows[1].TourID != 2 || rows[1].SimilarID != 1 {
+		t.Errorf("expected ties broken by id, got %+v", rows[1])
+	}
+}
@@ -0,0 +1,320 @@
+package savedsearches
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+	"tour-server/email"
+	search "tour-server/search/api"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Each run looks at up to maxPages pages of 100 results per search.
+const maxPages = 5
+
+// checkEvery is how often one saved search is re-run.
+const checkEvery = 24 * time.Hour
+
+// Match is a tour a saved search returned, with its open departures.
+type Match struct {
+	TourID     uint
+	Title      string
+	Price      float64
+	Departures []Departure
+}
+
+type Departure struct {
+	ID       uint      `gorm:"column:id"`
+	TourID   uint      `gorm:"column:tour_id"`
+	DateFrom time.Time `gorm:"column:date_from"`
+	DateTo   time.Time `gorm:"column:date_to"`
+}
+
+// PriceDrop is a reported tour that got cheaper since it was last seen.
+type PriceDrop struct {
+	Match
+	OldPrice float64
+}
+
+// Changes is what a run found that the user hasn't been told about.
+// NewDepartures holds already reported tours with only their new
+// departures.
+type Changes struct {
+	NewTours      []Match
+	PriceDrops    []PriceDrop
+	NewDepartures []Match
+}
+
+func (c Changes) Empty() bool {
+	return len(c.NewTours) == 0 && len(c.PriceDrops) == 0 && len(c.NewDepartures) == 0
+}
+
+// diff compares a run's matches with the tours (id → last seen price) and
+// departures reported before.
+func diff(seenTours map[uint]float64, seenDepartures map[uint]bool, matches []Match) Changes {
+	var ch Changes
+	for _, m := range matches {
+		oldPrice, seen := seenTours[m.TourID]
+		if !seen {
+			ch.NewTours = append(ch.NewTours, m)
+			continue
+		}
+		if m.Price < oldPrice {
+			ch.PriceDrops = append(ch.PriceDrops, PriceDrop{Match: m, OldPrice: oldPrice})
+		}
+		var fresh []Departure
+		for _, d := range m.Departures {
+			if !seenDepartures[d.ID] {
+				fresh = append(fresh, d)
+			}
+		}
+		if len(fresh) > 0 {
+			nm := m
+			nm.Departures = fresh
+			ch.NewDepartures = append(ch.NewDepartures, nm)
+		}
+	}
+	return ch
+}
+
+// StartAlertJob checks due saved searches every interval in a goroutine.
+// Each search is re-run once a day.
+func StartAlertJob(db *gorm.DB, interval time.Duration) {
+	go func() {
+		for {
+			RunAlerts(db)
+			time.Sleep(interval)
+		}
+	}()
+	log.Printf("Saved search alert job started (every %s)", interval)
+}
+
+type dueSearch struct {
+	SavedSearch
+	Email    string `gorm:"column:email"`
+	UserName string `gorm:"column:user_name"`
+}
+
+// RunAlerts re-runs every active saved search not checked in the last
+// day. The first run of a search only records what it matches.
+func RunAlerts(db *gorm.DB) {
+	var due []dueSearch
+	err := db.Raw(`
+		SELECT s.*, u.email, u.name AS user_name
+		FROM saved_searches s
+		JOIN tour_users u ON u.id = s.user_id
+		WHERE NOT s.is_paused
+			AND (s.last_checked_at IS NULL OR s.last_checked_at <= ?)
+		ORDER BY s.last_checked_at NULLS FIRST
+		LIMIT 200
+	`, time.Now().Add(-checkEvery)).Scan(&due).Error
+	if err != nil {
+		log.Printf("Saved search alerts: %v", err)
+		return
+	}
+
+	for _, s := range due {
+		// Claim the search so that another instance skips it
+		claim := db.Exec(`
+			UPDATE saved_searches SET last_checked_at = NOW()
+			WHERE id = ? AND last_checked_at IS NOT DISTINCT FROM ?
+		`, s.ID, s.LastCheckedAt)
+		if claim.Error != nil || claim.RowsAffected == 0 {
+			continue
+		}
+		if err := check(db, s); err != nil {
+			log.Printf("Saved search %d: %v", s.ID, err)
+		}
+	}
+}
+
+func check(db *gorm.DB, s dueSearch) error {
+	matches, err := run(db, s.Query)
+	if err != nil {
+		return err
+	}
+
+	var seenRows []struct {
+		TourID uint    `gorm:"column:tour_id"`
+		Price  float64 `gorm:"column:price"`
+	}
+	if err := db.Raw("SELECT tour_id, price FROM saved_search_tours WHERE saved_search_id = ?", s.ID).
+		Scan(&seenRows).Error; err != nil {
+		return err
+	}
+	seenTours := make(map[uint]float64, len(seenRows))
+	for _, r := range seenRows {
+		seenTours[r.TourID] = r.Price
+	}
+	var depIDs []uint
+	if err := db.Raw("SELECT tour_date_id FROM saved_search_departures WHERE saved_search_id = ?", s.ID).
+		Scan(&depIDs).Error; err != nil {
+		return err
+	}
+	seenDepartures := make(map[uint]bool, len(depIDs))
+	for _, id := range depIDs {
+		seenDepartures[id] = true
+	}
+
+	changes := diff(seenTours, seenDepartures, matches)
+	if err := record(db, s.ID, matches); err != nil {
+		return err
+	}
+
+	if s.LastCheckedAt == nil || changes.Empty() {
+		return nil
+	}
+	email.NotifySavedSearchAlert(s.Email, alertEmail(s, changes))
+	db.Exec("UPDATE saved_searches SET last_alert_at = NOW() WHERE id = ?", s.ID)
+	return nil
+}
+
+// run executes the saved query and collects the matching tours with their
+// open departures: the ones fitting the dates and party size if the query
+// has them, otherwise every upcoming departure with free seats.
+func run(db *gorm.DB, query string) ([]Match, error) {
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("limit", "100")
+	params.Set("sortBy", "newest")
+	params.Del("facets")
+	byDates := params.Get("dateFrom") != "" || params.Get("dateTo") != "" ||
+		params.Get("flexDays") != "" || params.Get("travellers") != ""
+
+	var matches []Match
+	for page := 1; page <= maxPages; page++ {
+		params.Set("page", strconv.Itoa(page))
+		res, serr := search.RunSearch(db, params)
+		if serr != nil {
+			return nil, serr
+		}
+		for _, t := range res.Tours {
+			m := Match{TourID: t.ID, Title: t.Title, Price: t.Price}
+			for _, d := range t.Departures {
+				m.Departures = append(m.Departures, Departure{ID: d.ID, TourID: t.ID, DateFrom: d.DateFrom, DateTo: d.DateTo})
+			}
+			matches = append(matches, m)
+		}
+		if page >= res.TotalPages {
+			break
+		}
+	}
+	if byDates || len(matches) == 0 {
+		return matches, nil
+	}
+
+	ids := make([]uint, len(matches))
+	for i, m := range matches {
+		ids[i] = m.TourID
+	}
+	var departures []Departure
+	err = db.Raw(`
+		SELECT td.id, td.tour_id, td.date_from, td.date_to
+		FROM tour_dates td
+		LEFT JOIN tour_seats ts ON ts.tour_date_id = td.id
+		WHERE td.tour_id IN ? AND NOT td.is_retired
+			AND td.date_from > NOW() AND COALESCE(ts.available_seats, 0) > 0
+		ORDER BY td.date_from
+	`, ids).Scan(&departures).Error
+	if err != nil {
+		return nil, err
+	}
+	byTour := map[uint][]Departure{}
+	for _, d := range departures {
+		byTour[d.TourID] = append(byTour[d.TourID], d)
+	}
+	for i := range matches {
+		matches[i].Departures = byTour[matches[i].TourID]
+	}
+	return matches, nil
+}
+
+// record remembers the matches as reported, at their current prices.
+func record(db *gorm.DB, searchID uint, matches []Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	var tourIDs, depIDs pq.Int64Array
+	var prices pq.Float64Array
+	for _, m := range matches {
+		tourIDs = append(tourIDs, int64(m.TourID))
+		prices = append(prices, m.Price)
+		for _, d := range m.Departures {
+			depIDs = append(depIDs, int64(d.ID))
+		}
+	}
+
+	tx := db.Begin()
+	if err := tx.Exec(`
+		INSERT INTO saved_search_tours (saved_search_id, tour_id, price)
+		SELECT ?, t.tour_id, t.price FROM unnest(?::int[], ?::numeric[]) AS t(tour_id, price)
+		ON CONFLICT (saved_search_id, tour_id) DO UPDATE SET price = EXCLUDED.price
+	`, searchID, tourIDs, prices).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(depIDs) > 0 {
+		if err := tx.Exec(`
+			INSERT INTO saved_search_departures (saved_search_id, tour_date_id)
+			SELECT ?, unnest(?::int[])
+			ON CONFLICT DO NOTHING
+		`, searchID, depIDs).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+func alertEmail(s dueSearch, ch Changes) email.SavedSearchAlert {
+	a := email.SavedSearchAlert{
+		UserName:       s.UserName,
+		SearchName:     s.Name,
+		SearchURL:      frontendURL("/Tours?" + s.Query),
+		UnsubscribeURL: UnsubscribeURL(s.UnsubscribeToken),
+	}
+	for _, m := range ch.NewTours {
+		a.NewTours = append(a.NewTours, alertTour(m, 0))
+	}
+	for _, p := range ch.PriceDrops {
+		a.PriceDrops = append(a.PriceDrops, alertTour(p.Match, p.OldPrice))
+	}
+	for _, m := range ch.NewDepartures {
+		a.NewDepartures = append(a.NewDepartures, alertTour(m, 0))
+	}
+	return a
+}
+
+func alertTour(m Match, oldPrice float64) email.SavedSearchTour {
+	t := email.SavedSearchTour{
+		Title:    m.Title,
+		URL:      frontendURL(fmt.Sprintf("/TourDetails/%d", m.TourID)),
+		Price:    m.Price,
+		OldPrice: oldPrice,
+	}
+	for _, d := range m.Departures {
+		t.Departures = append(t.Departures, email.SavedSearchDeparture{DateFrom: d.DateFrom, DateTo: d.DateTo})
+	}
+	return t
+}
+
+// UnsubscribeURL is the one-click link in every alert. The SPA's
+// /unsubscribe/:token page calls /saved-searches/unsubscribe/:token.
+func UnsubscribeURL(token string) string {
+	return frontendURL("/unsubscribe/" + token)
+}
+
+func frontendURL(path string) string {
+	base := os.Getenv("FRONTEND_URL")
+	if base == "" {
+		base = "https://openworld.local"
+	}
+	return base + path
+}
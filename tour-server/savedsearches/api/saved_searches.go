@@ -0,0 +1,243 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"tour-server/savedsearches"
+	search "tour-server/search/api"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type SavedSearchRequest struct {
+	Name     *string `json:"name"`
+	Query    *string `json:"query"` // /search query string, create only
+	IsPaused *bool   `json:"is_paused"`
+}
+
+// validate checks the fields that are present and returns an error
+// message, or "" if valid.
+func (r *SavedSearchRequest) validate() string {
+	if r.Name != nil {
+		n := strings.TrimSpace(*r.Name)
+		if n == "" || len([]rune(n)) > 100 {
+			return "name is required (max 100 characters)"
+		}
+		r.Name = &n
+	}
+	return ""
+}
+
+// GET /saved-searches
+func GetSavedSearches(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, ok := c.Get("user_id").(uint)
+		if !ok || userID == 0 {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Необхідна авторизація"})
+		}
+
+		searches := []savedsearches.SavedSearch{}
+		err := db.Raw(`
+			SELECT * FROM saved_searches WHERE user_id = ? ORDER BY created_at DESC
+		`, userID).Scan(&searches).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch saved searches"})
+		}
+
+		return c.JSON(http.StatusOK, searches)
+	}
+}
+
+// POST /saved-searches
+// {"name": "Море в липні", "query": "q=море&dateFrom=2026-07-01&travellers=2"}
+func CreateSavedSearch(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, ok := c.Get("user_id").(uint)
+		if !ok || userID == 0 {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Необхідна авторизація"})
+		}
+
+		var req SavedSearchRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		}
+		if req.Name == nil || req.Query == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "name and query are required"})
+		}
+		if msg := req.validate(); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		query, err := savedsearches.NormalizeQuery(*req.Query)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+
+		// Reject parameters /search itself would reject
+		params, _ := url.ParseQuery(query)
+		params.Set("limit", "1")
+		result, serr := search.RunSearch(db, params)
+		if serr != nil {
+			return c.JSON(serr.Status, map[string]string{"error": serr.Message})
+		}
+
+		var existing uint
+		db.Raw("SELECT id FROM saved_searches WHERE user_id = ? AND query = ?", userID, query).Scan(&existing)
+		if existing != 0 {
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": "This search is already saved",
+				"id":    existing,
+			})
+		}
+		var count int64
+		db.Table("saved_searches").Where("user_id = ?", userID).Count(&count)
+		if count >= savedsearches.MaxPerUser {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "At most 20 saved searches per user"})
+		}
+
+		token, err := generateUnsubscribeToken()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save search"})
+		}
+
+		// uq_saved_searches_user_query catches a concurrent save of the
+		// same search that got past the check above
+		var id uint
+		err = db.Raw(`
+			INSERT INTO saved_searches (user_id, name, query, unsubscribe_token)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (user_id, query) DO NOTHING
+			RETURNING id
+		`, userID, *req.Name, query, token).Scan(&id).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save search"})
+		}
+		if id == 0 {
+			db.Raw("SELECT id FROM saved_searches WHERE user_id = ? AND query = ?", userID, query).Scan(&existing)
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": "This search is already saved",
+				"id":    existing,
+			})
+		}
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message": "Search saved",
+			"id":      id,
+			"query":   query,
+			"matches": result.Total,
+		})
+	}
+}
+
+// PUT /saved-searches/:id
+// Rename, pause ({"is_paused": true}) or resume alerts.
+func UpdateSavedSearch(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, ok := c.Get("user_id").(uint)
+		if !ok || userID == 0 {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Необхідна авторизація"})
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid saved search ID"})
+		}
+
+		var req SavedSearchRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		}
+		if req.Query != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "query can't be changed; save a new search"})
+		}
+		if msg := req.validate(); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+
+		updates := map[string]interface{}{}
+		if req.Name != nil {
+			updates["name"] = *req.Name
+		}
+		if req.IsPaused != nil {
+			updates["is_paused"] = *req.IsPaused
+		}
+		if len(updates) == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Nothing to update"})
+		}
+		updates["updated_at"] = gorm.Expr("NOW()")
+
+		result := db.Table("saved_searches").Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update saved search"})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Saved search not found"})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"message": "Saved search updated"})
+	}
+}
+
+// DELETE /saved-searches/:id
+func DeleteSavedSearch(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, ok := c.Get("user_id").(uint)
+		if !ok || userID == 0 {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Необхідна авторизація"})
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid saved search ID"})
+		}
+
+		result := db.Exec("DELETE FROM saved_searches WHERE id = ? AND user_id = ?", id, userID)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete saved search"})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Saved search not found"})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"message": "Saved search deleted"})
+	}
+}
+
+// GET|POST /saved-searches/unsubscribe/:token
+// The one-click link in alert emails: pauses that search's alerts. No
+// login needed; the token is the credential.
+func UnsubscribeSavedSearch(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Param("token")
+		if len(token) != 64 {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+
+		var name string
+		err := db.Raw(`
+			UPDATE saved_searches SET is_paused = TRUE, updated_at = NOW()
+			WHERE unsubscribe_token = ?
+			RETURNING name
+		`, token).Scan(&name).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to unsubscribe"})
+		}
+		if name == "" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Посилання недійсне"})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Сповіщення за пошуком «" + name + "» вимкнено",
+		})
+	}
+}
+
+func generateUnsubscribeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
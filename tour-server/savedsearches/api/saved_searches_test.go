@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newContext(method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func TestCreateSavedSearch_Unauthorized(t *testing.T) {
+	c, rec := newContext(http.MethodPost, "/saved-searches", `{"name": "Море", "query": "q=море"}`)
+
+	CreateSavedSearch(nil)(c)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestCreateSavedSearch_Invalid(t *testing.T) {
+	bodies := []string{
+		`{"query": "q=море"}`,
+		`{"name": "  ", "query": "q=море"}`,
+		`{"name": "Море", "query": "page=2"}`,
+	}
+	for _, body := range bodies {
+		c, rec := newContext(http.MethodPost, "/saved-searches", body)
+		c.Set("user_id", uint(1))
+
+		CreateSavedSearch(nil)(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestUpdateSavedSearch_QueryIsReadOnly(t *testing.T) {
+	c, rec := newContext(http.MethodPut, "/saved-searches/1", `{"query": "q=гори"}`)
+	c.Set("user_id", uint(1))
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	UpdateSavedSearch(nil)(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestUnsubscribeSavedSearch_InvalidToken(t *testing.T) {
+	c, rec := newContext(http.MethodGet, "/saved-searches/unsubscribe/abc", "")
+	c.SetParamNames("token")
+	c.SetParamValues("abc")
+
+	UnsubscribeSavedSearch(nil)(c)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
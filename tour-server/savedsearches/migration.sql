@@ -0,0 +1,37 @@
+-- Migration: saved searches with new-match email alerts
+-- A saved search stores /search parameters; the daily alert job re-runs
+-- it and emails tours and departures it hasn't reported yet, and tours
+-- whose price dropped. The *_seen tables remember what was reported.
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id                 SERIAL PRIMARY KEY,
+    user_id            INTEGER NOT NULL REFERENCES tour_users(id) ON DELETE CASCADE,
+    name               VARCHAR(100) NOT NULL,
+    query              TEXT NOT NULL,                -- url-encoded /search parameters
+    is_paused          BOOLEAN NOT NULL DEFAULT FALSE,
+    unsubscribe_token  CHAR(64) NOT NULL UNIQUE,
+    last_checked_at    TIMESTAMP,                    -- NULL: not checked yet
+    last_alert_at      TIMESTAMP,
+    created_at         TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches(user_id);
+-- One saved search per query and user; query is normalized before saving
+CREATE UNIQUE INDEX IF NOT EXISTS uq_saved_searches_user_query ON saved_searches(user_id, query);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_due
+    ON saved_searches(last_checked_at) WHERE NOT is_paused;
+
+-- Tours already reported, with the price they were last seen at
+CREATE TABLE IF NOT EXISTS saved_search_tours (
+    saved_search_id  INTEGER NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    tour_id          INTEGER NOT NULL REFERENCES tours(id) ON DELETE CASCADE,
+    price            NUMERIC NOT NULL,
+    PRIMARY KEY (saved_search_id, tour_id)
+);
+
+CREATE TABLE IF NOT EXISTS saved_search_departures (
+    saved_search_id  INTEGER NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    tour_date_id     INTEGER NOT NULL REFERENCES tour_dates(id) ON DELETE CASCADE,
+    PRIMARY KEY (saved_search_id, tour_date_id)
+);
@@ -0,0 +1,63 @@
+// Package savedsearches stores users' /search queries and emails them
+// what's new: tours and departures not reported before and price drops.
+package savedsearches
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// MaxPerUser caps the saved searches of one user.
+const MaxPerUser = 20
+
+type SavedSearch struct {
+	ID               uint       `json:"id" gorm:"column:id"`
+	UserID           uint       `json:"-" gorm:"column:user_id"`
+	Name             string     `json:"name" gorm:"column:name"`
+	Query            string     `json:"query" gorm:"column:query"`
+	IsPaused         bool       `json:"is_paused" gorm:"column:is_paused"`
+	UnsubscribeToken string     `json:"-" gorm:"column:unsubscribe_token"`
+	LastCheckedAt    *time.Time `json:"last_checked_at" gorm:"column:last_checked_at"`
+	LastAlertAt      *time.Time `json:"last_alert_at" gorm:"column:last_alert_at"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"column:created_at"`
+}
+
+// filterParams are the /search parameters worth saving; paging, sorting
+// and facets are left out.
+var filterParams = map[string]bool{
+	"q": true, "title": true,
+	"minPrice": true, "maxPrice": true, "minDuration": true, "maxDuration": true,
+	"ratings": true, "region": true, "country": true, "city": true,
+	"from_location_id": true, "categories": true, "tags": true,
+	"bbox": true, "lat": true, "lng": true, "radiusKm": true, "nearCity": true,
+	"dateFrom": true, "dateTo": true, "flexDays": true, "travellers": true,
+}
+
+// NormalizeQuery keeps the non-empty filter parameters of a /search query
+// string, in a stable order. A query without filters is an error: it
+// would match the whole catalog.
+func NormalizeQuery(raw string) (string, error) {
+	if len(raw) > 2000 {
+		return "", errors.New("query is too long")
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return "", errors.New("query must be a /search query string")
+	}
+	out := url.Values{}
+	for k, vs := range values {
+		if !filterParams[k] {
+			continue
+		}
+		for _, v := range vs {
+			if v != "" {
+				out.Set(k, v)
+			}
+		}
+	}
+	if len(out) == 0 {
+		return "", errors.New("query has no search filters")
+	}
+	return out.Encode(), nil
+}
@@ -0,0 +1,49 @@
+package savedsearches
+
+import "testing"
+
+func TestNormalizeQuery(t *testing.T) {
+	got, err := NormalizeQuery("travellers=2&q=%D0%BC%D0%BE%D1%80%D0%B5&page=3&sortBy=price_asc&minPrice=&facets=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "q=%D0%BC%D0%BE%D1%80%D0%B5&travellers=2"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{"", "page=2&limit=12", "minPrice=&q=", "q=%zz"} {
+		if _, err := NormalizeQuery(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	seenTours := map[uint]float64{1: 10000, 2: 20000}
+	seenDepartures := map[uint]bool{11: true, 21: true}
+	matches := []Match{
+		{TourID: 1, Price: 9000, Departures: []Departure{{ID: 11}}},
+		{TourID: 2, Price: 25000, Departures: []Departure{{ID: 21}, {ID: 22}}},
+		{TourID: 3, Price: 5000, Departures: []Departure{{ID: 31}}},
+	}
+
+	ch := diff(seenTours, seenDepartures, matches)
+	if len(ch.NewTours) != 1 || ch.NewTours[0].TourID != 3 {
+		t.Errorf("expected tour 3 as new, got %+v", ch.NewTours)
+	}
+	if len(ch.PriceDrops) != 1 || ch.PriceDrops[0].TourID != 1 || ch.PriceDrops[0].OldPrice != 10000 {
+		t.Errorf("expected a price drop on tour 1, got %+v", ch.PriceDrops)
+	}
+	if len(ch.NewDepartures) != 1 || ch.NewDepartures[0].TourID != 2 ||
+		len(ch.NewDepartures[0].Departures) != 1 || ch.NewDepartures[0].Departures[0].ID != 22 {
+		t.Errorf("expected only departure 22 of tour 2 as new, got %+v", ch.NewDepartures)
+	}
+
+	// Nothing changed since the last run
+	seenTours[3] = 5000
+	seenTours[1] = 9000
+	seenDepartures[22], seenDepartures[31] = true, true
+	if ch := diff(seenTours, seenDepartures, matches); !ch.Empty() {
+		t.Errorf("expected no changes, got %+v", ch)
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Limits of the dateFrom/dateTo/flexDays/travellers search.
+const (
+	maxFlexDays   = 14
+	maxTravellers = 50
+	maxDateWindow = 2 * 366 * 24 * time.Hour
+)
+
+// availability selects departures starting in [From, To) with at least
+// Travellers seats left.
+type availability struct {
+	From       time.Time
+	To         time.Time // exclusive
+	Travellers int
+}
+
+// SearchDeparture is a departure of a result that fits the dates and
+// party size. TotalPrice is for all travellers.
+type SearchDeparture struct {
+	ID             uint      `json:"id" gorm:"column:id"`
+	TourID         uint      `json:"-" gorm:"column:tour_id"`
+	DateFrom       time.Time `json:"dateFrom" gorm:"column:date_from"`
+	DateTo         time.Time `json:"dateTo" gorm:"column:date_to"`
+	FromLocation   string    `json:"fromLocation" gorm:"column:from_location"`
+	ToLocation     string    `json:"toLocation" gorm:"column:to_location"`
+	AvailableSeats uint      `json:"availableSeats" gorm:"column:available_seats"`
+	IsGuaranteed   bool      `json:"isGuaranteed" gorm:"column:is_guaranteed"`
+	Price          float64   `json:"price" gorm:"column:price"`
+	TotalPrice     float64   `json:"totalPrice" gorm:"column:total_price"`
+}
+
+// parseAvailability reads dateFrom/dateTo (YYYY-MM-DD), flexDays and
+// travellers. It returns nil if none is set, or an error message. The
+// window widens by flexDays on both sides and never starts in the past;
+// an open end means a year after the start.
+func parseAvailability(dateFrom, dateTo, flexDays, travellers string, today time.Time) (*availability, string) {
+	if dateFrom == "" && dateTo == "" && flexDays == "" && travellers == "" {
+		return nil, ""
+	}
+
+	a := &availability{From: today, Travellers: 1}
+	var err error
+	if dateFrom != "" {
+		if a.From, err = time.Parse("2006-01-02", dateFrom); err != nil {
+			return nil, "dateFrom must be YYYY-MM-DD"
+		}
+	}
+	a.To = a.From.AddDate(1, 0, 0)
+	if dateTo != "" {
+		if a.To, err = time.Parse("2006-01-02", dateTo); err != nil {
+			return nil, "dateTo must be YYYY-MM-DD"
+		}
+		a.To = a.To.AddDate(0, 0, 1)
+	}
+	if !a.To.After(a.From) || a.To.Sub(a.From) > maxDateWindow {
+		return nil, "dateTo must not be before dateFrom and at most two years later"
+	}
+
+	if flexDays != "" {
+		flex, ok := parseBounded(flexDays, 0, maxFlexDays)
+		if !ok {
+			return nil, "flexDays must be between 0 and 14"
+		}
+		a.From = a.From.AddDate(0, 0, -flex)
+		a.To = a.To.AddDate(0, 0, flex)
+	}
+	if travellers != "" {
+		n, ok := parseBounded(travellers, 1, maxTravellers)
+		if !ok {
+			return nil, "travellers must be between 1 and 50"
+		}
+		a.Travellers = n
+	}
+
+	// Departures that already left can't be booked.
+	if a.From.Before(today) {
+		a.From = today
+	}
+	if !a.To.After(a.From) {
+		return nil, "The dates are in the past"
+	}
+	return a, ""
+}
+
+// where is the tour_dates (td) / tour_seats (ts) condition.
+func (a *availability) where() (string, []interface{}) {
+	return `NOT td.is_retired AND td.date_from >= ? AND td.date_from < ?
+		AND COALESCE(ts.available_seats, 0) >= ?`, []interface{}{a.From, a.To, a.Travellers}
+}
+
+// attachDepartures fills in each tour's matching departures and the
+// party's price on the cheapest one.
+func (a *availability) attachDepartures(db *gorm.DB, tours []SearchTourItem) error {
+	if len(tours) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(tours))
+	for i, t := range tours {
+		ids[i] = t.ID
+	}
+
+	cond, args := a.where()
+	var departures []SearchDeparture
+	err := db.Raw(`
+		SELECT td.id, td.tour_id, td.date_from, td.date_to,
+			COALESCE(fl.name, '') AS from_location, COALESCE(tl.name, '') AS to_location,
+			COALESCE(ts.available_seats, 0) AS available_seats,
+			COALESCE(td.is_guaranteed, FALSE) AS is_guaranteed,
+			t.price, t.price * ? AS total_price
+		FROM tour_dates td
+		JOIN tours t ON td.tour_id = t.id
+		LEFT JOIN tour_seats ts ON ts.tour_date_id = td.id
+		LEFT JOIN locations fl ON td.from_location_id = fl.id
+		LEFT JOIN locations tl ON td.to_location_id = tl.id
+		WHERE td.tour_id IN ? AND `+cond+`
+		ORDER BY td.date_from
+	`, append([]interface{}{a.Travellers, ids}, args...)...).Scan(&departures).Error
+	if err != nil {
+		return err
+	}
+
+	byTour := map[uint][]SearchDeparture{}
+	for _, d := range departures {
+		byTour[d.TourID] = append(byTour[d.TourID], d)
+	}
+	for i := range tours {
+		tours[i].Departures = byTour[tours[i].ID]
+		for _, d := range tours[i].Departures {
+			if tours[i].EffectivePrice == nil || d.TotalPrice < *tours[i].EffectivePrice {
+				p := d.TotalPrice
+				tours[i].EffectivePrice = &p
+			}
+		}
+	}
+	return nil
+}
+
+// parseBounded parses an integer in [min, max].
+func parseBounded(s string, min, max int) (int, bool) {
+	v, err := strconv.Atoi(s)
+	return v, err == nil && v >= min && v <= max
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAvailability(t *testing.T) {
+	today := time.Date(2026, 6, 20, 0, 0, 0, 0, time.UTC)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+
+	if a, msg := parseAvailability("", "", "", "", today); a != nil || msg != "" {
+		t.Fatalf("expected no filter, got %+v %q", a, msg)
+	}
+
+	a, msg := parseAvailability("2026-07-01", "2026-07-15", "", "3", today)
+	if msg != "" {
+		t.Fatal(msg)
+	}
+	if !a.From.Equal(day(7, 1)) || !a.To.Equal(day(7, 16)) || a.Travellers != 3 {
+		t.Errorf("unexpected window %+v", a)
+	}
+
+	a, _ = parseAvailability("2026-07-01", "2026-07-15", "2", "", today)
+	if !a.From.Equal(day(6, 29)) || !a.To.Equal(day(7, 18)) || a.Travellers != 1 {
+		t.Errorf("flexDays should widen both ends, got %+v", a)
+	}
+
+	// Never before today; open end is a year after the start
+	a, _ = parseAvailability("2026-06-01", "", "", "", today)
+	if !a.From.Equal(today) || !a.To.Equal(time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected open window %+v", a)
+	}
+	a, _ = parseAvailability("", "", "", "2", today)
+	if !a.From.Equal(today) || a.Travellers != 2 {
+		t.Errorf("travellers alone should search from today, got %+v", a)
+	}
+
+	bad := [][4]string{
+		{"01.07.2026", "", "", ""},
+		{"", "2026-13-01", "", ""},
+		{"2026-07-15", "2026-07-01", "", ""},
+		{"2026-07-01", "2029-07-01", "", ""},
+		{"2026-07-01", "", "15", ""},
+		{"2026-07-01", "", "-1", ""},
+		{"", "", "", "0"},
+		{"", "", "", "51"},
+		{"2026-01-01", "2026-02-01", "", ""},
+	}
+	for _, in := range bad {
+		if a, msg := parseAvailability(in[0], in[1], in[2], in[3], today); a != nil || msg == "" {
+			t.Errorf("expected an error for %v, got %+v", in, a)
+		}
+	}
+}
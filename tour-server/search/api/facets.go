@@ -0,0 +1,241 @@
+package api
+
+import (
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Facets a filter can narrow. Filters tagged "" (text, geo, departure
+// city, tags) apply to every facet count.
+const (
+	facetPrice       = "price"
+	facetDuration    = "duration"
+	facetRating      = "rating"
+	facetDestination = "destination" // region, country and city
+	facetCategory    = "category"
+	facetDate        = "date" // dateFrom/dateTo/flexDays/travellers
+)
+
+type searchFilter struct {
+	facet string
+	cond  string
+	args  []interface{}
+}
+
+// priceEdges are the lower bounds of the price histogram buckets, UAH.
+// The last bucket is open-ended.
+var priceEdges = []float64{0, 5000, 10000, 20000, 30000, 50000, 75000, 100000}
+
+// durationRanges map to minDuration/maxDuration; 999 is the search's own
+// open upper bound.
+var durationRanges = [][2]int{{1, 3}, {4, 7}, {8, 14}, {15, 999}}
+
+type PriceBucket struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"` // exclusive; null for the last bucket
+	Count int      `json:"count"`
+}
+
+type DurationRange struct {
+	Min   int  `json:"min"`
+	Max   *int `json:"max"` // inclusive; null for the last range
+	Count int  `json:"count"`
+}
+
+type RatingBand struct {
+	Rating int `json:"rating"` // as in ratings=: the rating rounded to whole stars
+	Count  int `json:"count"`
+}
+
+type FacetTerm struct {
+	ID       uint   `json:"id" gorm:"column:id"`
+	Slug     string `json:"slug,omitempty" gorm:"column:slug"`
+	Name     string `json:"name" gorm:"column:name"`
+	RegionID *uint  `json:"regionId,omitempty" gorm:"column:region_id"`
+	Count    int    `json:"count" gorm:"column:count"`
+}
+
+type MonthCount struct {
+	Month string `json:"month" gorm:"column:month"` // YYYY-MM
+	Count int    `json:"count" gorm:"column:count"`
+}
+
+// SearchFacets counts the tours each filter option would return, given
+// the other active filters. Price, duration, rating, regions and
+// categories list every option; countries and months only non-empty ones.
+type SearchFacets struct {
+	Price      []PriceBucket   `json:"price"`
+	Duration   []DurationRange `json:"duration"`
+	Ratings    []RatingBand    `json:"ratings"`
+	Regions    []FacetTerm     `json:"regions"`
+	Countries  []FacetTerm     `json:"countries"`
+	Categories []FacetTerm     `json:"categories"`
+	Months     []MonthCount    `json:"months"`
+}
+
+type bucketCount struct {
+	Bucket int `gorm:"column:bucket"`
+	Count  int `gorm:"column:count"`
+}
+
+// loadFacets runs one query per facet; scope(facet) returns the active
+// tours matching every filter except that facet's own.
+func loadFacets(db *gorm.DB, scope func(except string) *gorm.DB) (*SearchFacets, error) {
+	f := &SearchFacets{}
+
+	// width_bucket: i means priceEdges[i-1] <= price < priceEdges[i]
+	var prices []bucketCount
+	if err := db.Raw(`
+		SELECT width_bucket(s.price::float8, ?::float8[]) AS bucket, COUNT(*) AS count
+		FROM (?) s
+		GROUP BY 1
+	`, pq.Float64Array(priceEdges), scope(facetPrice).Select("tours.id, tours.price")).Scan(&prices).Error; err != nil {
+		return nil, err
+	}
+	f.Price = priceBuckets(prices)
+
+	// Same condition as the minDuration/maxDuration filter
+	var durations []bucketCount
+	lows, highs := make(pq.Int64Array, len(durationRanges)), make(pq.Int64Array, len(durationRanges))
+	for i, r := range durationRanges {
+		lows[i], highs[i] = int64(r[0]), int64(r[1])
+	}
+	if err := db.Raw(`
+		SELECT r.idx AS bucket, COUNT(DISTINCT s.id) AS count
+		FROM (?) s
+		JOIN tour_dates td ON td.tour_id = s.id
+		JOIN unnest(?::int[], ?::int[]) WITH ORDINALITY AS r(lo, hi, idx)
+			ON EXTRACT(DAY FROM (td.date_to - td.date_from)) BETWEEN r.lo AND r.hi
+		GROUP BY r.idx
+	`, scope(facetDuration).Select("tours.id"), lows, highs).Scan(&durations).Error; err != nil {
+		return nil, err
+	}
+	f.Duration = durationCounts(durations)
+
+	var ratings []bucketCount
+	if err := db.Raw(`
+		SELECT `+ratingStarsSQL("s.rating")+` AS bucket, COUNT(*) AS count
+		FROM (?) s
+		WHERE s.rating >= 0.5
+		GROUP BY 1
+	`, scope(facetRating).Select("tours.id, tours.rating")).Scan(&ratings).Error; err != nil {
+		return nil, err
+	}
+	f.Ratings = ratingBands(ratings)
+
+	f.Regions = []FacetTerm{}
+	if err := db.Raw(`
+		SELECT r.id, r.name, COALESCE(x.count, 0) AS count
+		FROM regions r
+		LEFT JOIN (
+			SELECT co.region_id, COUNT(DISTINCT s.id) AS count
+			FROM (?) s
+			JOIN tour_dates td ON td.tour_id = s.id AND NOT td.is_retired
+			JOIN cities ci ON td.to_location_id = ci.id
+			JOIN countries co ON ci.country_id = co.id
+			GROUP BY co.region_id
+		) x ON x.region_id = r.id
+		ORDER BY r.sort_order, r.id
+	`, scope(facetDestination).Select("tours.id")).Scan(&f.Regions).Error; err != nil {
+		return nil, err
+	}
+
+	f.Countries = []FacetTerm{}
+	if err := db.Raw(`
+		SELECT co.id, co.name, co.region_id, COUNT(DISTINCT s.id) AS count
+		FROM (?) s
+		JOIN tour_dates td ON td.tour_id = s.id AND NOT td.is_retired
+		JOIN cities ci ON td.to_location_id = ci.id
+		JOIN countries co ON ci.country_id = co.id
+		GROUP BY co.id, co.name, co.region_id
+		ORDER BY count DESC, co.name
+	`, scope(facetDestination).Select("tours.id")).Scan(&f.Countries).Error; err != nil {
+		return nil, err
+	}
+
+	f.Categories = []FacetTerm{}
+	if err := db.Raw(`
+		SELECT c.id, c.slug, c.name, COALESCE(x.count, 0) AS count
+		FROM categories c
+		LEFT JOIN (
+			SELECT tc.category_id, COUNT(*) AS count
+			FROM (?) s
+			JOIN tour_categories tc ON tc.tour_id = s.id
+			GROUP BY tc.category_id
+		) x ON x.category_id = c.id
+		ORDER BY c.sort_order, c.name
+	`, scope(facetCategory).Select("tours.id")).Scan(&f.Categories).Error; err != nil {
+		return nil, err
+	}
+
+	f.Months = []MonthCount{}
+	if err := db.Raw(`
+		SELECT to_char(date_trunc('month', td.date_from), 'YYYY-MM') AS month,
+			COUNT(DISTINCT s.id) AS count
+		FROM (?) s
+		JOIN tour_dates td ON td.tour_id = s.id AND NOT td.is_retired AND td.date_from > NOW()
+		GROUP BY 1
+		ORDER BY 1
+		LIMIT 24
+	`, scope(facetDate).Select("tours.id")).Scan(&f.Months).Error; err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// priceBuckets lays the width_bucket counts over priceEdges, zeros included.
+func priceBuckets(rows []bucketCount) []PriceBucket {
+	out := make([]PriceBucket, len(priceEdges))
+	for i, lo := range priceEdges {
+		out[i].Min = lo
+		if i+1 < len(priceEdges) {
+			hi := priceEdges[i+1]
+			out[i].Max = &hi
+		}
+	}
+	for _, r := range rows {
+		if r.Bucket >= 1 && r.Bucket <= len(out) {
+			out[r.Bucket-1].Count += r.Count
+		}
+	}
+	return out
+}
+
+// durationCounts lays the 1-based range counts over durationRanges.
+func durationCounts(rows []bucketCount) []DurationRange {
+	out := make([]DurationRange, len(durationRanges))
+	for i, r := range durationRanges {
+		out[i].Min = r[0]
+		if i+1 < len(durationRanges) {
+			hi := r[1]
+			out[i].Max = &hi
+		}
+	}
+	for _, r := range rows {
+		if r.Bucket >= 1 && r.Bucket <= len(out) {
+			out[r.Bucket-1].Count = r.Count
+		}
+	}
+	return out
+}
+
+// ratingStarsSQL rounds a rating column to whole stars, half up (a plain
+// ROUND on double precision rounds half to even).
+func ratingStarsSQL(col string) string {
+	return "ROUND(" + col + "::numeric)::int"
+}
+
+// ratingBands returns bands 5 down to 1, zeros included.
+func ratingBands(rows []bucketCount) []RatingBand {
+	out := make([]RatingBand, 5)
+	for i := range out {
+		out[i].Rating = 5 - i
+	}
+	for _, r := range rows {
+		if r.Bucket >= 1 && r.Bucket <= 5 {
+			out[5-r.Bucket].Count = r.Count
+		}
+	}
+	return out
+}
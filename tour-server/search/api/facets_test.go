@@ -0,0 +1,43 @@
+package api
+
+import "testing"
+
+func TestPriceBuckets(t *testing.T) {
+	got := priceBuckets([]bucketCount{{Bucket: 1, Count: 3}, {Bucket: 3, Count: 2}, {Bucket: len(priceEdges), Count: 1}, {Bucket: 0, Count: 9}})
+	if len(got) != len(priceEdges) {
+		t.Fatalf("expected %d buckets, got %d", len(priceEdges), len(got))
+	}
+	if got[0].Min != 0 || got[0].Max == nil || *got[0].Max != 5000 || got[0].Count != 3 {
+		t.Errorf("unexpected first bucket %+v", got[0])
+	}
+	if got[1].Count != 0 || got[2].Count != 2 {
+		t.Errorf("unexpected counts %+v %+v", got[1], got[2])
+	}
+	last := got[len(got)-1]
+	if last.Max != nil || last.Count != 1 {
+		t.Errorf("expected an open-ended last bucket with 1 tour, got %+v", last)
+	}
+}
+
+func TestDurationCounts(t *testing.T) {
+	got := durationCounts([]bucketCount{{Bucket: 2, Count: 4}})
+	if len(got) != len(durationRanges) {
+		t.Fatalf("expected %d ranges, got %d", len(durationRanges), len(got))
+	}
+	if got[1].Min != 4 || *got[1].Max != 7 || got[1].Count != 4 {
+		t.Errorf("unexpected range %+v", got[1])
+	}
+	if got[0].Count != 0 || got[len(got)-1].Max != nil {
+		t.Errorf("unexpected ranges %+v", got)
+	}
+}
+
+func TestRatingBands(t *testing.T) {
+	got := ratingBands([]bucketCount{{Bucket: 5, Count: 2}, {Bucket: 4, Count: 7}})
+	want := []RatingBand{{5, 2}, {4, 7}, {3, 0}, {2, 0}, {1, 0}}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("band %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Highlight markers for ts_headline; the frontend styles <mark>.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=12, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// escapeHTMLSQL wraps a SQL text expression so that it comes out
+// HTML-escaped. ts_headline passes markup in its input through untouched,
+// and the highlighted title and snippet are rendered as HTML, so tour text
+// is escaped before the <mark> tags are added.
+func escapeHTMLSQL(expr string) string {
+	return `replace(replace(replace(replace(` + expr + `, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;')`
+}
+
+// ftsQueryJoin makes `fts.q` available to the search query: the
+// websearch-parsed text in both configurations, OR-ed with a prefix query
+// so that partially typed words still match ("карп" → Карпати).
+const ftsQueryJoin = `JOIN (
+	SELECT websearch_to_tsquery('public.ukrainian', ?) ||
+		websearch_to_tsquery('english', ?) ||
+		to_tsquery('simple', ?) AS q
+) fts ON TRUE`
+
+// prefixQuery turns free text into a to_tsquery expression that matches
+// every word as a prefix: "Карпати зим" → "карпати:* & зим:*". Anything
+// that isn't a letter or digit is dropped, so the result is always valid
+// tsquery syntax; "" means there is nothing to search for.
+func prefixQuery(text string) string {
+	words := queryWords(text)
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// queryWords lowercases text and splits it into at most 8 words of
+// letters and digits.
+func queryWords(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 8 {
+		words = words[:8]
+	}
+	return words
+}
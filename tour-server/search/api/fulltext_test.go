@@ -0,0 +1,30 @@
+package api
+
+import "testing"
+
+func TestPrefixQuery(t *testing.T) {
+	cases := map[string]string{
+		"Карпати зим":         "карпати:* & зим:*",
+		"  beach & (resort)!": "beach:* & resort:*",
+		"сім'я":               "сім:* & я:*",
+		"Lviv 2026":           "lviv:* & 2026:*",
+		"':*|!":               "",
+		"":                    "",
+	}
+	for in, want := range cases {
+		if got := prefixQuery(in); got != want {
+			t.Errorf("prefixQuery(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if got := prefixQuery("a b c d e f g h i j"); got != "a:* & b:* & c:* & d:* & e:* & f:* & g:* & h:*" {
+		t.Errorf("expected at most 8 words, got %q", got)
+	}
+}
+
+func TestEscapeHTMLSQL(t *testing.T) {
+	want := `replace(replace(replace(replace(tours.title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;')`
+	if got := escapeHTMLSQL("tours.title"); got != want {
+		t.Errorf("escapeHTMLSQL = %s", got)
+	}
+}
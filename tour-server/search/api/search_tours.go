@@ -2,8 +2,13 @@ package api
 
 import (
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
+	"tour-server/geo"
+	"tour-server/searchlog"
+	"tour-server/tourtaxonomy"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -17,6 +22,23 @@ type SearchTourItem struct {
 	ImageSrc string  `json:"image_src" gorm:"column:image_src"`
 	Duration float64 `json:"duration" gorm:"column:duration"`
 	Location string  `json:"location" gorm:"column:location"`
+	// ReviewCount: votes behind Rating, the computed Bayesian rating (tourratings).
+	ReviewCount int `json:"review_count" gorm:"column:review_count"`
+	// Guaranteed: at least one upcoming departure reached its minimum group size.
+	Guaranteed bool `json:"guaranteed" gorm:"column:guaranteed"`
+	// Next upcoming departure and its seats left; null/0 if none is scheduled.
+	NextDeparture *time.Time `json:"nextDeparture" gorm:"column:next_departure_at"`
+	SeatsLeft     int        `json:"seatsLeft" gorm:"column:seats_left"`
+	// DistanceKm: nearest destination to the search point, radius searches only.
+	DistanceKm *float64 `json:"distanceKm,omitempty" gorm:"column:distance_km"`
+	// Text searches only: 0..1 rank and HTML with matches wrapped in <mark>.
+	Relevance      float64 `json:"relevance,omitempty" gorm:"column:relevance"`
+	TitleHighlight string  `json:"titleHighlight,omitempty" gorm:"column:title_highlight"`
+	Snippet        string  `json:"snippet,omitempty" gorm:"column:snippet"`
+	// Date / travellers searches only: the departures that fit and the
+	// party's price on the cheapest of them.
+	Departures     []SearchDeparture `json:"departures,omitempty" gorm:"-"`
+	EffectivePrice *float64          `json:"effectivePrice,omitempty" gorm:"-"`
 }
 
 type SearchResult struct {
@@ -25,144 +47,340 @@ type SearchResult struct {
 	Page       int              `json:"page"`
 	Limit      int              `json:"limit"`
 	TotalPages int              `json:"totalPages"`
+	DidYouMean string           `json:"didYouMean,omitempty"` // corrected q when nothing matched
+	Facets     *SearchFacets    `json:"facets,omitempty"`     // facets=true only
+	SearchID   string           `json:"searchId,omitempty"`   // send back with /search/click and bookings
 }
 
+// SearchError is a failed search: Status is 400 for bad parameters,
+// 500 otherwise.
+type SearchError struct {
+	Status  int
+	Message string
+}
+
+func (e *SearchError) Error() string { return e.Message }
+
+// SearchTours is GET /search; see RunSearch for the parameters.
 func SearchTours(db *gorm.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		searchTitle := strings.TrimSpace(c.QueryParam("title"))
-		minPriceStr := c.QueryParam("minPrice")
-		maxPriceStr := c.QueryParam("maxPrice")
-		minDurationStr := c.QueryParam("minDuration")
-		maxDurationStr := c.QueryParam("maxDuration")
-		ratingsStr := c.QueryParam("ratings")
-		regionStr := c.QueryParam("region")
-		pageStr := c.QueryParam("page")
-		limitStr := c.QueryParam("limit")
-		sortBy := c.QueryParam("sortBy")
-
-		page, limit := 1, 12
-		if v, err := strconv.Atoi(pageStr); err == nil && v > 0 { page = v }
-		if v, err := strconv.Atoi(limitStr); err == nil && v > 0 && v <= 100 { limit = v }
-
-		base := db.Table("tours").
-			Select(`
-				tours.id, tours.title, tours.price, tours.rating,
-				COALESCE(tci.image_src, '/static/images/no-image.jpg') AS image_src,
-				COALESCE((
-					SELECT EXTRACT(DAY FROM (td.date_to - td.date_from))
-					FROM tour_dates td WHERE td.tour_id = tours.id LIMIT 1
-				), 0) AS duration,
-				COALESCE((
-					SELECT CONCAT(l.name, ', ', l.country)
-					FROM tour_dates td JOIN locations l ON td.to_location_id = l.id
-					WHERE td.tour_id = tours.id LIMIT 1
-				), '') AS location
-			`).
-			Joins("LEFT JOIN tour_card_images tci ON tci.tour_id = tours.id").
-			Where("tours.status_id = (SELECT id FROM statuses WHERE name = 'active')")
-
-		// Filters
-		if searchTitle != "" {
-			base = base.Where("tours.title ILIKE ? OR tours.description ILIKE ?",
-				"%"+searchTitle+"%", "%"+searchTitle+"%")
-		}
-
-		if minPriceStr != "" {
-			if v, err := strconv.ParseFloat(minPriceStr, 64); err == nil { base = base.Where("tours.price >= ?", v) }
+		params := c.QueryParams()
+		result, err := RunSearch(db, params)
+		if err != nil {
+			return c.JSON(err.Status, map[string]string{"error": err.Message})
 		}
-		if maxPriceStr != "" {
-			if v, err := strconv.ParseFloat(maxPriceStr, 64); err == nil { base = base.Where("tours.price <= ?", v) }
+		if result.Page == 1 {
+			result.SearchID = searchlog.Record(logEntry(c, searchlog.SourceSearch, params, result.Total))
 		}
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// unloggedParams are /search parameters that are not filters: the text
+// (logged as the query) and paging/presentation.
+var unloggedParams = map[string]bool{"q": true, "title": true, "page": true, "limit": true, "sortBy": true, "facets": true}
 
-		if minDurationStr != "" || maxDurationStr != "" {
-			minD, maxD := 0, 999
-			if v, err := strconv.Atoi(minDurationStr); err == nil { minD = v }
-			if v, err := strconv.Atoi(maxDurationStr); err == nil { maxD = v }
-			base = base.Where(`EXISTS (
-				SELECT 1 FROM tour_dates td
-				WHERE td.tour_id = tours.id
-				AND EXTRACT(DAY FROM (td.date_to - td.date_from)) BETWEEN ? AND ?
-			)`, minD, maxD)
+// logEntry builds the search log entry for a request; user_id is set by
+// the optional auth middleware for signed-in users.
+func logEntry(c echo.Context, source string, params url.Values, results int) searchlog.Entry {
+	q := strings.TrimSpace(params.Get("q"))
+	if q == "" {
+		q = strings.TrimSpace(params.Get("title"))
+	}
+	filters := map[string]string{}
+	for k, v := range params {
+		if !unloggedParams[k] && len(v) > 0 && strings.TrimSpace(v[0]) != "" {
+			filters[k] = strings.TrimSpace(v[0])
 		}
+	}
+	e := searchlog.Entry{Source: source, Query: q, Filters: filters, ResultCount: results}
+	if id, ok := c.Get("user_id").(uint); ok {
+		e.UserID = &id
+	}
+	return e
+}
+
+// RunSearch runs a catalog search from /search query parameters. Saved
+// searches re-run their stored parameters through it.
+func RunSearch(db *gorm.DB, params url.Values) (*SearchResult, *SearchError) {
+	searchTitle := strings.TrimSpace(params.Get("q"))
+	if searchTitle == "" { searchTitle = strings.TrimSpace(params.Get("title")) }
+	ftsPrefix := prefixQuery(searchTitle)
+	minPriceStr := params.Get("minPrice")
+	maxPriceStr := params.Get("maxPrice")
+	minDurationStr := params.Get("minDuration")
+	maxDurationStr := params.Get("maxDuration")
+	ratingsStr := params.Get("ratings")
+	regionStr := params.Get("region")
+	categories := tourtaxonomy.ParseSlugs(params.Get("categories"))
+	tags := tourtaxonomy.ParseSlugs(params.Get("tags"))
+	pageStr := params.Get("page")
+	limitStr := params.Get("limit")
+	sortBy := params.Get("sortBy")
+
+	page, limit := 1, 12
+	if v, err := strconv.Atoi(pageStr); err == nil && v > 0 { page = v }
+	if v, err := strconv.Atoi(limitStr); err == nil && v > 0 && v <= 100 { limit = v }
+
+	// A category / tag filter in which no slug is valid would otherwise be
+	// dropped and return the whole catalog
+	if len(categories) == 0 && strings.Trim(params.Get("categories"), ", ") != "" {
+		return nil, &SearchError{http.StatusBadRequest, "categories must be comma-separated slugs"}
+	}
+	if len(tags) == 0 && strings.Trim(params.Get("tags"), ", ") != "" {
+		return nil, &SearchError{http.StatusBadRequest, "tags must be comma-separated slugs"}
+	}
 
-		if ratingsStr != "" {
-			parts := strings.Split(ratingsStr, ",")
-			var conds []string
-			var vals []interface{}
-			for _, r := range parts {
-				v, err := strconv.Atoi(strings.TrimSpace(r))
-				if err != nil { continue }
-				if v == 5 {
-					conds = append(conds, "tours.rating >= 5")
-				} else {
-					conds = append(conds, "tours.rating >= ? AND tours.rating < ?")
-					vals = append(vals, float64(v), float64(v+1))
-				}
-			}
-			if len(conds) > 0 {
-				base = base.Where("("+strings.Join(conds, " OR ")+")", vals...)
-			}
+	// Geo: bbox=minLng,minLat,maxLng,maxLat and/or a radius around
+	// lat/lng or a city (nearCity=<id>), matched against destinations
+	var bbox *geo.BBox
+	if v := params.Get("bbox"); v != "" {
+		b, ok := geo.ParseBBox(v)
+		if !ok {
+			return nil, &SearchError{http.StatusBadRequest, "bbox must be minLng,minLat,maxLng,maxLat"}
 		}
+		bbox = &b
+	}
+	// Departure window and party size
+	avail, msg := parseAvailability(params.Get("dateFrom"), params.Get("dateTo"),
+		params.Get("flexDays"), params.Get("travellers"), time.Now().UTC().Truncate(24*time.Hour))
+	if msg != "" {
+		return nil, &SearchError{http.StatusBadRequest, msg}
+	}
 
-		regionMap := map[string][]string{
-			"1": {"Україна", "Ukraine"},
-			"2": {"Франція", "Італія", "Іспанія", "Німеччина", "Польща", "Нідерланди", "Греція",
-				"France", "Italy", "Spain", "Germany", "Poland", "Netherlands", "Greece"},
-			"3": {"Китай", "Японія", "Таїланд", "Тайвань", "Індія", "Сінгапур",
-				"China", "Japan", "Thailand", "Taiwan", "India", "Singapore"},
-			"4": {"США", "Канада", "Мексика", "Бразилія",
-				"USA", "United States", "Canada", "Mexico", "Brazil"},
-			"5": {"ОАЕ", "Туреччина", "Єгипет", "Ізраїль",
-				"UAE", "Turkey", "Egypt", "Israel"},
-			"6": {"Австралія", "Нова Зеландія", "Australia", "New Zealand"},
+	var circle *geo.Circle
+	latStr, lngStr, radiusStr := params.Get("lat"), params.Get("lng"), params.Get("radiusKm")
+	if cityID, err := strconv.Atoi(params.Get("nearCity")); err == nil && cityID > 0 {
+		var city struct {
+			Latitude  *float64 `gorm:"column:latitude"`
+			Longitude *float64 `gorm:"column:longitude"`
 		}
+		db.Raw("SELECT latitude, longitude FROM cities WHERE id = ?", cityID).Scan(&city)
+		if city.Latitude == nil || city.Longitude == nil {
+			return nil, &SearchError{http.StatusBadRequest, "City has no coordinates"}
+		}
+		latStr = strconv.FormatFloat(*city.Latitude, 'f', -1, 64)
+		lngStr = strconv.FormatFloat(*city.Longitude, 'f', -1, 64)
+	}
+	if latStr != "" || lngStr != "" || radiusStr != "" {
+		ci, ok := geo.ParseCircle(latStr, lngStr, radiusStr)
+		if !ok {
+			return nil, &SearchError{http.StatusBadRequest, "Radius search needs lat, lng and radiusKm (up to 5000)"}
+		}
+		circle = &ci
+	}
+
+	// Card data comes precomputed from tour_search_index
+	// (searchindex/migration.sql)
+	selectCols := `
+			tours.id, tours.title, tours.price, tours.rating, tours.review_count,
+			COALESCE(tsi.image_src, '/static/images/no-image.jpg') AS image_src,
+			COALESCE(tsi.duration, 0) AS duration,
+			COALESCE(tsi.destination, '') AS location,
+			COALESCE(tsi.guaranteed, FALSE) AS guaranteed,
+			tsi.next_departure_at, COALESCE(tsi.seats_left, 0) AS seats_left
+		`
+	if circle != nil {
+		selectCols += `, (
+			SELECT MIN(` + circle.DistanceExpr("ci.latitude", "ci.longitude") + `)
+			FROM tour_dates td JOIN cities ci ON td.to_location_id = ci.id
+			WHERE td.tour_id = tours.id AND NOT td.is_retired
+		) AS distance_km`
+	}
+
+	if ftsPrefix != "" {
+		selectCols += `,
+			ts_rank_cd(tours.search_vector, fts.q, 32) AS relevance,
+			ts_headline('public.ukrainian', ` + escapeHTMLSQL("tours.title") + `, fts.q, '` + headlineOptions + `') AS title_highlight,
+			ts_headline('public.ukrainian', ` + escapeHTMLSQL("concat_ws(' ', tours.description, tours.detailed_description)") + `,
+				fts.q, '` + headlineOptions + `') AS snippet`
+	}
+
+	// Filters. Each one is tagged with the facet it narrows, so that
+	// facet counts can be computed under all the other filters.
+	var filters []searchFilter
+
+	if minPriceStr != "" {
+		if v, err := strconv.ParseFloat(minPriceStr, 64); err == nil { filters = append(filters, searchFilter{facetPrice, "tours.price >= ?", []interface{}{v}}) }
+	}
+	if maxPriceStr != "" {
+		if v, err := strconv.ParseFloat(maxPriceStr, 64); err == nil { filters = append(filters, searchFilter{facetPrice, "tours.price <= ?", []interface{}{v}}) }
+	}
+
+	if minDurationStr != "" || maxDurationStr != "" {
+		minD, maxD := 0, 999
+		if v, err := strconv.Atoi(minDurationStr); err == nil { minD = v }
+		if v, err := strconv.Atoi(maxDurationStr); err == nil { maxD = v }
+		filters = append(filters, searchFilter{facetDuration, `EXISTS (
+			SELECT 1 FROM tour_dates td
+			WHERE td.tour_id = tours.id
+			AND EXTRACT(DAY FROM (td.date_to - td.date_from)) BETWEEN ? AND ?
+		)`, []interface{}{minD, maxD}})
+	}
 
-		if regionStr != "" {
-			var regionNames []string
-			for _, id := range strings.Split(regionStr, ",") {
-				if names, ok := regionMap[strings.TrimSpace(id)]; ok {
-					regionNames = append(regionNames, names...)
-				}
-			}
-			if len(regionNames) > 0 {
-				base = base.Where(`EXISTS (
-					SELECT 1 FROM tour_dates td
-					JOIN locations l ON td.to_location_id = l.id
-					WHERE td.tour_id = tours.id AND l.country IN ?
-				)`, regionNames)
-			}
+	// Ratings are matched as rounded stars: the Bayesian rating rarely
+	// reaches 5.0, so ratings=5 means 4.5 and up
+	if ratingsStr != "" {
+		var stars []int
+		for _, r := range strings.Split(ratingsStr, ",") {
+			v, err := strconv.Atoi(strings.TrimSpace(r))
+			if err == nil && v >= 1 && v <= 5 { stars = append(stars, v) }
+		}
+		if len(stars) > 0 {
+			filters = append(filters, searchFilter{facetRating, ratingStarsSQL("tours.rating") + " IN ?", []interface{}{stars}})
 		}
+	}
+
+	// Destination by any level of the geography; each param takes a
+	// comma-separated list of ids and matches any of them
+	regionIDs := parseIDs(regionStr)
+	countryIDs := parseIDs(params.Get("country"))
+	cityIDs := parseIDs(params.Get("city"))
+	if len(regionIDs) > 0 || len(countryIDs) > 0 || len(cityIDs) > 0 {
+		var conds []string
+		var vals []interface{}
+		if len(regionIDs) > 0 { conds = append(conds, "co.region_id IN ?"); vals = append(vals, regionIDs) }
+		if len(countryIDs) > 0 { conds = append(conds, "co.id IN ?"); vals = append(vals, countryIDs) }
+		if len(cityIDs) > 0 { conds = append(conds, "ci.id IN ?"); vals = append(vals, cityIDs) }
+		filters = append(filters, searchFilter{facetDestination, `EXISTS (
+			SELECT 1 FROM tour_dates td
+			JOIN cities ci ON td.to_location_id = ci.id
+			JOIN countries co ON ci.country_id = co.id
+			WHERE td.tour_id = tours.id AND NOT td.is_retired
+			AND (` + strings.Join(conds, " OR ") + `)
+		)`, vals})
+	}
+
+	// Departure city
+	if fromIDs := parseIDs(params.Get("from_location_id")); len(fromIDs) > 0 {
+		filters = append(filters, searchFilter{"", `EXISTS (
+			SELECT 1 FROM tour_dates td
+			WHERE td.tour_id = tours.id AND NOT td.is_retired
+			AND td.from_location_id IN ?
+		)`, []interface{}{fromIDs}})
+	}
+
+	if bbox != nil {
+		cond, args := bbox.Where("ci.latitude", "ci.longitude")
+		filters = append(filters, searchFilter{"", `EXISTS (
+			SELECT 1 FROM tour_dates td JOIN cities ci ON td.to_location_id = ci.id
+			WHERE td.tour_id = tours.id AND NOT td.is_retired AND ` + cond + `
+		)`, args})
+	}
+	if circle != nil {
+		cond, args := circle.Where("ci.latitude", "ci.longitude")
+		filters = append(filters, searchFilter{"", `EXISTS (
+			SELECT 1 FROM tour_dates td JOIN cities ci ON td.to_location_id = ci.id
+			WHERE td.tour_id = tours.id AND NOT td.is_retired AND ` + cond + `
+		)`, args})
+	}
+
+	if avail != nil {
+		cond, args := avail.where()
+		filters = append(filters, searchFilter{facetDate, `EXISTS (
+			SELECT 1 FROM tour_dates td
+			LEFT JOIN tour_seats ts ON ts.tour_date_id = td.id
+			WHERE td.tour_id = tours.id AND ` + cond + `
+		)`, args})
+	}
+
+	// Any of the listed categories / tags, by slug
+	if len(categories) > 0 {
+		filters = append(filters, searchFilter{facetCategory, `EXISTS (
+			SELECT 1 FROM tour_categories tc
+			JOIN categories cat ON tc.category_id = cat.id
+			WHERE tc.tour_id = tours.id AND cat.slug IN ?
+		)`, []interface{}{categories}})
+	}
+	if len(tags) > 0 {
+		filters = append(filters, searchFilter{"", `EXISTS (
+			SELECT 1 FROM tour_tags tt
+			JOIN tags tg ON tt.tag_id = tg.id
+			WHERE tt.tour_id = tours.id AND tg.slug IN ?
+		)`, []interface{}{tags}})
+	}
 
-		// Count
-		var total int64
-		if err := base.Count(&total).Error; err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Count error"})
+	// Active tours matching the text query and every filter except
+	// those of the given facet ("" applies them all)
+	scope := func(except string) *gorm.DB {
+		q := db.Table("tours").Where("tours.status_id = (SELECT id FROM statuses WHERE name = 'active')")
+		// Full text over title, descriptions, itinerary and destinations
+		// (see migration_fulltext.sql)
+		if ftsPrefix != "" {
+			q = q.Joins(ftsQueryJoin, searchTitle, searchTitle, ftsPrefix).
+				Where("tours.search_vector @@ fts.q")
 		}
+		for _, f := range filters {
+			if except == "" || f.facet != except { q = q.Where(f.cond, f.args...) }
+		}
+		return q
+	}
 
-		// Sort
-		switch sortBy {
-		case "price_asc":
-			base = base.Order("tours.price ASC")
-		case "price_desc":
-			base = base.Order("tours.price DESC")
-		case "rating_desc":
-			base = base.Order("CASE WHEN tours.rating IS NULL OR tours.rating = 0 THEN 1 ELSE 0 END ASC, tours.rating DESC, tours.id DESC")
-		case "newest":
-			base = base.Order("tours.id DESC")
-		default:
-			base = base.Order("CASE WHEN tours.rating IS NULL OR tours.rating = 0 THEN 1 ELSE 0 END ASC, tours.rating DESC, tours.price ASC")
+	base := scope("").
+		Select(selectCols).
+		Joins("LEFT JOIN tour_search_index tsi ON tsi.tour_id = tours.id")
+
+	// Count
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, &SearchError{http.StatusInternalServerError, "Count error"}
+	}
+
+	// Sort
+	if sortBy == "distance" && circle == nil { sortBy = "" }
+	if sortBy == "relevance" && ftsPrefix == "" { sortBy = "" }
+	if sortBy == "" && ftsPrefix != "" { sortBy = "relevance" }
+	switch sortBy {
+	case "price_asc":
+		base = base.Order("tours.price ASC")
+	case "price_desc":
+		base = base.Order("tours.price DESC")
+	case "rating_desc":
+		base = base.Order("CASE WHEN tours.rating IS NULL OR tours.rating = 0 THEN 1 ELSE 0 END ASC, tours.rating DESC, tours.review_count DESC, tours.id DESC")
+	case "newest":
+		base = base.Order("tours.id DESC")
+	case "distance":
+		base = base.Order("distance_km ASC, tours.id")
+	case "relevance":
+		base = base.Order("relevance DESC, tours.rating DESC NULLS LAST, tours.id")
+	default:
+		base = base.Order("CASE WHEN tours.rating IS NULL OR tours.rating = 0 THEN 1 ELSE 0 END ASC, tours.rating DESC, tours.review_count DESC, tours.price ASC")
+	}
+
+	offset := (page - 1) * limit
+	var tours []SearchTourItem
+	if err := base.Offset(offset).Limit(limit).Find(&tours).Error; err != nil {
+		return nil, &SearchError{http.StatusInternalServerError, "Search error"}
+	}
+	if avail != nil {
+		if err := avail.attachDepartures(db, tours); err != nil {
+			return nil, &SearchError{http.StatusInternalServerError, "Search error"}
 		}
+	}
 
-		offset := (page - 1) * limit
-		var tours []SearchTourItem
-		if err := base.Offset(offset).Limit(limit).Find(&tours).Error; err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Search error"})
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	result := SearchResult{
+		Tours: tours, Total: int(total),
+		Page: page, Limit: limit, TotalPages: totalPages,
+	}
+	if total == 0 && ftsPrefix != "" { result.DidYouMean = didYouMean(db, searchTitle) }
+	if withFacets, _ := strconv.ParseBool(params.Get("facets")); withFacets {
+		facets, err := loadFacets(db, scope)
+		if err != nil {
+			return nil, &SearchError{http.StatusInternalServerError, "Facets error"}
 		}
+		result.Facets = facets
+	}
+	return &result, nil
+}
 
-		totalPages := int((total + int64(limit) - 1) / int64(limit))
-		return c.JSON(http.StatusOK, SearchResult{
-			Tours: tours, Total: int(total),
-			Page: page, Limit: limit, TotalPages: totalPages,
-		})
+// parseIDs reads a comma-separated list of positive ids, skipping junk.
+func parseIDs(s string) []int {
+	var ids []int
+	for _, part := range strings.Split(s, ",") {
+		if v, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && v > 0 {
+			ids = append(ids, v)
+		}
 	}
+	return ids
 }
\ No newline at end of file
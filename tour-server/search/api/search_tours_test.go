@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRunSearchInvalidSlugs(t *testing.T) {
+	for _, q := range []string{"categories=Bad Slug", "tags=пляж,-x"} {
+		params, _ := url.ParseQuery(q)
+		_, serr := RunSearch(nil, params)
+		if serr == nil || serr.Status != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %+v", q, serr)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"tour-server/searchlog"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Suggestion types
+const (
+	SuggestTour     = "tour"
+	SuggestCity     = "city"
+	SuggestCountry  = "country"
+	SuggestCategory = "category"
+)
+
+type Suggestion struct {
+	Type  string  `json:"type" gorm:"column:type"`
+	ID    uint    `json:"id" gorm:"column:id"`
+	Label string  `json:"label" gorm:"column:label"`
+	Slug  string  `json:"slug,omitempty" gorm:"column:slug"`
+	Score float64 `json:"score" gorm:"column:score"`
+}
+
+type SuggestResult struct {
+	Query       string       `json:"query"`
+	Suggestions []Suggestion `json:"suggestions"`
+	SearchID    string       `json:"searchId,omitempty"`
+}
+
+// Lower than pg_trgm's 0.6 default so that one wrong letter in a short
+// word ("Мальдиви") still matches.
+const suggestThreshold = 0.4
+
+// suggestSQL ranks by word similarity, with a bonus when the name starts
+// with what was typed. Only destinations that active tours go to are
+// suggested. The `<%` operator uses the trigram indexes.
+const suggestSQL = `
+	SELECT * FROM (
+		(SELECT 'tour' AS type, t.id, t.title AS label, '' AS slug,
+			word_similarity(@q, t.title) + CASE WHEN strpos(lower(t.title), lower(@q)) = 1 THEN 0.5 ELSE 0 END AS score
+		FROM tours t
+		WHERE @q <% t.title AND t.status_id = (SELECT id FROM statuses WHERE name = 'active')
+		ORDER BY score DESC LIMIT @limit)
+		UNION ALL
+		(SELECT 'city', ci.id, CONCAT(ci.name, ', ', co.name), '',
+			GREATEST(word_similarity(@q, ci.name), word_similarity(@q, ci.name_en))
+				+ CASE WHEN strpos(lower(ci.name), lower(@q)) = 1 OR strpos(lower(ci.name_en), lower(@q)) = 1 THEN 0.5 ELSE 0 END
+		FROM cities ci
+		JOIN countries co ON co.id = ci.country_id
+		WHERE (@q <% ci.name OR @q <% ci.name_en) AND EXISTS (
+			SELECT 1 FROM tour_dates td JOIN tours t ON t.id = td.tour_id
+			WHERE td.to_location_id = ci.id AND NOT td.is_retired
+				AND t.status_id = (SELECT id FROM statuses WHERE name = 'active'))
+		ORDER BY 5 DESC LIMIT @limit)
+		UNION ALL
+		(SELECT 'country', co.id, co.name, '',
+			GREATEST(word_similarity(@q, co.name), word_similarity(@q, co.name_en))
+				+ CASE WHEN strpos(lower(co.name), lower(@q)) = 1 OR strpos(lower(co.name_en), lower(@q)) = 1 THEN 0.5 ELSE 0 END
+		FROM countries co
+		WHERE (@q <% co.name OR @q <% co.name_en) AND EXISTS (
+			SELECT 1 FROM cities ci
+			JOIN tour_dates td ON td.to_location_id = ci.id AND NOT td.is_retired
+			JOIN tours t ON t.id = td.tour_id
+			WHERE ci.country_id = co.id
+				AND t.status_id = (SELECT id FROM statuses WHERE name = 'active'))
+		ORDER BY 5 DESC LIMIT @limit)
+		UNION ALL
+		(SELECT 'category', c.id, c.name, c.slug,
+			word_similarity(@q, c.name) + CASE WHEN strpos(lower(c.name), lower(@q)) = 1 THEN 0.5 ELSE 0 END
+		FROM categories c
+		WHERE @q <% c.name
+		ORDER BY 5 DESC LIMIT @limit)
+	) s
+	ORDER BY score DESC, label
+	LIMIT @limit`
+
+// GET /search/suggest?q=мальд&limit=8
+// Mixed tour / destination / category suggestions for the search box.
+// Queries shorter than two characters return an empty list.
+func SearchSuggest(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q := strings.TrimSpace(c.QueryParam("q"))
+		limit := 8
+		if v, err := strconv.Atoi(c.QueryParam("limit")); err == nil && v > 0 && v <= 20 {
+			limit = v
+		}
+
+		result := SuggestResult{Query: q, Suggestions: []Suggestion{}}
+		if n := utf8.RuneCountInString(q); n < 2 || n > 100 {
+			return c.JSON(http.StatusOK, result)
+		}
+
+		tx := db.Begin()
+		defer tx.Rollback()
+		tx.Exec("SET LOCAL pg_trgm.word_similarity_threshold = " + strconv.FormatFloat(suggestThreshold, 'f', 2, 64))
+		err := tx.Raw(suggestSQL, map[string]interface{}{"q": q, "limit": limit}).Scan(&result.Suggestions).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Suggest error"})
+		}
+		result.SearchID = searchlog.Record(logEntry(c, searchlog.SourceSuggest, url.Values{"q": {q}}, len(result.Suggestions)))
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// correction is the closest vocabulary word to the query word at Idx
+// (1-based, from WITH ORDINALITY).
+type correction struct {
+	Idx  int    `gorm:"column:idx"`
+	Word string `gorm:"column:word"`
+}
+
+// didYouMean corrects each query word to the most similar known word
+// (search_vocabulary) and returns the corrected query, or "" if nothing
+// changed.
+func didYouMean(db *gorm.DB, text string) string {
+	words := queryWords(text)
+	if len(words) == 0 {
+		return ""
+	}
+
+	var rows []correction
+	db.Raw(`
+		SELECT DISTINCT ON (q.idx) q.idx, v.word
+		FROM unnest(?::text[]) WITH ORDINALITY AS q(w, idx)
+		JOIN search_vocabulary v ON similarity(v.word, q.w) >= 0.3
+		ORDER BY q.idx, similarity(v.word, q.w) DESC, v.ndoc DESC
+	`, pq.StringArray(words)).Scan(&rows)
+
+	fixes := make(map[int]string, len(rows))
+	for _, r := range rows {
+		fixes[r.Idx-1] = r.Word
+	}
+	return applyCorrections(words, fixes)
+}
+
+// applyCorrections rebuilds the query from words with fixes applied by
+// index; "" if no word changed.
+func applyCorrections(words []string, fixes map[int]string) string {
+	changed := false
+	out := make([]string, len(words))
+	for i, w := range words {
+		out[i] = w
+		if fix, ok := fixes[i]; ok && fix != w {
+			out[i] = fix
+			changed = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+	return strings.Join(out, " ")
+}
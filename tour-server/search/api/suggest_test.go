@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestApplyCorrections(t *testing.T) {
+	words := []string{"тур", "мальдиви"}
+
+	if got := applyCorrections(words, map[int]string{1: "мальдіви"}); got != "тур мальдіви" {
+		t.Errorf("got %q, want %q", got, "тур мальдіви")
+	}
+	if got := applyCorrections(words, map[int]string{0: "тур", 1: "мальдиви"}); got != "" {
+		t.Errorf("expected no correction when every word is known, got %q", got)
+	}
+	if got := applyCorrections(words, nil); got != "" {
+		t.Errorf("expected no correction without matches, got %q", got)
+	}
+}
+
+func TestSearchSuggestShortQuery(t *testing.T) {
+	for _, q := range []string{"", "м", "  к  "} {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/search/suggest?q="+url.QueryEscape(q), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		// A nil DB would panic if the query were run
+		if err := SearchSuggest(nil)(c); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("q=%q: expected 200, got %d", q, rec.Code)
+		}
+		var res SuggestResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Suggestions == nil || len(res.Suggestions) != 0 {
+			t.Errorf("q=%q: expected an empty list, got %v", q, res.Suggestions)
+		}
+	}
+}
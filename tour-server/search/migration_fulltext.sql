@@ -0,0 +1,93 @@
+-- Migration: full-text search over tours
+-- tours.search_vector combines the title (weight A), destination names (B),
+-- short description (B), detailed description and itinerary (C), each
+-- indexed with both the Ukrainian and the English configuration. Triggers
+-- keep it current when a tour, its itinerary or its departures change;
+-- renaming a city is picked up on the tour's next change.
+-- Run after geo/migration.sql and touritinerary/migration.sql.
+
+BEGIN;
+
+-- Stock Postgres has no Ukrainian configuration. Fall back to `simple`
+-- (lower-casing, no stemming); installing the uk_UA hunspell dictionary
+-- and altering this configuration's mapping adds word-form handling
+-- without code changes.
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'ukrainian') THEN
+        CREATE TEXT SEARCH CONFIGURATION public.ukrainian (COPY = pg_catalog.simple);
+    END IF;
+END $$;
+
+ALTER TABLE tours ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+CREATE OR REPLACE FUNCTION tour_search_text(doc TEXT, weight "char")
+RETURNS TSVECTOR
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT setweight(to_tsvector('public.ukrainian', COALESCE(doc, '')), weight) ||
+           setweight(to_tsvector('english', COALESCE(doc, '')), weight)
+$$;
+
+CREATE OR REPLACE FUNCTION tour_search_vector(p_tour_id INTEGER, p_title TEXT,
+                                              p_description TEXT, p_detailed TEXT)
+RETURNS TSVECTOR
+LANGUAGE sql STABLE AS $$
+    SELECT tour_search_text(p_title, 'A') ||
+           tour_search_text(p_description, 'B') ||
+           tour_search_text((
+               SELECT string_agg(DISTINCT concat_ws(' ', ci.name, ci.name_en, co.name, co.name_en), ' ')
+               FROM tour_dates td
+               JOIN cities ci ON ci.id = td.to_location_id
+               JOIN countries co ON co.id = ci.country_id
+               WHERE td.tour_id = p_tour_id AND NOT td.is_retired
+           ), 'B') ||
+           tour_search_text(p_detailed, 'C') ||
+           tour_search_text((
+               SELECT string_agg(concat_ws(' ', d.title, d.description), ' ' ORDER BY d.day_number)
+               FROM tour_itinerary_days d
+               WHERE d.tour_id = p_tour_id
+           ), 'C')
+$$;
+
+CREATE OR REPLACE FUNCTION tours_search_vector_trigger()
+RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.search_vector := tour_search_vector(NEW.id, NEW.title, NEW.description, NEW.detailed_description);
+    RETURN NEW;
+END $$;
+
+DROP TRIGGER IF EXISTS trg_tours_search_vector ON tours;
+CREATE TRIGGER trg_tours_search_vector
+    BEFORE INSERT OR UPDATE OF title, description, detailed_description ON tours
+    FOR EACH ROW EXECUTE FUNCTION tours_search_vector_trigger();
+
+-- Itinerary days and departures live in other tables; touching the tour's
+-- title column re-runs the trigger above.
+CREATE OR REPLACE FUNCTION tour_children_search_trigger()
+RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        UPDATE tours SET title = title WHERE id = OLD.tour_id;
+    END IF;
+    IF TG_OP <> 'DELETE' AND (TG_OP = 'INSERT' OR NEW.tour_id <> OLD.tour_id) THEN
+        UPDATE tours SET title = title WHERE id = NEW.tour_id;
+    END IF;
+    RETURN NULL;
+END $$;
+
+DROP TRIGGER IF EXISTS trg_itinerary_search_vector ON tour_itinerary_days;
+CREATE TRIGGER trg_itinerary_search_vector
+    AFTER INSERT OR UPDATE OF title, description, tour_id OR DELETE ON tour_itinerary_days
+    FOR EACH ROW EXECUTE FUNCTION tour_children_search_trigger();
+
+DROP TRIGGER IF EXISTS trg_tour_dates_search_vector ON tour_dates;
+CREATE TRIGGER trg_tour_dates_search_vector
+    AFTER INSERT OR UPDATE OF to_location_id, is_retired, tour_id OR DELETE ON tour_dates
+    FOR EACH ROW EXECUTE FUNCTION tour_children_search_trigger();
+
+UPDATE tours SET search_vector = tour_search_vector(id, title, description, detailed_description);
+
+CREATE INDEX IF NOT EXISTS idx_tours_search_vector ON tours USING GIN (search_vector);
+
+COMMIT;
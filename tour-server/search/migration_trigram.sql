@@ -0,0 +1,29 @@
+-- Migration: typo-tolerant suggestions
+-- pg_trgm (bundled contrib extension) powers GET /search/suggest and the
+-- "did you mean" correction on empty /search results.
+-- Run after search/migration_fulltext.sql and tourtaxonomy/migration.sql.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_tours_title_trgm        ON tours      USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_cities_name_trgm        ON cities     USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_cities_name_en_trgm     ON cities     USING GIN (name_en gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_countries_name_trgm     ON countries  USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_countries_name_en_trgm  ON countries  USING GIN (name_en gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm    ON categories USING GIN (name gin_trgm_ops);
+
+-- Words a query can be corrected to: active tour titles, destination and
+-- category names. Only read when a search comes back empty.
+CREATE OR REPLACE VIEW search_vocabulary AS
+SELECT word, ndoc
+FROM ts_stat($q$
+    SELECT to_tsvector('simple', title) FROM tours
+    WHERE status_id = (SELECT id FROM statuses WHERE name = 'active')
+    UNION ALL
+    SELECT to_tsvector('simple', concat_ws(' ', name, name_en)) FROM cities
+    UNION ALL
+    SELECT to_tsvector('simple', concat_ws(' ', name, name_en)) FROM countries
+    UNION ALL
+    SELECT to_tsvector('simple', name) FROM categories
+$q$)
+WHERE length(word) >= 3;
@@ -0,0 +1,169 @@
+-- Migration: denormalized catalog index
+-- One row per tour with the card data the catalog endpoints used to work
+-- out per request with correlated subqueries: cover image, duration and
+-- destination of the representative departure, the next departure and
+-- its seats. Kept current by triggers on every table it reads from;
+-- rows whose next departure has passed are refreshed by
+-- searchindex.StartRefreshJob.
+-- Run after geo/migration.sql and tourdate/migration.sql.
+
+CREATE TABLE IF NOT EXISTS tour_search_index (
+    tour_id              INTEGER PRIMARY KEY REFERENCES tours(id) ON DELETE CASCADE,
+    status_id            INTEGER,
+    title                TEXT NOT NULL,
+    price                NUMERIC NOT NULL DEFAULT 0,
+    rating               NUMERIC NOT NULL DEFAULT 0,
+    image_src            TEXT,                       -- NULL: no card image
+    duration             INTEGER NOT NULL DEFAULT 0, -- days
+    destination_city_id  INTEGER,
+    destination          TEXT NOT NULL DEFAULT '',   -- "City, Country"
+    min_price            NUMERIC,                    -- per person; NULL: nothing bookable
+    next_departure_id    INTEGER,
+    next_departure_at    TIMESTAMP,
+    seats_left           INTEGER NOT NULL DEFAULT 0, -- on the next departure
+    guaranteed           BOOLEAN NOT NULL DEFAULT FALSE,
+    refreshed_at         TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tour_search_index_status ON tour_search_index(status_id);
+CREATE INDEX IF NOT EXISTS idx_tour_search_index_price  ON tour_search_index(price);
+CREATE INDEX IF NOT EXISTS idx_tour_search_index_next   ON tour_search_index(next_departure_at);
+
+-- Rebuilds one tour's row (or drops it if the tour is gone). The
+-- representative departure is the next upcoming one, else the earliest,
+-- as on the tour page.
+CREATE OR REPLACE FUNCTION refresh_tour_search_index(p_tour_id INTEGER) RETURNS VOID AS $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM tours WHERE id = p_tour_id) THEN
+        DELETE FROM tour_search_index WHERE tour_id = p_tour_id;
+        RETURN;
+    END IF;
+
+    INSERT INTO tour_search_index (
+        tour_id, status_id, title, price, rating, image_src, duration,
+        destination_city_id, destination, min_price,
+        next_departure_id, next_departure_at, seats_left, guaranteed, refreshed_at
+    )
+    SELECT t.id, t.status_id, t.title, t.price, COALESCE(t.rating, 0),
+        (SELECT image_src FROM tour_card_images WHERE tour_id = t.id LIMIT 1),
+        COALESCE(EXTRACT(DAY FROM (d.date_to - d.date_from))::INTEGER, 0),
+        d.to_location_id,
+        CASE WHEN l.id IS NULL THEN '' ELSE CONCAT(l.name, ', ', l.country) END,
+        CASE WHEN EXISTS (
+            SELECT 1 FROM tour_dates td
+            JOIN tour_seats ts ON ts.tour_date_id = td.id
+            WHERE td.tour_id = t.id AND NOT td.is_retired
+                AND td.date_from > NOW() AND ts.available_seats > 0
+        ) THEN t.price END,
+        n.id, n.date_from, COALESCE(n.available_seats, 0),
+        EXISTS (
+            SELECT 1 FROM tour_dates td
+            WHERE td.tour_id = t.id AND td.is_guaranteed
+                AND NOT td.is_retired AND td.date_from > NOW()
+        ),
+        NOW()
+    FROM tours t
+    LEFT JOIN LATERAL (
+        SELECT td.date_from, td.date_to, td.to_location_id
+        FROM tour_dates td
+        WHERE td.tour_id = t.id AND NOT td.is_retired
+        ORDER BY td.date_from < NOW(), td.date_from
+        LIMIT 1
+    ) d ON TRUE
+    LEFT JOIN locations l ON l.id = d.to_location_id
+    LEFT JOIN LATERAL (
+        SELECT td.id, td.date_from, ts.available_seats
+        FROM tour_dates td
+        LEFT JOIN tour_seats ts ON ts.tour_date_id = td.id
+        WHERE td.tour_id = t.id AND NOT td.is_retired AND td.date_from > NOW()
+        ORDER BY td.date_from
+        LIMIT 1
+    ) n ON TRUE
+    WHERE t.id = p_tour_id
+    ON CONFLICT (tour_id) DO UPDATE SET
+        status_id = EXCLUDED.status_id,
+        title = EXCLUDED.title,
+        price = EXCLUDED.price,
+        rating = EXCLUDED.rating,
+        image_src = EXCLUDED.image_src,
+        duration = EXCLUDED.duration,
+        destination_city_id = EXCLUDED.destination_city_id,
+        destination = EXCLUDED.destination,
+        min_price = EXCLUDED.min_price,
+        next_departure_id = EXCLUDED.next_departure_id,
+        next_departure_at = EXCLUDED.next_departure_at,
+        seats_left = EXCLUDED.seats_left,
+        guaranteed = EXCLUDED.guaranteed,
+        refreshed_at = EXCLUDED.refreshed_at;
+END;
+$$ LANGUAGE plpgsql;
+
+-- One trigger function for every source table; refreshes only the tours
+-- the changed row belongs to. tours.rating is maintained from ratings and
+-- reviews by trg_tour_rating (tourratings/migration.sql), so ratings are
+-- covered by tours.
+CREATE OR REPLACE FUNCTION tour_search_index_sync() RETURNS TRIGGER AS $$
+DECLARE
+    r RECORD;
+BEGIN
+    IF TG_OP = 'DELETE' THEN r := OLD; ELSE r := NEW; END IF;
+
+    CASE TG_TABLE_NAME
+    WHEN 'tours' THEN
+        PERFORM refresh_tour_search_index(r.id);
+    WHEN 'tour_dates', 'tour_card_images' THEN
+        PERFORM refresh_tour_search_index(r.tour_id);
+        IF TG_OP = 'UPDATE' AND OLD.tour_id IS DISTINCT FROM NEW.tour_id THEN
+            PERFORM refresh_tour_search_index(OLD.tour_id);
+        END IF;
+    WHEN 'tour_seats' THEN
+        PERFORM refresh_tour_search_index(td.tour_id)
+        FROM tour_dates td WHERE td.id = r.tour_date_id;
+    WHEN 'cities' THEN
+        PERFORM refresh_tour_search_index(s.tour_id)
+        FROM (SELECT DISTINCT tour_id FROM tour_dates WHERE to_location_id = r.id) s;
+    WHEN 'countries' THEN
+        PERFORM refresh_tour_search_index(s.tour_id)
+        FROM (
+            SELECT DISTINCT td.tour_id FROM tour_dates td
+            JOIN cities ci ON ci.id = td.to_location_id
+            WHERE ci.country_id = r.id
+        ) s;
+    END CASE;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_tour_search_index ON tours;
+CREATE TRIGGER trg_tour_search_index
+    AFTER INSERT OR UPDATE OF title, price, rating, status_id ON tours
+    FOR EACH ROW EXECUTE FUNCTION tour_search_index_sync();
+
+DROP TRIGGER IF EXISTS trg_tour_search_index ON tour_dates;
+CREATE TRIGGER trg_tour_search_index
+    AFTER INSERT OR UPDATE OR DELETE ON tour_dates
+    FOR EACH ROW EXECUTE FUNCTION tour_search_index_sync();
+
+DROP TRIGGER IF EXISTS trg_tour_search_index ON tour_seats;
+CREATE TRIGGER trg_tour_search_index
+    AFTER INSERT OR UPDATE OF available_seats OR DELETE ON tour_seats
+    FOR EACH ROW EXECUTE FUNCTION tour_search_index_sync();
+
+DROP TRIGGER IF EXISTS trg_tour_search_index ON tour_card_images;
+CREATE TRIGGER trg_tour_search_index
+    AFTER INSERT OR UPDATE OR DELETE ON tour_card_images
+    FOR EACH ROW EXECUTE FUNCTION tour_search_index_sync();
+
+DROP TRIGGER IF EXISTS trg_tour_search_index ON cities;
+CREATE TRIGGER trg_tour_search_index
+    AFTER UPDATE OF name, country_id ON cities
+    FOR EACH ROW EXECUTE FUNCTION tour_search_index_sync();
+
+DROP TRIGGER IF EXISTS trg_tour_search_index ON countries;
+CREATE TRIGGER trg_tour_search_index
+    AFTER UPDATE OF name ON countries
+    FOR EACH ROW EXECUTE FUNCTION tour_search_index_sync();
+
+-- Backfill
+SELECT refresh_tour_search_index(id) FROM tours;
@@ -0,0 +1,52 @@
+// Package searchindex maintains tour_search_index, the denormalized
+// per-tour card data the catalog endpoints read (see migration.sql).
+// Writes to tours, dates, seats, card images and destinations are picked
+// up by triggers; this package only covers what changes with time.
+package searchindex
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Refresh rebuilds one tour's index row.
+func Refresh(db *gorm.DB, tourID uint) error {
+	return db.Exec("SELECT refresh_tour_search_index(?)", tourID).Error
+}
+
+// RefreshDeparted rebuilds the rows whose next departure has left, so the
+// next one (its date and seats) takes its place. Returns how many rows
+// were refreshed.
+func RefreshDeparted(db *gorm.DB) (int, error) {
+	var ids []uint
+	err := db.Raw(`
+		SELECT tour_id FROM tour_search_index
+		WHERE next_departure_at <= NOW()
+	`).Scan(&ids).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if err := Refresh(db, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// StartRefreshJob runs RefreshDeparted every interval in a goroutine.
+func StartRefreshJob(db *gorm.DB, interval time.Duration) {
+	go func() {
+		for {
+			if n, err := RefreshDeparted(db); err != nil {
+				log.Printf("Search index refresh failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Search index: refreshed %d departed tours", n)
+			}
+			time.Sleep(interval)
+		}
+	}()
+	log.Printf("Search index refresh job started (every %s)", interval)
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"tour-server/searchlog"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// maxReportDays caps the report range.
+const maxReportDays = 366
+
+type clickRequest struct {
+	SearchID string `json:"search_id"`
+	TourID   uint   `json:"tour_id"`
+}
+
+// POST /search/click — a result from a logged search was opened
+func RecordSearchClick(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req clickRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Невірний формат запиту"})
+		}
+		if !searchlog.ValidID(req.SearchID) || req.TourID == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "search_id і tour_id обов'язкові"})
+		}
+		if err := searchlog.RecordClick(db, req.SearchID, req.TourID); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to record click"})
+		}
+		return c.JSON(http.StatusOK, map[string]string{"message": "ok"})
+	}
+}
+
+// Counts are scaled by 1/sample_rate, so they estimate real traffic.
+type SourceTotals struct {
+	Source         string  `json:"source" gorm:"column:source"`
+	Searches       float64 `json:"searches" gorm:"column:searches"`
+	ZeroResults    float64 `json:"zero_results" gorm:"column:zero_results"`
+	Clicks         float64 `json:"clicks" gorm:"column:clicks"`
+	Bookings       float64 `json:"bookings" gorm:"column:bookings"`
+	ZeroResultRate float64 `json:"zero_result_rate" gorm:"-"`
+	ClickRate      float64 `json:"click_rate" gorm:"-"`
+	ConversionRate float64 `json:"conversion_rate" gorm:"-"` // searches ending in a booking
+}
+
+type QueryStat struct {
+	Query      string    `json:"query" gorm:"column:query"`
+	Searches   float64   `json:"searches" gorm:"column:searches"`
+	AvgResults float64   `json:"avg_results" gorm:"column:avg_results"`
+	Clicks     float64   `json:"clicks" gorm:"column:clicks"`
+	Bookings   float64   `json:"bookings" gorm:"column:bookings"`
+	LastSeen   time.Time `json:"last_seen" gorm:"column:last_seen"`
+}
+
+type SearchReport struct {
+	From              string         `json:"from"`
+	To                string         `json:"to"`
+	Totals            []SourceTotals `json:"totals"`
+	TopQueries        []QueryStat    `json:"top_queries"`
+	ZeroResultQueries []QueryStat    `json:"zero_result_queries"`
+}
+
+// parseRange reads from/to (YYYY-MM-DD, inclusive; default: the last 30
+// days) and returns [from, to+1 day).
+func parseRange(fromStr, toStr string, today time.Time) (time.Time, time.Time, string) {
+	to := today
+	if toStr != "" {
+		t, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, "to має бути у форматі YYYY-MM-DD"
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -29)
+	if fromStr != "" {
+		f, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, "from має бути у форматі YYYY-MM-DD"
+		}
+		from = f
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, "from не може бути пізніше за to"
+	}
+	if to.Sub(from) >= maxReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, "Період не може перевищувати 366 днів"
+	}
+	return from, to.AddDate(0, 0, 1), ""
+}
+
+const queryStatsSQL = `
+	SELECT query,
+		ROUND(SUM(1 / sample_rate)::numeric, 1) AS searches,
+		ROUND(AVG(result_count)::numeric, 1) AS avg_results,
+		ROUND(COALESCE(SUM(1 / sample_rate) FILTER (WHERE clicked_at IS NOT NULL), 0)::numeric, 1) AS clicks,
+		ROUND(COALESCE(SUM(1 / sample_rate) FILTER (WHERE booking_id IS NOT NULL), 0)::numeric, 1) AS bookings,
+		MAX(created_at) AS last_seen
+	FROM search_log
+	WHERE source = ? AND query <> '' AND created_at >= ? AND created_at < ?`
+
+// GET /admin/search-report?from=&to=&source=search|suggest&limit=
+func GetSearchReport(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		now := time.Now()
+		from, to, msg := parseRange(c.QueryParam("from"), c.QueryParam("to"),
+			time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+		if msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		source := c.QueryParam("source")
+		if source == "" {
+			source = searchlog.SourceSearch
+		}
+		if source != searchlog.SourceSearch && source != searchlog.SourceSuggest {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "source має бути search або suggest"})
+		}
+		limit := 20
+		if v, err := strconv.Atoi(c.QueryParam("limit")); err == nil && v > 0 && v <= 100 {
+			limit = v
+		}
+
+		report := SearchReport{
+			From:              from.Format("2006-01-02"),
+			To:                to.AddDate(0, 0, -1).Format("2006-01-02"),
+			Totals:            []SourceTotals{},
+			TopQueries:        []QueryStat{},
+			ZeroResultQueries: []QueryStat{},
+		}
+
+		if err := db.Raw(`
+			SELECT source,
+				ROUND(SUM(1 / sample_rate)::numeric, 1) AS searches,
+				ROUND(COALESCE(SUM(1 / sample_rate) FILTER (WHERE result_count = 0), 0)::numeric, 1) AS zero_results,
+				ROUND(COALESCE(SUM(1 / sample_rate) FILTER (WHERE clicked_at IS NOT NULL), 0)::numeric, 1) AS clicks,
+				ROUND(COALESCE(SUM(1 / sample_rate) FILTER (WHERE booking_id IS NOT NULL), 0)::numeric, 1) AS bookings
+			FROM search_log
+			WHERE created_at >= ? AND created_at < ?
+			GROUP BY source
+			ORDER BY source
+		`, from, to).Scan(&report.Totals).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch search totals"})
+		}
+		for i := range report.Totals {
+			report.Totals[i].rates()
+		}
+
+		if err := db.Raw(queryStatsSQL+`
+			GROUP BY query
+			ORDER BY searches DESC, query
+			LIMIT ?
+		`, source, from, to, limit).Scan(&report.TopQueries).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch top queries"})
+		}
+
+		if err := db.Raw(queryStatsSQL+` AND result_count = 0
+			GROUP BY query
+			ORDER BY searches DESC, query
+			LIMIT ?
+		`, source, from, to, limit).Scan(&report.ZeroResultQueries).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch zero-result queries"})
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
+
+func (t *SourceTotals) rates() {
+	if t.Searches == 0 {
+		return
+	}
+	t.ZeroResultRate = round(t.ZeroResults / t.Searches)
+	t.ClickRate = round(t.Clicks / t.Searches)
+	t.ConversionRate = round(t.Bookings / t.Searches)
+}
+
+// round keeps four decimals, enough for a percentage with two.
+func round(v float64) float64 {
+	return float64(int64(v*10000+0.5)) / 10000
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseRange(t *testing.T) {
+	today := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	from, to, msg := parseRange("", "", today)
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if from.Format("2006-01-02") != "2026-03-02" || to.Format("2006-01-02") != "2026-04-01" {
+		t.Errorf("unexpected default range %s – %s", from, to)
+	}
+
+	for _, r := range [][2]string{{"2026-13-01", ""}, {"2026-03-10", "2026-03-01"}, {"2024-01-01", "2026-01-01"}} {
+		if _, _, msg := parseRange(r[0], r[1], today); msg == "" {
+			t.Errorf("expected error for %v", r)
+		}
+	}
+}
+
+func TestGetSearchReport_InvalidSource(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/search-report?source=all", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := GetSearchReport(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestRecordSearchClick_InvalidID(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/search/click", strings.NewReader(`{"search_id": "abc", "tour_id": 1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := RecordSearchClick(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,22 @@
+-- Migration: search analytics
+-- One row per logged /search (first page only) or /search/suggest call.
+-- Guests are anonymous: no user, IP or user agent is stored. Under load
+-- only a sample is kept; sample_rate lets reports scale counts back up.
+
+CREATE TABLE IF NOT EXISTS search_log (
+    id               CHAR(32) PRIMARY KEY,        -- returned to clients as searchId
+    source           VARCHAR(10) NOT NULL,        -- search | suggest
+    query            VARCHAR(200) NOT NULL DEFAULT '',
+    filters          JSONB NOT NULL DEFAULT '{}',
+    result_count     INTEGER NOT NULL,
+    user_id          INTEGER REFERENCES tour_users(id) ON DELETE SET NULL,
+    sample_rate      REAL NOT NULL DEFAULT 1,
+    clicked_tour_id  INTEGER,
+    clicked_at       TIMESTAMP,
+    booking_id       INTEGER REFERENCES bookings(id) ON DELETE SET NULL,
+    booked_at        TIMESTAMP,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_log_created_at ON search_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_search_log_query ON search_log(source, query);
@@ -0,0 +1,178 @@
+// Package searchlog records what people search for, how many results
+// they got and whether a click or booking followed (see migration.sql).
+// Entries are written in the background and sampled under load; nothing
+// is recorded until Start is called.
+package searchlog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	mathrand "math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sources
+const (
+	SourceSearch  = "search"
+	SourceSuggest = "suggest"
+)
+
+// FullRatePerSecond is how many searches per second are all logged;
+// above it a fraction is kept so that about this many are written.
+const FullRatePerSecond = 20
+
+// maxPendingWrites bounds the inserts in flight; entries beyond it are
+// dropped rather than queued.
+const maxPendingWrites = 50
+
+type Entry struct {
+	Source      string
+	Query       string            // raw; normalized by Record
+	Filters     map[string]string // applied filters, text excluded
+	ResultCount int
+	UserID      *uint // nil for guests
+}
+
+var (
+	db      *gorm.DB
+	pending = make(chan struct{}, maxPendingWrites)
+	sampler = newSampler(FullRatePerSecond)
+)
+
+// Start enables logging to database. Call once at startup.
+func Start(database *gorm.DB) {
+	db = database
+	log.Printf("Search log enabled (all searches up to %d/s, sampled above)", FullRatePerSecond)
+}
+
+// Record logs an entry in the background and returns its id for click
+// and booking attribution, or "" if it wasn't kept.
+func Record(e Entry) string {
+	if db == nil {
+		return ""
+	}
+	rate, keep := sampler.sample(time.Now())
+	if !keep {
+		return ""
+	}
+	select {
+	case pending <- struct{}{}:
+	default:
+		return ""
+	}
+
+	id := newID()
+	filters, _ := json.Marshal(e.Filters)
+	if e.Filters == nil {
+		filters = []byte("{}")
+	}
+	go func() {
+		defer func() { <-pending }()
+		err := db.Exec(`
+			INSERT INTO search_log (id, source, query, filters, result_count, user_id, sample_rate)
+			VALUES (?, ?, ?, ?::jsonb, ?, ?, ?)
+		`, id, e.Source, NormalizeQuery(e.Query), string(filters), e.ResultCount, e.UserID, rate).Error
+		if err != nil {
+			log.Printf("Search log: %v", err)
+		}
+	}()
+	return id
+}
+
+// RecordClick marks the first result opened from a logged search.
+func RecordClick(db *gorm.DB, searchID string, tourID uint) error {
+	if !ValidID(searchID) {
+		return nil
+	}
+	return db.Exec(`
+		UPDATE search_log SET clicked_tour_id = ?, clicked_at = NOW()
+		WHERE id = ? AND clicked_at IS NULL
+	`, tourID, searchID).Error
+}
+
+// RecordBooking attributes a booking to the search it came from. A
+// booking counts as a click too.
+func RecordBooking(db *gorm.DB, searchID string, bookingID uint) error {
+	if !ValidID(searchID) {
+		return nil
+	}
+	return db.Exec(`
+		UPDATE search_log
+		SET booking_id = ?, booked_at = NOW(), clicked_at = COALESCE(clicked_at, NOW())
+		WHERE id = ? AND booking_id IS NULL
+	`, bookingID, searchID).Error
+}
+
+// NormalizeQuery lowercases the query, collapses whitespace and caps it
+// at 200 characters, so that the same search groups together.
+func NormalizeQuery(q string) string {
+	q = strings.Join(strings.Fields(strings.ToLower(q)), " ")
+	if r := []rune(q); len(r) > 200 {
+		q = string(r[:200])
+	}
+	return q
+}
+
+// ValidID reports whether s looks like an id returned by Record.
+func ValidID(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// rateSampler keeps everything while searches per second stay within
+// limit and about limit per second above it, judged by the busier of the
+// current and the previous second.
+type rateSampler struct {
+	mu        sync.Mutex
+	limit     int
+	second    int64
+	count     int
+	prevCount int
+	random    func() float64
+}
+
+func newSampler(limit int) *rateSampler {
+	return &rateSampler{limit: limit, random: mathrand.Float64}
+}
+
+// sample returns the sampling rate in effect and whether to keep this
+// entry.
+func (s *rateSampler) sample(now time.Time) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec := now.Unix()
+	switch {
+	case sec == s.second:
+	case sec == s.second+1:
+		s.second, s.prevCount, s.count = sec, s.count, 0
+	default:
+		s.second, s.prevCount, s.count = sec, 0, 0
+	}
+	s.count++
+
+	busiest := s.count
+	if s.prevCount > busiest {
+		busiest = s.prevCount
+	}
+	if busiest <= s.limit {
+		return 1, true
+	}
+	rate := float64(s.limit) / float64(busiest)
+	return rate, s.random() < rate
+}
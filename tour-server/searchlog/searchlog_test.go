@@ -0,0 +1,60 @@
+package searchlog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	if got := NormalizeQuery("  Тури   до\tКарпат "); got != "тури до карпат" {
+		t.Errorf("got %q", got)
+	}
+	if got := NormalizeQuery(strings.Repeat("я", 250)); len([]rune(got)) != 200 {
+		t.Errorf("expected 200 runes, got %d", len([]rune(got)))
+	}
+}
+
+func TestValidID(t *testing.T) {
+	if !ValidID(newID()) {
+		t.Error("generated id should be valid")
+	}
+	for _, s := range []string{"", "abc", strings.Repeat("z", 32)} {
+		if ValidID(s) {
+			t.Errorf("%q should be invalid", s)
+		}
+	}
+}
+
+func TestRecord_NotStarted(t *testing.T) {
+	if id := Record(Entry{Source: SourceSearch, Query: "море"}); id != "" {
+		t.Errorf("expected no id before Start, got %q", id)
+	}
+}
+
+func TestSampler(t *testing.T) {
+	s := newSampler(10)
+	s.random = func() float64 { return 0.99 }
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < 10; i++ {
+		if rate, keep := s.sample(now); rate != 1 || !keep {
+			t.Fatalf("entry %d: expected full logging, got rate %v", i, rate)
+		}
+	}
+	rate, keep := s.sample(now)
+	if keep || rate >= 1 {
+		t.Errorf("expected sampling above the limit, got rate %v keep %v", rate, keep)
+	}
+
+	// The next second is judged by the busier previous one.
+	rate, _ = s.sample(now.Add(time.Second))
+	if rate != 10.0/11 {
+		t.Errorf("expected rate 10/11, got %v", rate)
+	}
+
+	// After a quiet gap everything is logged again.
+	if rate, _ := s.sample(now.Add(5 * time.Second)); rate != 1 {
+		t.Errorf("expected full logging after a gap, got %v", rate)
+	}
+}
@@ -3,10 +3,19 @@ package main
 import (
 	"log"
 	"net/http"
+	"time"
 	"tour-server/config"
 	"tour-server/database"
 	"tour-server/email"
 	"tour-server/middleware"
+	"tour-server/notify"
+	"tour-server/recommendations"
+	"tour-server/savedsearches"
+	"tour-server/searchindex"
+	"tour-server/searchlog"
+	"tour-server/telegram"
+	"tour-server/tourratings"
+	"tour-server/webhooks"
 
 	adminAPI "tour-server/admin/api"
 	bookings "tour-server/bookings/api"
@@ -19,8 +28,16 @@ import (
 	userfavorites "tour-server/userfavorites/api"
 	tourcomments "tour-server/tourcomments/api"
 	liqpayAPI "tour-server/liqpay/api"
-	tourratings "tour-server/tourratings/api"
+	tourRatingsAPI "tour-server/tourratings/api"
 	tourviews "tour-server/tourviews/api"
+	telegramAPI "tour-server/telegram/api"
+	webhooksAPI "tour-server/webhooks/api"
+	taxonomyAPI "tour-server/tourtaxonomy/api"
+	geoAPI "tour-server/geo/api"
+	savedSearchesAPI "tour-server/savedsearches/api"
+	searchLogAPI "tour-server/searchlog/api"
+	recommendationsAPI "tour-server/recommendations/api"
+	moderationAPI "tour-server/moderation/api"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -65,6 +82,76 @@ func main() {
 		log.Println("SMTP credentials not set — email notifications disabled")
 	}
 
+	// ========================================
+	// SMS SERVICE INITIALIZATION
+	// ========================================
+	if cfg.SMS.Provider != "" {
+		gw, err := notify.NewGateway(cfg.SMS.Provider, cfg.SMS.Sender, cfg.SMS.Token, cfg.SMS.URL)
+		if err != nil {
+			log.Printf("SMS gateway not started: %v", err)
+		} else {
+			notify.Init(gw)
+			notify.StartReminderJob(database.DB, cfg.SMS.ReminderHours, 15*time.Minute)
+		}
+	}
+
+	// ========================================
+	// TELEGRAM STAFF BOT
+	// ========================================
+	tg := cfg.Telegram
+	if tg.BotToken != "" {
+		telegram.Init(telegram.Config{
+			Token:    tg.BotToken,
+			ChatID:   tg.ChatID,
+			StaffIDs: tg.StaffIDs,
+			APIURL:   tg.APIURL,
+		})
+		if tg.Mode == "webhook" {
+			if tg.WebhookURL == "" || tg.WebhookSecret == "" {
+				log.Println("Telegram webhook mode needs webhook_url and TELEGRAM_WEBHOOK_SECRET — bot updates disabled")
+			} else if err := telegramAPI.RegisterWebhook(tg.WebhookURL, tg.WebhookSecret); err != nil {
+				log.Printf("Telegram setWebhook failed: %v", err)
+			}
+		} else {
+			telegramAPI.StartPolling(database.DB)
+		}
+	}
+
+	// ========================================
+	// PARTNER WEBHOOKS (retry failed deliveries)
+	// ========================================
+	webhooks.StartRetryJob(database.DB, time.Minute)
+
+	// ========================================
+	// DEPARTURE GO/NO-GO (min participants)
+	// ========================================
+	adminAPI.StartGoNoGoJob(database.DB, 15*time.Minute)
+
+	// ========================================
+	// CATALOG SEARCH INDEX (departed tours)
+	// ========================================
+	searchindex.StartRefreshJob(database.DB, 5*time.Minute)
+
+	// ========================================
+	// SAVED SEARCH ALERTS (each search daily)
+	// ========================================
+	savedsearches.StartAlertJob(database.DB, time.Hour)
+
+	// ========================================
+	// TOUR RATINGS (daily full recalculation)
+	// ========================================
+	tourratings.StartJob(database.DB, 24*time.Hour)
+
+	// ========================================
+	// RECOMMENDATIONS (similar tours, popularity)
+	// ========================================
+	recommendations.StartJob(database.DB, 6*time.Hour)
+
+	// ========================================
+	// SEARCH ANALYTICS LOG (sampled under load)
+	// ========================================
+	searchlog.Start(database.DB)
+
 	// ========================================
 	// RATE LIMITERS
 	// ========================================
@@ -110,6 +197,7 @@ func main() {
 			"Authorization",
 			"X-Requested-With",
 			"X-Guest-Token",
+			"X-Guest-Session",
 		},
 		ExposeHeaders: []string{
 			"X-Total-Count",
@@ -178,27 +266,56 @@ func main() {
 	// ========================================
 	e.GET("/cards", api.GetToursForCards(database.DB))
 	e.GET("/tours", api.GetTours(database.DB))
+	e.GET("/tours/map.geojson", api.GetToursMap(database.DB))
 	e.GET("/tours/:id", api.GetTourById(database.DB))
+	e.GET("/tours/:id/departures", api.GetTourDepartures(database.DB))
+	e.GET("/tours/:id/calendar", api.GetTourCalendar(database.DB))
+	e.GET("/tours/:id/similar", recommendationsAPI.GetSimilarTours(database.DB))
+	e.GET("/tours/:id/rating-summary", tourRatingsAPI.GetRatingSummary(database.DB))
 	e.GET("/tourimage", tourcard.GetTourCardImages(database.DB))
 	e.GET("/tourswiper", api.GetToursForSwiper(database.DB))
 	e.GET("/tour-seats/:id", tourseats.GetTourSeatsByTourID(database.DB))
 	e.GET("/tour-carousel/:id", api.GetToursCarouselByID(database.DB))
 	e.GET("/tours-search-by-ids", api.GetToursForCardsByID(database.DB))
-	e.GET("/search", search.SearchTours(database.DB))
+	e.GET("/categories", taxonomyAPI.GetCategories(database.DB))
+	e.GET("/tags", taxonomyAPI.GetTags(database.DB))
+	e.GET("/collections", taxonomyAPI.GetCollections(database.DB))
+	e.GET("/collections/:slug", taxonomyAPI.GetCollectionBySlug(database.DB))
+	e.GET("/regions", geoAPI.GetRegions(database.DB))
+	e.GET("/departure-cities", geoAPI.GetDepartureCities(database.DB))
 	e.GET("/tour-reviews/:id", tourreviews.GetReviewsByTourID(database.DB))
 	e.GET("/stats", api.GetPublicStats(database.DB))
 
 	// Guest "pay later" magic-link endpoints (token-authenticated, no JWT)
 	e.GET("/bookings/by-token/:token", bookings.GetBookingByToken(database.DB))
+	e.GET("/bookings/by-token/:token/document", bookings.GetBookingDocumentByToken(database.DB))
 	e.POST("/bookings/by-token/:token/cancel", bookings.CancelBookingByToken(database.DB), bookingRL)
+	e.POST("/bookings/by-token/:token/transfer", bookings.TransferBookingByToken(database.DB), bookingRL)
+	e.GET("/booking-transfers/:token", bookings.GetBookingTransfer(database.DB))
+	e.POST("/booking-transfers/:token/accept", bookings.AcceptBookingTransfer(database.DB), bookingRL)
 	e.POST("/liqpay/create-payment-by-token", liqpayAPI.CreatePaymentByToken(database.DB), paymentRL)
 
+	// One-click unsubscribe from saved-search alert emails
+	e.GET("/saved-searches/unsubscribe/:token", savedSearchesAPI.UnsubscribeSavedSearch(database.DB))
+	e.POST("/saved-searches/unsubscribe/:token", savedSearchesAPI.UnsubscribeSavedSearch(database.DB))
+
+	// Guest "find my bookings": email one-time code → short-lived session
+	e.POST("/guest-bookings/code", bookings.RequestGuestAccessCode(database.DB), authRL)
+	e.POST("/guest-bookings/verify", bookings.VerifyGuestAccessCode(database.DB), authRL)
+	e.GET("/guest-bookings", bookings.GetGuestBookings(database.DB))
+	e.POST("/guest-bookings/:id/payment-link", bookings.ReissueGuestPaymentLink(database.DB), paymentRL)
+
 	// ========================================
 	// OPTIONAL AUTH (guests + authorized users)
 	// ========================================
 	optionalAuth := e.Group("")
 	optionalAuth.Use(middleware.OptionalJWTMiddleware())
 
+	// Search — logged for analytics, with user_id for signed-in users
+	optionalAuth.GET("/search", search.SearchTours(database.DB))
+	optionalAuth.GET("/search/suggest", search.SearchSuggest(database.DB))
+	optionalAuth.POST("/search/click", searchLogAPI.RecordSearchClick(database.DB))
+
 	// Booking — rate limited
 	optionalAuth.POST("/tour/bookings", bookings.PostBookings(database.DB), bookingRL)
 
@@ -206,6 +323,7 @@ func main() {
 	optionalAuth.GET("/tour-comments/:id", tourcomments.GetTourComments(database.DB))
 	optionalAuth.POST("/tour-comments", tourcomments.CreateComment(database.DB), commentRL)
 	optionalAuth.POST("/tour-comments/:id/like", tourcomments.ToggleLike(database.DB), commentRL)
+	optionalAuth.POST("/tour-comments/:id/report", tourcomments.ReportComment(database.DB), commentRL)
 
 	// ========================================
 	// LIQPAY (rate limited)
@@ -214,6 +332,13 @@ func main() {
 	optionalAuth.POST("/liqpay/confirm", liqpayAPI.ConfirmPayment(database.DB), paymentRL)
 	optionalAuth.POST("/liqpay/create-payment", liqpayAPI.CreatePayment(database.DB), paymentRL)
 
+	// ========================================
+	// TELEGRAM WEBHOOK (secret header checked)
+	// ========================================
+	if tg.BotToken != "" && tg.Mode == "webhook" {
+		e.POST("/telegram/webhook", telegramAPI.TelegramWebhook(database.DB, tg.WebhookSecret))
+	}
+
 	// ========================================
 	// PROTECTED ENDPOINTS (auth required)
 	// ========================================
@@ -225,13 +350,20 @@ func main() {
 	protected.GET("/user-bookings", bookings.GetUserBookings(database.DB))
 	protected.POST("/tour-reviews", tourreviews.CreateTourReview(database.DB), commentRL)
 	protected.PUT("/bookings/:id/cancel", bookings.CancelBooking(database.DB), bookingRL)
+	protected.POST("/bookings/:id/transfer", bookings.TransferBooking(database.DB), bookingRL)
+	protected.GET("/bookings/:id/document", bookings.GetBookingDocument(database.DB))
 	protected.POST("/user-favorites", userfavorites.AddFavorite(database.DB))
 	protected.GET("/user-favorites", userfavorites.GetUserFavorites(database.DB))
 	protected.DELETE("/user-favorites/:tour_id", userfavorites.RemoveFavorite(database.DB))
+	protected.GET("/saved-searches", savedSearchesAPI.GetSavedSearches(database.DB))
+	protected.POST("/saved-searches", savedSearchesAPI.CreateSavedSearch(database.DB))
+	protected.PUT("/saved-searches/:id", savedSearchesAPI.UpdateSavedSearch(database.DB))
+	protected.DELETE("/saved-searches/:id", savedSearchesAPI.DeleteSavedSearch(database.DB))
 	protected.PUT("/tour-comments/:id", tourcomments.UpdateComment(database.DB), commentRL)
 	protected.DELETE("/tour-comments/:id", tourcomments.DeleteComment(database.DB), commentRL)
-	protected.POST("/tour-ratings", tourratings.PostTourRating(database.DB))
-	protected.GET("/tour-ratings/:tour_id/my", tourratings.GetMyTourRating(database.DB))
+	protected.POST("/tour-ratings", tourRatingsAPI.PostTourRating(database.DB))
+	protected.GET("/tour-ratings/:tour_id/my", tourRatingsAPI.GetMyTourRating(database.DB))
+	protected.GET("/recommendations", recommendationsAPI.GetRecommendations(database.DB))
 	protected.POST("/tour-views/:tour_id", tourviews.RecordTourView(database.DB))
 	protected.GET("/tour-views", tourviews.GetRecentViews(database.DB))
 	protected.DELETE("/tour-views", tourviews.ClearViewHistory(database.DB))
@@ -261,10 +393,64 @@ func main() {
 	admin.POST("/tours", adminAPI.CreateTour(database.DB))
 	admin.PUT("/tours/:id", adminAPI.UpdateTour(database.DB))
 	admin.DELETE("/tours/:id", adminAPI.DeleteTour(database.DB))
-
-	admin.GET("/locations", adminAPI.GetLocations(database.DB))
+	admin.GET("/tours/:id/dates", adminAPI.GetAdminTourDates(database.DB))
+	admin.POST("/tours/:id/dates", adminAPI.CreateTourDate(database.DB))
+	admin.PUT("/tours/:id/dates/:dateId", adminAPI.UpdateTourDate(database.DB))
+	admin.DELETE("/tours/:id/dates/:dateId", adminAPI.DeleteTourDate(database.DB))
+	admin.POST("/tours/:id/dates/:dateId/cancel", adminAPI.CancelTourDate(database.DB))
+	admin.GET("/tours/:id/series", adminAPI.GetDepartureSeries(database.DB))
+	admin.POST("/tours/:id/series", adminAPI.CreateDepartureSeries(database.DB))
+	admin.PUT("/tours/:id/series/:seriesId", adminAPI.RegenerateDepartureSeries(database.DB))
+	admin.GET("/tours/:id/itinerary", adminAPI.GetAdminItinerary(database.DB))
+	admin.POST("/tours/:id/itinerary", adminAPI.CreateItineraryDay(database.DB))
+	admin.PUT("/tours/:id/itinerary/order", adminAPI.ReorderItinerary(database.DB))
+	admin.PUT("/tours/:id/itinerary/:dayId", adminAPI.UpdateItineraryDay(database.DB))
+	admin.DELETE("/tours/:id/itinerary/:dayId", adminAPI.DeleteItineraryDay(database.DB))
+	admin.GET("/tours/:id/taxonomy", taxonomyAPI.GetTourTaxonomy(database.DB))
+	admin.PUT("/tours/:id/taxonomy", taxonomyAPI.SetTourTaxonomy(database.DB))
+
+	admin.POST("/categories", taxonomyAPI.CreateCategory(database.DB))
+	admin.PUT("/categories/:id", taxonomyAPI.UpdateCategory(database.DB))
+	admin.DELETE("/categories/:id", taxonomyAPI.DeleteCategory(database.DB))
+	admin.GET("/tags", taxonomyAPI.GetAdminTags(database.DB))
+	admin.PUT("/tags/:id", taxonomyAPI.UpdateTag(database.DB))
+	admin.DELETE("/tags/:id", taxonomyAPI.DeleteTag(database.DB))
+	admin.GET("/collections", taxonomyAPI.GetAdminCollections(database.DB))
+	admin.POST("/collections", taxonomyAPI.CreateCollection(database.DB))
+	admin.PUT("/collections/:id", taxonomyAPI.UpdateCollection(database.DB))
+	admin.DELETE("/collections/:id", taxonomyAPI.DeleteCollection(database.DB))
+	admin.PUT("/collections/:id/tours", taxonomyAPI.SetCollectionTours(database.DB))
+
+	admin.GET("/locations", geoAPI.GetAdminCities(database.DB))
+	admin.GET("/regions", geoAPI.GetRegions(database.DB))
+	admin.POST("/regions", geoAPI.CreateRegion(database.DB))
+	admin.PUT("/regions/:id", geoAPI.UpdateRegion(database.DB))
+	admin.DELETE("/regions/:id", geoAPI.DeleteRegion(database.DB))
+	admin.GET("/countries", geoAPI.GetAdminCountries(database.DB))
+	admin.POST("/countries", geoAPI.CreateCountry(database.DB))
+	admin.PUT("/countries/:id", geoAPI.UpdateCountry(database.DB))
+	admin.DELETE("/countries/:id", geoAPI.DeleteCountry(database.DB))
+	admin.GET("/cities", geoAPI.GetAdminCities(database.DB))
+	admin.POST("/cities", geoAPI.CreateCity(database.DB))
+	admin.PUT("/cities/:id", geoAPI.UpdateCity(database.DB))
+	admin.DELETE("/cities/:id", geoAPI.DeleteCity(database.DB))
 	admin.POST("/upload", adminAPI.UploadImage)
 
+	admin.GET("/webhooks", webhooksAPI.GetWebhookEndpoints(database.DB))
+	admin.POST("/webhooks", webhooksAPI.CreateWebhookEndpoint(database.DB))
+	admin.PUT("/webhooks/:id", webhooksAPI.UpdateWebhookEndpoint(database.DB))
+	admin.DELETE("/webhooks/:id", webhooksAPI.DeleteWebhookEndpoint(database.DB))
+	admin.GET("/webhooks/:id/deliveries", webhooksAPI.GetWebhookDeliveries(database.DB))
+	admin.POST("/webhooks/deliveries/:id/replay", webhooksAPI.ReplayWebhookDelivery(database.DB))
+
+	admin.GET("/search-report", searchLogAPI.GetSearchReport(database.DB))
+
+	admin.GET("/comments", moderationAPI.GetAdminComments(database.DB))
+	admin.POST("/comments/approve", moderationAPI.ApproveComments(database.DB))
+	admin.POST("/comments/reject", moderationAPI.RejectComments(database.DB))
+	admin.GET("/comment-reports", moderationAPI.GetCommentReports(database.DB))
+	admin.POST("/comment-reports/:id/resolve", moderationAPI.ResolveCommentReports(database.DB))
+
 	// ========================================
 	// START SERVER
 	// ========================================
@@ -0,0 +1,153 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AlertEvent names a staff alert.
+type AlertEvent string
+
+const (
+	EventNewBooking      AlertEvent = "new_booking"
+	EventPaymentReceived AlertEvent = "payment_received"
+	EventPaymentFailed   AlertEvent = "payment_failed"
+	EventPaymentReversed AlertEvent = "payment_reversed"
+	// EventPaidAfterCancel: LiqPay reported a successful payment for a
+	// booking that had already been cancelled; staff must refund it.
+	EventPaidAfterCancel AlertEvent = "paid_after_cancel"
+)
+
+// BookingAlert holds the data shown in a staff alert.
+type BookingAlert struct {
+	BookingID     uint
+	TourTitle     string
+	DateFrom      time.Time
+	CustomerName  string
+	CustomerPhone string
+	CustomerEmail string
+	Seats         int
+	TotalPrice    float64
+	Status        string
+	IsGuest       bool
+}
+
+// AlertBooking posts event for the booking to the staff chat. New pending
+// bookings get confirm/cancel buttons. Runs in a goroutine — never blocks
+// or fails the caller.
+func AlertBooking(db *gorm.DB, event AlertEvent, bookingID uint) {
+	if !IsConfigured() || db == nil || bookingID == 0 {
+		return
+	}
+	go func() {
+		if err := alertBooking(db, event, bookingID); err != nil {
+			log.Printf("Telegram alert error: %v", err)
+		}
+	}()
+}
+
+func alertBooking(db *gorm.DB, event AlertEvent, bookingID uint) error {
+	a, err := LoadBookingAlert(db, bookingID)
+	if err != nil {
+		return err
+	}
+
+	var markup *InlineKeyboardMarkup
+	if event == EventNewBooking && a.Status == "pending" {
+		markup = bookingKeyboard(a.BookingID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := bot.SendMessage(ctx, chatID, AlertText(event, a), markup); err != nil {
+		return err
+	}
+	log.Printf("Telegram alert sent: %s booking #%d", event, bookingID)
+	return nil
+}
+
+// LoadBookingAlert reads the fields shown in an alert.
+func LoadBookingAlert(db *gorm.DB, bookingID uint) (BookingAlert, error) {
+	var row struct {
+		ID             uint      `gorm:"column:id"`
+		TourTitle      string    `gorm:"column:tour_title"`
+		DateFrom       time.Time `gorm:"column:date_from"`
+		CustomerName   string    `gorm:"column:customer_name"`
+		CustomerPhone  string    `gorm:"column:customer_phone"`
+		CustomerEmail  string    `gorm:"column:customer_email"`
+		Seats          int       `gorm:"column:seats"`
+		TotalPrice     float64   `gorm:"column:total_price"`
+		Status         string    `gorm:"column:status"`
+		IsGuestBooking bool      `gorm:"column:is_guest_booking"`
+	}
+	err := db.Raw(`
+		SELECT b.id, t.title AS tour_title, td.date_from,
+			b.customer_name, b.customer_phone, COALESCE(b.customer_email, '') AS customer_email,
+			b.seats, b.total_price, b.status, b.is_guest_booking
+		FROM bookings b
+		JOIN tour_dates td ON b.tour_date_id = td.id
+		JOIN tours t ON td.tour_id = t.id
+		WHERE b.id = ?
+	`, bookingID).Scan(&row).Error
+	if err != nil || row.ID == 0 {
+		return BookingAlert{}, fmt.Errorf("telegram: booking #%d not found", bookingID)
+	}
+	return BookingAlert{
+		BookingID:     row.ID,
+		TourTitle:     row.TourTitle,
+		DateFrom:      row.DateFrom,
+		CustomerName:  row.CustomerName,
+		CustomerPhone: row.CustomerPhone,
+		CustomerEmail: row.CustomerEmail,
+		Seats:         row.Seats,
+		TotalPrice:    row.TotalPrice,
+		Status:        row.Status,
+		IsGuest:       row.IsGuestBooking,
+	}, nil
+}
+
+// AlertText renders the HTML message for event. All user-supplied values
+// are escaped — customer names come straight from the booking form.
+func AlertText(event AlertEvent, a BookingAlert) string {
+	var header string
+	switch event {
+	case EventNewBooking:
+		header = "🆕 <b>Нове бронювання #%d</b>"
+	case EventPaymentReceived:
+		header = "💳 <b>Оплату отримано — бронювання #%d</b>"
+	case EventPaymentFailed:
+		header = "⚠️ <b>Оплата не пройшла — бронювання #%d</b>"
+	case EventPaymentReversed:
+		header = "↩️ <b>Платіж повернено — бронювання #%d скасовано</b>"
+	case EventPaidAfterCancel:
+		header = "❗ <b>Оплачено скасоване бронювання #%d — потрібне повернення коштів</b>"
+	default:
+		header = "ℹ️ <b>Бронювання #%d</b>"
+	}
+
+	who := "користувач"
+	if a.IsGuest {
+		who = "гість"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, header, a.BookingID)
+	fmt.Fprintf(&b, "\n\n🧭 %s", html.EscapeString(a.TourTitle))
+	if !a.DateFrom.IsZero() {
+		fmt.Fprintf(&b, "\n📅 %s", a.DateFrom.Format("02.01.2006"))
+	}
+	fmt.Fprintf(&b, "\n👤 %s (%s)", html.EscapeString(a.CustomerName), who)
+	fmt.Fprintf(&b, "\n📞 %s", html.EscapeString(a.CustomerPhone))
+	if a.CustomerEmail != "" {
+		fmt.Fprintf(&b, "\n✉️ %s", html.EscapeString(a.CustomerEmail))
+	}
+	fmt.Fprintf(&b, "\n🎫 %d міс. · %.2f ₴", a.Seats, a.TotalPrice)
+	fmt.Fprintf(&b, "\nСтатус: <code>%s</code>", html.EscapeString(a.Status))
+	return b.String()
+}
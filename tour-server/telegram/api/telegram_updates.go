@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	adminAPI "tour-server/admin/api"
+	"tour-server/telegram"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// pollTimeout is how long one getUpdates request is held open by Telegram.
+const pollTimeout = 50
+
+// TelegramWebhook receives updates pushed by Telegram in webhook mode.
+// Telegram echoes the secret we registered with setWebhook in the
+// X-Telegram-Bot-Api-Secret-Token header; anything else is rejected.
+// Always answers 200 once authenticated — a non-2xx makes Telegram
+// redeliver the same update.
+func TelegramWebhook(db *gorm.DB, secret string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if secret == "" || c.Request().Header.Get("X-Telegram-Bot-Api-Secret-Token") != secret {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
+		}
+
+		var update telegram.Update
+		if err := c.Bind(&update); err != nil {
+			log.Printf("Telegram webhook: bad update: %v", err)
+			return c.NoContent(http.StatusOK)
+		}
+
+		HandleUpdate(db, update)
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// RegisterWebhook points Telegram at url (webhook mode).
+func RegisterWebhook(url, secret string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return telegram.Bot().SetWebhook(ctx, url, secret)
+}
+
+// StartPolling runs the getUpdates loop in a goroutine (long-poll mode) —
+// for local development and servers Telegram can't reach.
+func StartPolling(db *gorm.DB) {
+	go func() {
+		bot := telegram.Bot()
+
+		// A registered webhook makes getUpdates fail, so clear it first.
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		if err := bot.DeleteWebhook(ctx); err != nil {
+			log.Printf("Telegram: deleteWebhook failed: %v", err)
+		}
+		cancel()
+
+		var offset int64
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), (pollTimeout+10)*time.Second)
+			updates, err := bot.GetUpdates(ctx, offset, pollTimeout)
+			cancel()
+			if err != nil {
+				log.Printf("Telegram: getUpdates failed: %v", err)
+				time.Sleep(5 * time.Second)
+				continue
+			}
+			for _, u := range updates {
+				HandleUpdate(db, u)
+				offset = u.UpdateID + 1
+			}
+		}
+	}()
+	log.Println("Telegram bot polling started")
+}
+
+// HandleUpdate dispatches one update from either mode.
+func HandleUpdate(db *gorm.DB, u telegram.Update) {
+	switch {
+	case u.CallbackQuery != nil:
+		handleCallback(db, u.CallbackQuery)
+	case u.Message != nil:
+		handleMessage(u.Message)
+	}
+}
+
+// handleMessage answers the setup commands. /start tells the sender their
+// Telegram user ID (for staff_ids); /chatid tells the chat ID (for chat_id).
+func handleMessage(m *telegram.Message) {
+	cmd := strings.Fields(m.Text)
+	if len(cmd) == 0 {
+		return
+	}
+	// Commands in groups arrive as "/chatid@BotName".
+	name := strings.SplitN(cmd[0], "@", 2)[0]
+
+	var reply string
+	switch name {
+	case "/start":
+		if m.From == nil {
+			return
+		}
+		reply = fmt.Sprintf("Бот сповіщень OpenWorld.\nВаш Telegram ID: <code>%d</code>", m.From.ID)
+	case "/chatid":
+		reply = fmt.Sprintf("ID цього чату: <code>%d</code>", m.Chat.ID)
+	default:
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := telegram.Bot().SendMessage(ctx, m.Chat.ID, reply, nil); err != nil {
+		log.Printf("Telegram: reply failed: %v", err)
+	}
+}
+
+// handleCallback processes a confirm/cancel button press. Only staff listed
+// in staff_ids may act, only pending bookings can be acted on, and the
+// status change goes through adminAPI.ApplyBookingStatus — the same path
+// as PUT /admin/bookings/:id/status, so seats and customer emails/SMS are
+// handled identically.
+func handleCallback(db *gorm.DB, cq *telegram.CallbackQuery) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	bot := telegram.Bot()
+
+	answer := func(text string) {
+		if err := bot.AnswerCallbackQuery(ctx, cq.ID, text); err != nil {
+			log.Printf("Telegram: answerCallbackQuery failed: %v", err)
+		}
+	}
+
+	action, bookingID, ok := telegram.ParseBookingCallback(cq.Data)
+	if !ok {
+		answer("Невідома дія")
+		return
+	}
+
+	if !telegram.IsStaff(cq.From.ID) {
+		log.Printf("Telegram: unauthorized %s on booking #%d by user %d", action, bookingID, cq.From.ID)
+		answer("Немає доступу")
+		return
+	}
+
+	var status string
+	db.Raw("SELECT status FROM bookings WHERE id = ?", bookingID).Scan(&status)
+	if status == "" {
+		answer("Бронювання не знайдено")
+		return
+	}
+	if status != "pending" {
+		answer(fmt.Sprintf("Бронювання вже має статус %s", status))
+		refreshAlert(ctx, db, cq, "")
+		return
+	}
+
+	newStatus, verdict := "confirmed", "✅ Підтверджено"
+	if action == telegram.ActionCancel {
+		newStatus, verdict = "cancelled", "❌ Скасовано"
+	}
+
+	changed, err := adminAPI.ApplyBookingStatusFrom(db, bookingID, "pending", newStatus)
+	if err != nil {
+		log.Printf("Telegram: %s booking #%d failed: %v", action, bookingID, err)
+		answer(err.Error())
+		return
+	}
+	if !changed {
+		// Another staff member pressed a button on it first.
+		answer("Бронювання вже оброблено")
+		refreshAlert(ctx, db, cq, "")
+		return
+	}
+
+	log.Printf("Telegram: booking #%d → %s by user %d", bookingID, newStatus, cq.From.ID)
+	answer("Готово")
+	refreshAlert(ctx, db, cq, fmt.Sprintf("%s — %s", verdict, staffName(cq.From)))
+}
+
+// refreshAlert re-renders the alert with the booking's current status and
+// drops the buttons so nobody presses them twice.
+func refreshAlert(ctx context.Context, db *gorm.DB, cq *telegram.CallbackQuery, note string) {
+	if cq.Message == nil {
+		return
+	}
+	_, bookingID, _ := telegram.ParseBookingCallback(cq.Data)
+	a, err := telegram.LoadBookingAlert(db, bookingID)
+	if err != nil {
+		return
+	}
+	text := telegram.AlertText(telegram.EventNewBooking, a)
+	if note != "" {
+		text += "\n\n" + note
+	}
+	if err := telegram.Bot().EditMessageText(ctx, cq.Message.Chat.ID, cq.Message.MessageID, text, nil); err != nil {
+		log.Printf("Telegram: editMessageText failed: %v", err)
+	}
+}
+
+func staffName(u telegram.User) string {
+	if u.Username != "" {
+		return "@" + html.EscapeString(u.Username)
+	}
+	return html.EscapeString(u.FirstName)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tour-server/telegram"
+	"tour-server/telegram/telegramtest"
+
+	"github.com/labstack/echo/v4"
+)
+
+func startBot(t *testing.T) *telegramtest.Server {
+	t.Helper()
+	srv := telegramtest.NewServer()
+	t.Cleanup(srv.Close)
+	telegram.Init(telegram.Config{Token: "TEST", ChatID: -100, StaffIDs: []int64{1}, APIURL: srv.URL})
+	return srv
+}
+
+func TestTelegramWebhook_RejectsBadSecret(t *testing.T) {
+	e := echo.New()
+	body := `{"update_id":1,"message":{"message_id":1,"chat":{"id":5},"text":"/chatid"}}`
+
+	for _, header := range []string{"", "wrong"} {
+		req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if header != "" {
+			req.Header.Set("X-Telegram-Bot-Api-Secret-Token", header)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := TelegramWebhook(nil, "s3cret")(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("secret %q: expected 403, got %d", header, rec.Code)
+		}
+	}
+}
+
+func TestTelegramWebhook_ChatIDCommand(t *testing.T) {
+	srv := startBot(t)
+	e := echo.New()
+	body := `{"update_id":1,"message":{"message_id":1,"chat":{"id":-555},"text":"/chatid@OpenWorldBot"}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "s3cret")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := TelegramWebhook(nil, "s3cret")(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	calls := srv.CallsTo("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	if calls[0].Params["chat_id"] != float64(-555) || !strings.Contains(calls[0].Params["text"].(string), "-555") {
+		t.Errorf("unexpected reply: %v", calls[0].Params)
+	}
+}
+
+func TestHandleUpdate_NonStaffCallbackRejected(t *testing.T) {
+	srv := startBot(t)
+
+	// db is nil: a non-staff press must be refused before any query runs.
+	HandleUpdate(nil, telegram.Update{
+		UpdateID: 1,
+		CallbackQuery: &telegram.CallbackQuery{
+			ID:   "cb1",
+			From: telegram.User{ID: 999},
+			Data: telegram.BookingCallbackData(telegram.ActionConfirm, 42),
+		},
+	})
+
+	calls := srv.CallsTo("answerCallbackQuery")
+	if len(calls) != 1 {
+		t.Fatalf("answerCallbackQuery calls = %d, want 1", len(calls))
+	}
+	if calls[0].Params["text"] != "Немає доступу" {
+		t.Errorf("answer text = %v", calls[0].Params["text"])
+	}
+	if n := len(srv.CallsTo("editMessageText")); n != 0 {
+		t.Errorf("message edited %d times for unauthorized user", n)
+	}
+}
+
+func TestHandleUpdate_UnknownCallbackData(t *testing.T) {
+	srv := startBot(t)
+
+	HandleUpdate(nil, telegram.Update{
+		UpdateID: 2,
+		CallbackQuery: &telegram.CallbackQuery{
+			ID:   "cb2",
+			From: telegram.User{ID: 1},
+			Data: "booking:refund:42",
+		},
+	})
+
+	calls := srv.CallsTo("answerCallbackQuery")
+	if len(calls) != 1 || calls[0].Params["text"] != "Невідома дія" {
+		t.Errorf("unexpected answers: %v", calls)
+	}
+}
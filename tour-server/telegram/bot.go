@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Config holds the staff bot settings.
+type Config struct {
+	Token    string
+	ChatID   int64   // staff chat that receives alerts
+	StaffIDs []int64 // Telegram users allowed to act on bookings
+	APIURL   string
+}
+
+var (
+	bot      *Client
+	chatID   int64
+	staffIDs map[int64]bool
+)
+
+// Init sets up the bot. Call once at startup.
+func Init(cfg Config) {
+	bot = NewClient(cfg.Token, cfg.APIURL)
+	chatID = cfg.ChatID
+	staffIDs = make(map[int64]bool, len(cfg.StaffIDs))
+	for _, id := range cfg.StaffIDs {
+		staffIDs[id] = true
+	}
+	log.Printf("Telegram bot initialized: staff chat %d, %d staff member(s)", chatID, len(staffIDs))
+}
+
+// IsConfigured returns true if the bot has been initialized with a chat to post to.
+func IsConfigured() bool {
+	return bot != nil && chatID != 0
+}
+
+// Bot returns the shared client (nil if not initialized).
+func Bot() *Client {
+	return bot
+}
+
+// IsStaff reports whether a Telegram user may confirm/cancel bookings.
+func IsStaff(userID int64) bool {
+	return staffIDs[userID]
+}
+
+// Booking actions carried in inline-button callback data: "booking:<action>:<id>".
+const (
+	ActionConfirm = "confirm"
+	ActionCancel  = "cancel"
+)
+
+// BookingCallbackData builds the callback payload for a booking button.
+func BookingCallbackData(action string, bookingID uint) string {
+	return fmt.Sprintf("booking:%s:%d", action, bookingID)
+}
+
+// ParseBookingCallback is the inverse of BookingCallbackData.
+func ParseBookingCallback(data string) (action string, bookingID uint, ok bool) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 || parts[0] != "booking" {
+		return "", 0, false
+	}
+	if parts[1] != ActionConfirm && parts[1] != ActionCancel {
+		return "", 0, false
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil || id == 0 {
+		return "", 0, false
+	}
+	return parts[1], uint(id), true
+}
+
+// bookingKeyboard is the confirm/cancel button row attached to pending bookings.
+func bookingKeyboard(bookingID uint) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: "✅ Підтвердити", CallbackData: BookingCallbackData(ActionConfirm, bookingID)},
+			{Text: "❌ Скасувати", CallbackData: BookingCallbackData(ActionCancel, bookingID)},
+		}},
+	}
+}
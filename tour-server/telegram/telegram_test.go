@@ -0,0 +1,126 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"tour-server/telegram/telegramtest"
+)
+
+func TestParseBookingCallback(t *testing.T) {
+	tests := []struct {
+		data       string
+		wantAction string
+		wantID     uint
+		wantOK     bool
+	}{
+		{BookingCallbackData(ActionConfirm, 42), ActionConfirm, 42, true},
+		{BookingCallbackData(ActionCancel, 7), ActionCancel, 7, true},
+		{"booking:delete:7", "", 0, false},
+		{"booking:confirm:0", "", 0, false},
+		{"booking:confirm:abc", "", 0, false},
+		{"tour:confirm:7", "", 0, false},
+		{"", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		action, id, ok := ParseBookingCallback(tt.data)
+		if action != tt.wantAction || id != tt.wantID || ok != tt.wantOK {
+			t.Errorf("ParseBookingCallback(%q) = (%q, %d, %v), want (%q, %d, %v)",
+				tt.data, action, id, ok, tt.wantAction, tt.wantID, tt.wantOK)
+		}
+	}
+}
+
+func TestAlertTextEscapesCustomerInput(t *testing.T) {
+	text := AlertText(EventNewBooking, BookingAlert{
+		BookingID:     5,
+		TourTitle:     "Карпати",
+		DateFrom:      time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		CustomerName:  "<b>Іван</b> & Co",
+		CustomerPhone: "+380951234567",
+		Seats:         2,
+		TotalPrice:    1500,
+		Status:        "pending",
+		IsGuest:       true,
+	})
+
+	if strings.Contains(text, "<b>Іван</b>") {
+		t.Errorf("customer name not escaped: %s", text)
+	}
+	for _, want := range []string{"#5", "Карпати", "01.07.2026", "&lt;b&gt;Іван&lt;/b&gt; &amp; Co", "гість", "1500.00"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("alert text missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestClientSendMessage(t *testing.T) {
+	srv := telegramtest.NewServer()
+	defer srv.Close()
+
+	c := NewClient("TEST", srv.URL)
+	_, err := c.SendMessage(context.Background(), -100, "hi", bookingKeyboard(9))
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	calls := srv.CallsTo("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	p := calls[0].Params
+	if p["chat_id"] != float64(-100) || p["text"] != "hi" || p["parse_mode"] != "HTML" {
+		t.Errorf("unexpected params: %v", p)
+	}
+	markup, _ := p["reply_markup"].(map[string]interface{})
+	rows, _ := markup["inline_keyboard"].([]interface{})
+	if len(rows) != 1 {
+		t.Fatalf("expected one row of buttons, got %v", p["reply_markup"])
+	}
+	row := rows[0].([]interface{})
+	if data := row[0].(map[string]interface{})["callback_data"]; data != "booking:confirm:9" {
+		t.Errorf("confirm button callback_data = %v", data)
+	}
+}
+
+func TestClientReportsAPIError(t *testing.T) {
+	srv := telegramtest.NewServer()
+	defer srv.Close()
+
+	// Wrong path → stand-in answers ok:false like the real API does.
+	c := NewClient("TEST", srv.URL+"/nope")
+	if err := c.DeleteWebhook(context.Background()); err == nil {
+		t.Fatal("expected error for ok:false response")
+	}
+}
+
+func TestClientGetUpdates(t *testing.T) {
+	srv := telegramtest.NewServer()
+	defer srv.Close()
+	srv.SetResult("getUpdates", []map[string]interface{}{{
+		"update_id": 11,
+		"callback_query": map[string]interface{}{
+			"id":   "cb1",
+			"from": map[string]interface{}{"id": 123, "username": "ops"},
+			"data": "booking:cancel:3",
+		},
+	}})
+
+	c := NewClient("TEST", srv.URL)
+	updates, err := c.GetUpdates(context.Background(), 10, 0)
+	if err != nil {
+		t.Fatalf("GetUpdates: %v", err)
+	}
+	if len(updates) != 1 || updates[0].UpdateID != 11 || updates[0].CallbackQuery == nil {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+	if cq := updates[0].CallbackQuery; cq.From.ID != 123 || cq.Data != "booking:cancel:3" {
+		t.Errorf("unexpected callback: %+v", cq)
+	}
+	if got := srv.CallsTo("getUpdates")[0].Params["offset"]; got != float64(10) {
+		t.Errorf("offset = %v, want 10", got)
+	}
+}
@@ -0,0 +1,92 @@
+// Package telegramtest provides a local stand-in for the Telegram Bot API,
+// so bot code can be tested without network access or a real bot token.
+package telegramtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Call is one Bot API request received by the server.
+type Call struct {
+	Method string
+	Params map[string]interface{}
+}
+
+// Server records every Bot API call and answers {"ok":true}. Results for
+// specific methods can be preset with SetResult.
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	calls   []Call
+	results map[string]interface{}
+}
+
+// NewServer starts a stand-in Bot API. Close it when done.
+func NewServer() *Server {
+	s := &Server{results: map[string]interface{}{
+		"sendMessage": map[string]interface{}{
+			"message_id": 1,
+			"chat":       map[string]interface{}{"id": 0},
+		},
+		"getUpdates": []interface{}{},
+	}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetResult sets the "result" payload returned for method.
+func (s *Server) SetResult(method string, result interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[method] = result
+}
+
+// Calls returns a copy of the calls received so far.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Call, len(s.calls))
+	copy(out, s.calls)
+	return out
+}
+
+// CallsTo returns the calls made to one method.
+func (s *Server) CallsTo(method string) []Call {
+	var out []Call
+	for _, c := range s.Calls() {
+		if c.Method == method {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	// Path is /bot<token>/<method>
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "bot") {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "description": "Not Found"})
+		return
+	}
+	method := parts[1]
+
+	params := map[string]interface{}{}
+	json.NewDecoder(r.Body).Decode(&params)
+
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Method: method, Params: params})
+	result, ok := s.results[method]
+	s.mu.Unlock()
+	if !ok {
+		result = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": result})
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"tour-server/tour/dto"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Departure statuses shown to customers.
+const (
+	DepartureAvailable = "available"
+	DepartureFewLeft   = "few_left"
+	DepartureSoldOut   = "sold_out"
+)
+
+// fewSeatsLeft is the threshold for the "few seats left" status.
+const fewSeatsLeft = 3
+
+// maxDepartureRange bounds the ?from=&to= window.
+const maxDepartureRange = 2 * 366 * 24 * time.Hour
+
+// GetTourDepartures returns every upcoming departure of a tour between
+// ?from= and ?to= (YYYY-MM-DD, default: today and one year ahead) with its
+// seats left, price per person and status.
+func GetTourDepartures(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid tour ID",
+			})
+		}
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		from, to := today, today.AddDate(1, 0, 0)
+		if v := c.QueryParam("from"); v != "" {
+			if from, err = time.Parse("2006-01-02", v); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "from must be YYYY-MM-DD",
+				})
+			}
+		}
+		if v := c.QueryParam("to"); v != "" {
+			if to, err = time.Parse("2006-01-02", v); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "to must be YYYY-MM-DD",
+				})
+			}
+		}
+		if to.Before(from) || to.Sub(from) > maxDepartureRange {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "to must be after from and at most two years later",
+			})
+		}
+		// Departures that already left can't be booked.
+		if from.Before(today) {
+			from = today
+		}
+
+		if !tourExists(db, tourID) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tour not found",
+			})
+		}
+
+		departures, err := loadDepartures(db, tourID, from, to)
+		if err != nil {
+			log.Printf("Failed to fetch departures: %v\n", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch departures",
+			})
+		}
+
+		return c.JSON(http.StatusOK, departures)
+	}
+}
+
+// GetTourCalendar returns a Monday-first month grid for ?month=YYYY-MM
+// (default: current month) with the departures starting on each day —
+// the data behind the date picker on the tour page.
+func GetTourCalendar(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid tour ID",
+			})
+		}
+
+		now := time.Now().UTC()
+		month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if v := c.QueryParam("month"); v != "" {
+			if month, err = time.Parse("2006-01", v); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "month must be YYYY-MM",
+				})
+			}
+		}
+
+		if !tourExists(db, tourID) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tour not found",
+			})
+		}
+
+		gridStart, gridEnd := calendarBounds(month)
+		from := gridStart
+		if today := now.Truncate(24 * time.Hour); from.Before(today) {
+			from = today
+		}
+
+		var departures []dto.TourDeparture
+		if !gridEnd.Before(from) {
+			departures, err = loadDepartures(db, tourID, from, gridEnd)
+			if err != nil {
+				log.Printf("Failed to fetch departures: %v\n", err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to fetch departures",
+				})
+			}
+		}
+
+		return c.JSON(http.StatusOK, buildCalendar(uint(tourID), month, departures))
+	}
+}
+
+func tourExists(db *gorm.DB, tourID int) bool {
+	var count int64
+	db.Table("tours").Where("id = ?", tourID).Count(&count)
+	return count > 0
+}
+
+// loadDepartures reads the tour's open departures starting between from
+// and to (inclusive days).
+func loadDepartures(db *gorm.DB, tourID int, from, to time.Time) ([]dto.TourDeparture, error) {
+	departures := []dto.TourDeparture{}
+	err := db.Raw(`
+		SELECT td.id, td.date_from, td.date_to,
+			EXTRACT(DAY FROM (td.date_to - td.date_from)) AS duration,
+			fl.name AS from_location, tl.name AS to_location,
+			COALESCE(td.total_seats, t.total_seats) AS total_seats,
+			COALESCE(ts.available_seats, 0) AS available_seats,
+			t.price, td.min_participants, td.is_guaranteed
+		FROM tour_dates td
+		JOIN tours t ON td.tour_id = t.id
+		JOIN locations fl ON td.from_location_id = fl.id
+		JOIN locations tl ON td.to_location_id = tl.id
+		LEFT JOIN tour_seats ts ON ts.tour_date_id = td.id
+		WHERE td.tour_id = ? AND NOT td.is_retired
+			AND td.date_from >= ? AND td.date_from < ?
+		ORDER BY td.date_from
+	`, tourID, from, to.AddDate(0, 0, 1)).Scan(&departures).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range departures {
+		departures[i].Status = departureStatus(departures[i].AvailableSeats)
+	}
+	return departures, nil
+}
+
+func departureStatus(seatsLeft uint) string {
+	switch {
+	case seatsLeft == 0:
+		return DepartureSoldOut
+	case seatsLeft <= fewSeatsLeft:
+		return DepartureFewLeft
+	default:
+		return DepartureAvailable
+	}
+}
+
+// calendarBounds returns the Monday on or before the 1st of month and the
+// Sunday on or after its last day.
+func calendarBounds(month time.Time) (time.Time, time.Time) {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	start := first.AddDate(0, 0, -((int(first.Weekday()) + 6) % 7))
+	end := last.AddDate(0, 0, (7-int(last.Weekday()))%7)
+	return start, end
+}
+
+func buildCalendar(tourID uint, month time.Time, departures []dto.TourDeparture) dto.TourCalendar {
+	byDay := map[string][]dto.TourDeparture{}
+	for _, d := range departures {
+		key := d.DateFrom.Format("2006-01-02")
+		byDay[key] = append(byDay[key], d)
+	}
+
+	cal := dto.TourCalendar{
+		TourID:    tourID,
+		Month:     month.Format("2006-01"),
+		PrevMonth: month.AddDate(0, -1, 0).Format("2006-01"),
+		NextMonth: month.AddDate(0, 1, 0).Format("2006-01"),
+	}
+
+	start, end := calendarBounds(month)
+	var week []dto.CalendarDay
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		day := dto.CalendarDay{
+			Date:       key,
+			InMonth:    d.Month() == month.Month(),
+			Departures: byDay[key],
+		}
+		if day.Departures == nil {
+			day.Departures = []dto.TourDeparture{}
+		}
+		for _, dep := range day.Departures {
+			if dep.AvailableSeats == 0 {
+				continue
+			}
+			if day.MinPrice == nil || dep.Price < *day.MinPrice {
+				p := dep.Price
+				day.MinPrice = &p
+			}
+		}
+
+		week = append(week, day)
+		if len(week) == 7 {
+			cal.Weeks = append(cal.Weeks, week)
+			week = nil
+		}
+	}
+	return cal
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"tour-server/tour/dto"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestCalendarBounds(t *testing.T) {
+	// July 2026 starts on a Wednesday and ends on a Friday.
+	start, end := calendarBounds(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC))
+	if got := start.Format("2006-01-02"); got != "2026-06-29" {
+		t.Errorf("grid start = %s, want 2026-06-29", got)
+	}
+	if got := end.Format("2006-01-02"); got != "2026-08-02" {
+		t.Errorf("grid end = %s, want 2026-08-02", got)
+	}
+}
+
+func TestBuildCalendar(t *testing.T) {
+	month := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	deps := []dto.TourDeparture{
+		{ID: 1, DateFrom: time.Date(2026, 7, 4, 0, 0, 0, 0, time.UTC), AvailableSeats: 5, Price: 1200},
+		{ID: 2, DateFrom: time.Date(2026, 7, 4, 0, 0, 0, 0, time.UTC), AvailableSeats: 0, Price: 900},
+	}
+
+	cal := buildCalendar(7, month, deps)
+	if len(cal.Weeks) != 5 {
+		t.Fatalf("expected 5 weeks, got %d", len(cal.Weeks))
+	}
+	for _, w := range cal.Weeks {
+		if len(w) != 7 {
+			t.Fatalf("expected 7 days per week, got %d", len(w))
+		}
+	}
+	if cal.PrevMonth != "2026-06" || cal.NextMonth != "2026-08" {
+		t.Errorf("unexpected prev/next: %s/%s", cal.PrevMonth, cal.NextMonth)
+	}
+	if cal.Weeks[0][0].InMonth {
+		t.Error("2026-06-29 should be outside the month")
+	}
+
+	sat := cal.Weeks[0][5]
+	if sat.Date != "2026-07-04" || len(sat.Departures) != 2 {
+		t.Fatalf("expected two departures on 2026-07-04, got %+v", sat)
+	}
+	// Sold-out departures don't set the "from" price.
+	if sat.MinPrice == nil || *sat.MinPrice != 1200 {
+		t.Errorf("expected min price 1200, got %v", sat.MinPrice)
+	}
+}
+
+func TestDepartureStatus(t *testing.T) {
+	cases := map[uint]string{0: DepartureSoldOut, 1: DepartureFewLeft, 3: DepartureFewLeft, 4: DepartureAvailable}
+	for seats, want := range cases {
+		if got := departureStatus(seats); got != want {
+			t.Errorf("departureStatus(%d) = %s, want %s", seats, got, want)
+		}
+	}
+}
+
+func TestGetTourDepartures_BadRange(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/tours/1/departures?from=2026-08-01&to=2026-07-01", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := GetTourDepartures(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestGetTourCalendar_BadMonth(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/tours/1/calendar?month=07-2026", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := GetTourCalendar(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"net/http"
 	"tour-server/tour/dto"
+	"tour-server/touritinerary"
+	"tour-server/tourtaxonomy"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -28,11 +30,14 @@ func GetTourById(db *gorm.DB) echo.HandlerFunc {
                 tour_dates.date_from, tour_dates.date_to,
                 EXTRACT(DAY FROM (tour_dates.date_to - tour_dates.date_from)) AS duration,
                 tours.total_seats,
-                COALESCE(tour_seats.available_seats, tours.total_seats) AS available_seats`).
-			Joins("JOIN statuses ON tours.status_id = statuses.id").                  // Join for tour status information
-			Joins("LEFT JOIN tour_dates ON tours.id = tour_dates.tour_id").           // Left join to include tours without dates
-			Joins("LEFT JOIN tour_seats ON tour_dates.id = tour_seats.tour_date_id"). // Left join to get seat availability
+                COALESCE(tour_seats.available_seats, tours.total_seats) AS available_seats,
+                tour_dates.min_participants,
+                COALESCE(tour_dates.is_guaranteed, FALSE) AS is_guaranteed`).
+			Joins("JOIN statuses ON tours.status_id = statuses.id").                                      // Join for tour status information
+			Joins("LEFT JOIN tour_dates ON tours.id = tour_dates.tour_id AND NOT tour_dates.is_retired"). // Left join to include tours without dates
+			Joins("LEFT JOIN tour_seats ON tour_dates.id = tour_seats.tour_date_id").                     // Left join to get seat availability
 			Where("tours.id = ?", id).
+			Order("tour_dates.date_from < NOW(), tour_dates.date_from"). // Next upcoming departure first; all of them: /tours/:id/departures
 			Scan(&tour).Error
 
 		if err != nil {
@@ -48,6 +53,18 @@ func GetTourById(db *gorm.DB) echo.HandlerFunc {
 			})
 		}
 
+		// Day-by-day programme; an empty list if the tour has none yet
+		tour.Itinerary, err = touritinerary.Load(db, tour.ID)
+		if err != nil {
+			log.Printf("Failed to fetch itinerary: %v\n", err)
+			tour.Itinerary = []touritinerary.Day{}
+		}
+
+		tour.Categories, tour.Tags, err = tourtaxonomy.LoadForTour(db, tour.ID)
+		if err != nil {
+			log.Printf("Failed to fetch tour taxonomy: %v\n", err)
+		}
+
 		return c.JSON(http.StatusOK, tour)
 	}
 }
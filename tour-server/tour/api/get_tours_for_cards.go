@@ -22,17 +22,17 @@ func GetToursForCards(db *gorm.DB) echo.HandlerFunc {
 		}
 
 		var tours []TourCardResponse
-		err := db.Table("tours").
+		// Precomputed card data, see searchindex/migration.sql
+		err := db.Table("tour_search_index").
 			Select(`
-				tours.id,
-				tours.title,
-				tours.price,
-				tours.rating,
-				COALESCE(tour_card_images.image_src, 'no-image.jpg') AS image_src
+				tour_id AS id,
+				title,
+				price,
+				rating,
+				COALESCE(image_src, 'no-image.jpg') AS image_src
 			`).
-			Joins("LEFT JOIN tour_card_images ON tours.id = tour_card_images.tour_id").
-			Where("tours.status_id = (SELECT id FROM statuses WHERE name = 'active')").
-			Order("tours.id ASC").
+			Where("status_id = (SELECT id FROM statuses WHERE name = 'active')").
+			Order("tour_id ASC").
 			Find(&tours).Error
 
 		if err != nil {
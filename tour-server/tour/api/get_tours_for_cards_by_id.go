@@ -31,10 +31,9 @@ func GetToursForCardsByID(db *gorm.DB) echo.HandlerFunc {
 		}
 
 		var toursWithImages []dto.TourCard
-		err := db.Table("tours").
-			Select("tours.id, tours.title, tours.price, tours.rating, COALESCE(tour_card_images.image_src, 'no-image.jpg') AS image_src").
-			Joins("LEFT JOIN tour_card_images ON tours.id = tour_card_images.tour_id").
-			Where("tours.id IN ?", ids).
+		err := db.Table("tour_search_index").
+			Select("tour_id AS id, title, price, rating, COALESCE(image_src, 'no-image.jpg') AS image_src").
+			Where("tour_id IN ?", ids).
 			Find(&toursWithImages).Error
 
 		log.Printf("SQL Query executed: %+v\n", db.Statement.SQL.String())
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strings"
 	"tour-server/tour/dto"
 
 	"github.com/labstack/echo/v4"
@@ -17,13 +18,30 @@ func GetToursForSwiper(db *gorm.DB) echo.HandlerFunc {
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Database connection is nil"})
 		}
 
+		// ?collection=slug shows that collection; otherwise the collection an
+		// admin marked show_in_swiper, falling back to all active tours
+		var collectionID uint
+		if slug := strings.ToLower(c.QueryParam("collection")); slug != "" {
+			db.Raw("SELECT id FROM collections WHERE slug = ? AND is_active", slug).Scan(&collectionID)
+			if collectionID == 0 {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "Collection not found"})
+			}
+		} else {
+			db.Raw("SELECT id FROM collections WHERE show_in_swiper AND is_active").Scan(&collectionID)
+		}
+
 		// Query the database for all tours with relevant swiper display information
+		query := db.Table("tours").
+		Select("tours.id, tours.title, tours.description, tours.call_to_action, COALESCE(tsi.image_src, 'no-image.jpg') AS image_src").
+		Joins("LEFT JOIN tour_search_index tsi ON tsi.tour_id = tours.id").
+		Where("tours.status_id = (SELECT id FROM statuses WHERE name = 'active')")
+		if collectionID != 0 {
+			query = query.Joins("JOIN collection_tours ON collection_tours.tour_id = tours.id AND collection_tours.collection_id = ?", collectionID).
+				Order("collection_tours.position, tours.id")
+		}
+
 		var TourSwiper []dto.TourSwiper
-		err := db.Table("tours").
-		Select("tours.id, tours.title, tours.description, tours.call_to_action, COALESCE(tour_card_images.image_src, 'no-image.jpg') AS image_src").
-		Joins("LEFT JOIN tour_card_images ON tours.id = tour_card_images.tour_id").
-		Where("tours.status_id = (SELECT id FROM statuses WHERE name = 'active')").
-		Find(&TourSwiper).Error
+		err := query.Find(&TourSwiper).Error
 
 		// Handle database errors
 		if err != nil {
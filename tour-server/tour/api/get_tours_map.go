@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"tour-server/geo"
+	"tour-server/tour/dto"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetToursMap returns active tours as a GeoJSON FeatureCollection. Each tour
+// is placed at the destination of its next departure (or its latest one if
+// none are upcoming); tours whose destination has no coordinates are left
+// out. ?bbox=minLng,minLat,maxLng,maxLat limits it to the visible map area.
+func GetToursMap(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		where := "TRUE"
+		var args []interface{}
+		if v := c.QueryParam("bbox"); v != "" {
+			bbox, ok := geo.ParseBBox(v)
+			if !ok {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "bbox must be minLng,minLat,maxLng,maxLat",
+				})
+			}
+			where, args = bbox.Where("p.latitude", "p.longitude")
+		}
+
+		var rows []struct {
+			ID        uint    `gorm:"column:id"`
+			Title     string  `gorm:"column:title"`
+			Price     float64 `gorm:"column:price"`
+			Rating    float64 `gorm:"column:rating"`
+			ImageSrc  string  `gorm:"column:image_src"`
+			Location  string  `gorm:"column:location"`
+			Latitude  float64 `gorm:"column:latitude"`
+			Longitude float64 `gorm:"column:longitude"`
+		}
+		err := db.Raw(`
+			SELECT * FROM (
+				SELECT DISTINCT ON (t.id)
+					t.id, t.title, t.price, COALESCE(t.rating, 0) AS rating,
+					COALESCE(tci.image_src, '/static/images/no-image.jpg') AS image_src,
+					CONCAT(ci.name, ', ', co.name) AS location,
+					ci.latitude, ci.longitude
+				FROM tours t
+				JOIN tour_dates td ON td.tour_id = t.id AND NOT td.is_retired
+				JOIN cities ci ON ci.id = td.to_location_id
+				JOIN countries co ON co.id = ci.country_id
+				LEFT JOIN tour_card_images tci ON tci.tour_id = t.id
+				WHERE t.status_id = (SELECT id FROM statuses WHERE name = 'active')
+					AND ci.latitude IS NOT NULL AND ci.longitude IS NOT NULL
+				ORDER BY t.id, td.date_from < NOW(), ABS(EXTRACT(EPOCH FROM (td.date_from - NOW())))
+			) p
+			WHERE `+where+`
+			ORDER BY p.id
+		`, args...).Scan(&rows).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch tours",
+			})
+		}
+
+		fc := dto.TourFeatureCollection{Type: "FeatureCollection", Features: make([]dto.TourFeature, 0, len(rows))}
+		for _, r := range rows {
+			fc.Features = append(fc.Features, dto.TourFeature{
+				Type:     "Feature",
+				ID:       r.ID,
+				Geometry: dto.PointGeometry{Type: "Point", Coordinates: [2]float64{r.Longitude, r.Latitude}},
+				Properties: dto.TourFeatureProperties{
+					Title:    r.Title,
+					Price:    r.Price,
+					Rating:   r.Rating,
+					ImageSrc: r.ImageSrc,
+					Location: r.Location,
+				},
+			})
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, "application/geo+json")
+		return c.JSON(http.StatusOK, fc)
+	}
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// TourDeparture is one bookable (or sold-out) departure of a tour.
+type TourDeparture struct {
+	ID              uint      `json:"id" gorm:"column:id"`
+	DateFrom        time.Time `json:"dateFrom" gorm:"column:date_from"`
+	DateTo          time.Time `json:"dateTo" gorm:"column:date_to"`
+	Duration        uint      `json:"duration" gorm:"column:duration"`
+	FromLocation    string    `json:"fromLocation" gorm:"column:from_location"`
+	ToLocation      string    `json:"toLocation" gorm:"column:to_location"`
+	TotalSeats      uint      `json:"totalSeats" gorm:"column:total_seats"`
+	AvailableSeats  uint      `json:"availableSeats" gorm:"column:available_seats"`
+	Price           float64   `json:"price" gorm:"column:price"`
+	Status          string    `json:"status" gorm:"-"`
+	MinParticipants *uint     `json:"minParticipants" gorm:"column:min_participants"`
+	IsGuaranteed    bool      `json:"isGuaranteed" gorm:"column:is_guaranteed"`
+}
+
+// CalendarDay is one cell of the month grid.
+type CalendarDay struct {
+	Date       string          `json:"date"` // YYYY-MM-DD
+	InMonth    bool            `json:"inMonth"`
+	MinPrice   *float64        `json:"minPrice"`
+	Departures []TourDeparture `json:"departures"`
+}
+
+// TourCalendar is a Monday-first month grid of departures.
+type TourCalendar struct {
+	TourID    uint            `json:"tourId"`
+	Month     string          `json:"month"` // YYYY-MM
+	PrevMonth string          `json:"prevMonth"`
+	NextMonth string          `json:"nextMonth"`
+	Weeks     [][]CalendarDay `json:"weeks"`
+}
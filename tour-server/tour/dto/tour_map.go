@@ -0,0 +1,28 @@
+package dto
+
+// GeoJSON FeatureCollection of tours for the map view (RFC 7946).
+type TourFeatureCollection struct {
+	Type     string        `json:"type"` // "FeatureCollection"
+	Features []TourFeature `json:"features"`
+}
+
+type TourFeature struct {
+	Type       string                `json:"type"` // "Feature"
+	ID         uint                  `json:"id"`
+	Geometry   PointGeometry         `json:"geometry"`
+	Properties TourFeatureProperties `json:"properties"`
+}
+
+// PointGeometry coordinates are [longitude, latitude].
+type PointGeometry struct {
+	Type        string     `json:"type"` // "Point"
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type TourFeatureProperties struct {
+	Title    string  `json:"title"`
+	Price    float64 `json:"price"`
+	Rating   float64 `json:"rating"`
+	ImageSrc string  `json:"imageSrc"`
+	Location string  `json:"location"`
+}
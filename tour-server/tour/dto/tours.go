@@ -2,6 +2,8 @@ package dto
 
 import (
 	"time"
+	"tour-server/touritinerary"
+	"tour-server/tourtaxonomy"
 )
 
 type TourDTO struct {
@@ -19,4 +21,10 @@ type TourDTO struct {
 	DetailedDescription string    `json:"detailedDescription"`
 	TotalSeats          uint      `json:"totalSeats"`
 	AvailableSeats      uint      `json:"availableSeats"`
+	MinParticipants     *uint     `json:"minParticipants"`
+	IsGuaranteed        bool      `json:"isGuaranteed"`
+
+	Itinerary  []touritinerary.Day `json:"itinerary" gorm:"-"`
+	Categories []tourtaxonomy.Term `json:"categories" gorm:"-"`
+	Tags       []tourtaxonomy.Term `json:"tags" gorm:"-"`
 }
@@ -10,4 +10,5 @@ type Tour struct {
 	StatusID            uint    `json:"statusId" gorm:"not null"`
 	DetailedDescription string  `json:"detailedDescription"`
 	TotalSeats          int     `json:"total_seats"`
+	TransferCutoffHours int     `json:"transfer_cutoff_hours" gorm:"default:72"`
 }
@@ -3,8 +3,11 @@ package api
 import (
 	"log"
 	"net/http"
+	"tour-server/middleware"
+	"tour-server/moderation"
 
 	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -26,6 +29,7 @@ func CreateComment(db *gorm.DB) echo.HandlerFunc {
 		if req.TourID == 0 {
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "tour_id is required"})
 		}
+		req.Comment = middleware.SanitizeComment(req.Comment, 1000)
 		if len([]rune(req.Comment)) < 1 {
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Коментар порожній"})
 		}
@@ -64,21 +68,31 @@ func CreateComment(db *gorm.DB) echo.HandlerFunc {
 			`, req.TourID, uid).Scan(&count)
 			isVerifiedBuyer = count > 0
 		} else {
-			name := req.GuestName
+			name := middleware.SanitizeName(req.GuestName, 50)
 			if name == "" {
 				name = "Гість"
 			}
 			guestName = &name
 		}
 
+		// Pre-screening decides whether the comment goes live now or
+		// waits in the moderation queue
+		status, flags := moderation.Screen(db, moderation.Submission{
+			Text:          req.Comment,
+			UserID:        userID,
+			VerifiedBuyer: isVerifiedBuyer,
+		}, 0)
+
 		type insertRow struct {
-			TourID          uint    `gorm:"column:tour_id"`
-			ParentID        *uint   `gorm:"column:parent_id"`
-			UserID          *uint   `gorm:"column:user_id"`
-			GuestName       *string `gorm:"column:guest_name"`
-			Comment         string  `gorm:"column:comment"`
-			Rating          *int    `gorm:"column:rating"`
-			IsVerifiedBuyer bool    `gorm:"column:is_verified_buyer"`
+			TourID          uint           `gorm:"column:tour_id"`
+			ParentID        *uint          `gorm:"column:parent_id"`
+			UserID          *uint          `gorm:"column:user_id"`
+			GuestName       *string        `gorm:"column:guest_name"`
+			Comment         string         `gorm:"column:comment"`
+			Rating          *int           `gorm:"column:rating"`
+			IsVerifiedBuyer bool           `gorm:"column:is_verified_buyer"`
+			Status          string         `gorm:"column:status"`
+			ModerationFlags pq.StringArray `gorm:"column:moderation_flags;type:text[]"`
 		}
 
 		row := insertRow{
@@ -89,6 +103,8 @@ func CreateComment(db *gorm.DB) echo.HandlerFunc {
 			Comment:         req.Comment,
 			Rating:          req.Rating,
 			IsVerifiedBuyer: isVerifiedBuyer,
+			Status:          status,
+			ModerationFlags: flags,
 		}
 
 		if err := db.Table("tour_reviews").Create(&row).Error; err != nil {
@@ -96,8 +112,13 @@ func CreateComment(db *gorm.DB) echo.HandlerFunc {
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create comment"})
 		}
 
+		message := "Comment created"
+		if status == moderation.StatusPending {
+			message = "Коментар надіслано на модерацію"
+		}
 		return c.JSON(http.StatusCreated, map[string]interface{}{
-			"message":           "Comment created",
+			"message":           message,
+			"status":            status,
 			"is_verified_buyer": isVerifiedBuyer,
 			"is_guest":          isGuest,
 		})
@@ -18,6 +18,7 @@ type ReplyRow struct {
 	IsGuest         bool      `json:"is_guest"`
 	IsOwner         bool      `json:"is_owner"`
 	IsVerifiedBuyer bool      `json:"is_verified_buyer"`
+	Status          string    `json:"status"` // only the author sees anything but approved
 	LikesCount      int       `json:"likes_count"`
 	DislikesCount   int       `json:"dislikes_count"`
 	LikedByMe       bool      `json:"liked_by_me"`
@@ -36,6 +37,7 @@ type CommentRow struct {
 	Comment         string     `json:"comment"`
 	Rating          *int       `json:"rating"`
 	IsVerifiedBuyer bool       `json:"is_verified_buyer"`
+	Status          string     `json:"status"` // only the author sees anything but approved
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 	IsOwner         bool       `json:"is_owner"`
@@ -67,8 +69,12 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 		}
 		guestToken := c.Request().Header.Get("X-Guest-Token")
 
+		// Readers see approved comments; authors also see their own pending
+		// and rejected ones
 		var total int64
-		db.Table("tour_reviews").Where("tour_id = ? AND parent_id IS NULL", tourID).Count(&total)
+		db.Table("tour_reviews").
+			Where("tour_id = ? AND parent_id IS NULL AND (status = 'approved' OR user_id = ?)", tourID, currentUserID).
+			Count(&total)
 
 		type rawRow struct {
 			ID              uint      `gorm:"column:id"`
@@ -80,6 +86,7 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 			Comment         string    `gorm:"column:comment"`
 			Rating          *int      `gorm:"column:rating"`
 			IsVerifiedBuyer bool      `gorm:"column:is_verified_buyer"`
+			Status          string    `gorm:"column:status"`
 			LikesCount      int       `gorm:"column:likes_count"`
 			DislikesCount   int       `gorm:"column:dislikes_count"`
 			LikedByMe       bool      `gorm:"column:liked_by_me"`
@@ -101,7 +108,7 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 				SELECT tr.id, tr.tour_id, tr.user_id,
 					tu.name AS user_name, tu.avatar_url AS user_avatar,
 					tr.guest_name, tr.comment, tr.rating,
-					tr.is_verified_buyer, tr.created_at, tr.updated_at,
+					tr.is_verified_buyer, tr.status, tr.created_at, tr.updated_at,
 					COALESCE((SELECT COUNT(*) FROM tour_review_likes l WHERE l.review_id = tr.id AND l.reaction_type = 'like'), 0) AS likes_count,
 					COALESCE((SELECT COUNT(*) FROM tour_review_likes l WHERE l.review_id = tr.id AND l.reaction_type = 'dislike'), 0) AS dislikes_count,
 					` + likedExpr + ` AS liked_by_me,
@@ -109,6 +116,7 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 				FROM tour_reviews tr
 				LEFT JOIN tour_users tu ON tr.user_id = tu.id
 				WHERE tr.tour_id = ? AND tr.parent_id IS NULL
+					AND (tr.status = 'approved' OR tr.user_id = ?)
 				ORDER BY tr.created_at DESC
 				LIMIT ? OFFSET ?`
 		}
@@ -117,7 +125,7 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 			return `
 				SELECT tr.id, tr.parent_id, tr.user_id,
 					tu.name AS user_name, tu.avatar_url AS user_avatar,
-					tr.guest_name, tr.comment, tr.is_verified_buyer,
+					tr.guest_name, tr.comment, tr.is_verified_buyer, tr.status,
 					tr.created_at, tr.updated_at,
 					COALESCE((SELECT COUNT(*) FROM tour_review_likes l WHERE l.review_id = tr.id AND l.reaction_type = 'like'), 0) AS likes_count,
 					COALESCE((SELECT COUNT(*) FROM tour_review_likes l WHERE l.review_id = tr.id AND l.reaction_type = 'dislike'), 0) AS dislikes_count,
@@ -126,6 +134,7 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 				FROM tour_reviews tr
 				LEFT JOIN tour_users tu ON tr.user_id = tu.id
 				WHERE tr.parent_id IN ?
+					AND (tr.status = 'approved' OR tr.user_id = ?)
 				ORDER BY tr.created_at ASC`
 		}
 
@@ -136,21 +145,21 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 			// Authenticated user — use parameterized user_id
 			likedExpr = "EXISTS (SELECT 1 FROM tour_review_likes l WHERE l.review_id = tr.id AND l.user_id = ? AND l.reaction_type = 'like')"
 			dislikedExpr = "EXISTS (SELECT 1 FROM tour_review_likes l WHERE l.review_id = tr.id AND l.user_id = ? AND l.reaction_type = 'dislike')"
-			// Args: liked_user_id, disliked_user_id, tour_id, limit, offset
-			mainArgs = []interface{}{currentUserID, currentUserID, tourID, limit, offset}
+			// Args: liked_user_id, disliked_user_id, tour_id, owner_id, limit, offset
+			mainArgs = []interface{}{currentUserID, currentUserID, tourID, currentUserID, limit, offset}
 			replyIdentityArgs = []interface{}{currentUserID, currentUserID}
 		} else if guestToken != "" {
 			// Guest — use parameterized guest_token
 			likedExpr = "EXISTS (SELECT 1 FROM tour_review_likes l WHERE l.review_id = tr.id AND l.guest_token = ? AND l.reaction_type = 'like')"
 			dislikedExpr = "EXISTS (SELECT 1 FROM tour_review_likes l WHERE l.review_id = tr.id AND l.guest_token = ? AND l.reaction_type = 'dislike')"
-			// Args: liked_guest_token, disliked_guest_token, tour_id, limit, offset
-			mainArgs = []interface{}{guestToken, guestToken, tourID, limit, offset}
+			// Args: liked_guest_token, disliked_guest_token, tour_id, owner_id, limit, offset
+			mainArgs = []interface{}{guestToken, guestToken, tourID, currentUserID, limit, offset}
 			replyIdentityArgs = []interface{}{guestToken, guestToken}
 		} else {
 			// Anonymous — no identity, hardcode false
 			likedExpr = "false"
 			dislikedExpr = "false"
-			mainArgs = []interface{}{tourID, limit, offset}
+			mainArgs = []interface{}{tourID, currentUserID, limit, offset}
 			replyIdentityArgs = nil
 		}
 
@@ -170,12 +179,12 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 			parentIDs = append(parentIDs, r.ID)
 		}
 
-		// Reply args: identity params first, then parentIDs
+		// Reply args: identity params first, then parentIDs and owner_id
 		var replyArgs []interface{}
 		if replyIdentityArgs != nil {
-			replyArgs = append(replyIdentityArgs, parentIDs)
+			replyArgs = append(replyIdentityArgs, parentIDs, currentUserID)
 		} else {
-			replyArgs = []interface{}{parentIDs}
+			replyArgs = []interface{}{parentIDs, currentUserID}
 		}
 
 		type rawReply struct {
@@ -187,6 +196,7 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 			GuestName       *string   `gorm:"column:guest_name"`
 			Comment         string    `gorm:"column:comment"`
 			IsVerifiedBuyer bool      `gorm:"column:is_verified_buyer"`
+			Status          string    `gorm:"column:status"`
 			LikesCount      int       `gorm:"column:likes_count"`
 			DislikesCount   int       `gorm:"column:dislikes_count"`
 			LikedByMe       bool      `gorm:"column:liked_by_me"`
@@ -220,7 +230,7 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 			repliesByParent[r.ParentID] = append(repliesByParent[r.ParentID], ReplyRow{
 				ID: r.ID, UserID: r.UserID, UserName: name, UserAvatar: avatar,
 				Comment: r.Comment, IsGuest: isGuest, IsOwner: isOwner,
-				IsVerifiedBuyer: r.IsVerifiedBuyer,
+				IsVerifiedBuyer: r.IsVerifiedBuyer, Status: r.Status,
 				LikesCount: r.LikesCount, DislikesCount: r.DislikesCount,
 				LikedByMe: r.LikedByMe, DislikedByMe: r.DislikedByMe,
 				CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
@@ -239,7 +249,7 @@ func GetTourComments(db *gorm.DB) echo.HandlerFunc {
 			comments = append(comments, CommentRow{
 				ID: r.ID, TourID: r.TourID, UserID: r.UserID, UserName: name, UserAvatar: avatar,
 				GuestName: r.GuestName, Comment: r.Comment, Rating: r.Rating,
-				IsVerifiedBuyer: r.IsVerifiedBuyer, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+				IsVerifiedBuyer: r.IsVerifiedBuyer, Status: r.Status, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
 				IsOwner: isOwner, IsGuest: isGuest,
 				LikesCount: r.LikesCount, DislikesCount: r.DislikesCount,
 				LikedByMe: r.LikedByMe, DislikedByMe: r.DislikedByMe,
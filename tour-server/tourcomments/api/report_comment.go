@@ -0,0 +1,93 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"tour-server/middleware"
+	"tour-server/moderation"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ReportCommentRequest struct {
+	Reason  string `json:"reason"` // spam, abuse, hate, off_topic, misleading, other
+	Details string `json:"details,omitempty"`
+}
+
+// POST /tour-comments/:id/report
+// Body: { "reason": "spam", "details": "..." }
+// Users or guests (X-Guest-Token) can report a comment once each; enough
+// reports from signed-in users hide it until a moderator looks at it.
+func ReportComment(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		commentID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || commentID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+		}
+
+		var req ReportCommentRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		}
+		if !moderation.ValidReason(req.Reason) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Невідома причина скарги"})
+		}
+		req.Details = middleware.SanitizeComment(req.Details, 500)
+
+		var userID *uint
+		if uid, ok := c.Get("user_id").(uint); ok {
+			userID = &uid
+		}
+		guestToken := c.Request().Header.Get("X-Guest-Token")
+		if userID == nil && (guestToken == "" || len(guestToken) > 128) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Потрібен токен або авторизація"})
+		}
+
+		var comment struct {
+			ID     uint   `gorm:"column:id"`
+			UserID *uint  `gorm:"column:user_id"`
+			Status string `gorm:"column:status"`
+		}
+		db.Raw("SELECT id, user_id, status FROM tour_reviews WHERE id = ?", commentID).Scan(&comment)
+		if comment.ID == 0 || comment.Status == moderation.StatusRejected {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Коментар не знайдено"})
+		}
+		if userID != nil && comment.UserID != nil && *comment.UserID == *userID {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Не можна поскаржитися на власний коментар"})
+		}
+
+		var res *gorm.DB
+		if userID != nil {
+			res = db.Exec(`
+				INSERT INTO comment_reports (review_id, user_id, reason, details)
+				VALUES (?, ?, ?, NULLIF(?, ''))
+				ON CONFLICT (review_id, user_id) WHERE user_id IS NOT NULL DO NOTHING
+			`, commentID, *userID, req.Reason, req.Details)
+		} else {
+			res = db.Exec(`
+				INSERT INTO comment_reports (review_id, guest_token, reason, details)
+				VALUES (?, ?, ?, NULLIF(?, ''))
+				ON CONFLICT (review_id, guest_token) WHERE guest_token IS NOT NULL DO NOTHING
+			`, commentID, guestToken, req.Reason, req.Details)
+		}
+		if res.Error != nil {
+			log.Printf("Failed to save comment report: %v", res.Error)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to report comment"})
+		}
+		if res.RowsAffected == 0 {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "Ви вже поскаржилися на цей коментар"})
+		}
+
+		hidden, err := moderation.HideIfReported(db, uint(commentID))
+		if err != nil {
+			log.Printf("Failed to hide reported comment %d: %v", commentID, err)
+		}
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message": "Скаргу надіслано",
+			"hidden":  hidden,
+		})
+	}
+}
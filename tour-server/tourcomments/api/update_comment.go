@@ -3,6 +3,8 @@ package api
 import (
 	"log"
 	"net/http"
+	"tour-server/middleware"
+	"tour-server/moderation"
 	"tour-server/tourcomments/dto"
 	"tour-server/tourcomments/models"
 
@@ -42,9 +44,22 @@ func UpdateComment(db *gorm.DB) echo.HandlerFunc {
 			})
 		}
 
-		comment.Comment = req.Comment
+		comment.Comment = middleware.SanitizeComment(req.Comment, 1000)
 		comment.Rating = req.Rating
 
+		// Edits are screened again; see moderation.EditStatus.
+		var verified bool
+		db.Raw("SELECT is_verified_buyer FROM tour_reviews WHERE id = ?", comment.ID).Scan(&verified)
+		status, flags := moderation.Screen(db, moderation.Submission{
+			Text:          comment.Comment,
+			UserID:        &userID,
+			VerifiedBuyer: verified,
+		}, comment.ID)
+		if comment.Status == moderation.StatusRejected {
+			comment.RejectionReason = nil
+		}
+		comment.Status, comment.ModerationFlags = moderation.EditStatus(comment.Status, comment.ModerationFlags, status, flags)
+
 		if err := db.Save(&comment).Error; err != nil {
 			log.Printf("Failed to update comment: %v", err)
 			return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -64,6 +79,7 @@ func UpdateComment(db *gorm.DB) echo.HandlerFunc {
 			UserAvatar: comment.User.AvatarURL,
 			Comment:    comment.Comment,
 			Rating:     comment.Rating,
+			Status:     comment.Status,
 			CreatedAt:  comment.CreatedAt,
 			UpdatedAt:  comment.UpdatedAt,
 			IsOwner:    true,
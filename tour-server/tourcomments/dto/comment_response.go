@@ -10,6 +10,7 @@ type CommentResponse struct {
 	UserAvatar string   `json:"user_avatar,omitempty"`
 	Comment   string    `json:"comment"`
 	Rating    *int      `json:"rating,omitempty"`
+	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	IsOwner   bool      `json:"is_owner"`
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 	userModels "tour-server/tourusers/models"
+
+	"github.com/lib/pq"
 )
 
 type TourComment struct {
@@ -14,6 +16,11 @@ type TourComment struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
+	// Moderation: pending, approved or rejected (moderation package)
+	Status          string         `json:"status" gorm:"default:approved"`
+	ModerationFlags pq.StringArray `json:"-" gorm:"column:moderation_flags;type:text[]"`
+	RejectionReason *string        `json:"-" gorm:"column:rejection_reason"`
+
 	User userModels.TourUser `json:"user" gorm:"foreignKey:UserID;references:ID"`
 }
 
@@ -0,0 +1,19 @@
+-- Migration: per-departure capacity and retirement
+-- Lets admins edit single departures without recreating them (which broke
+-- bookings.tour_date_id). Capacity is backfilled from the live state —
+-- available seats plus seats held by active bookings — so nothing changes
+-- for existing dates.
+
+ALTER TABLE tour_dates
+    ADD COLUMN IF NOT EXISTS total_seats INTEGER,
+    ADD COLUMN IF NOT EXISTS is_retired  BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE tour_dates td
+SET total_seats = COALESCE(ts.available_seats, 0) + COALESCE((
+        SELECT SUM(b.seats) FROM bookings b
+        WHERE b.tour_date_id = td.id AND b.status <> 'cancelled'
+    ), 0)
+FROM tour_seats ts
+WHERE ts.tour_date_id = td.id AND td.total_seats IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tour_dates_tour_id ON tour_dates(tour_id);
@@ -0,0 +1,7 @@
+-- Migration: operator-cancelled departures
+-- A cancelled departure is also retired (is_retired) so it leaves the
+-- public site; cancelled_at/cancellation_reason record why and when.
+
+ALTER TABLE tour_dates
+    ADD COLUMN IF NOT EXISTS cancelled_at        TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS cancellation_reason TEXT;
@@ -0,0 +1,15 @@
+-- Migration: minimum participants and go/no-go decision
+-- min_participants NULL means the departure always runs. The decision is
+-- taken decision_days_before days before date_from: departures that still
+-- have fewer confirmed travellers are cancelled and refunded. Reaching the
+-- minimum earlier marks the departure guaranteed straight away.
+
+ALTER TABLE tour_dates
+    ADD COLUMN IF NOT EXISTS min_participants     INTEGER CHECK (min_participants > 0),
+    ADD COLUMN IF NOT EXISTS decision_days_before INTEGER NOT NULL DEFAULT 7 CHECK (decision_days_before >= 0),
+    ADD COLUMN IF NOT EXISTS is_guaranteed        BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS guaranteed_at        TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tour_dates_go_no_go
+    ON tour_dates(date_from)
+    WHERE min_participants IS NOT NULL AND NOT is_guaranteed AND cancelled_at IS NULL;
@@ -0,0 +1,30 @@
+-- Migration: recurring departure series
+-- A series stores the rule a batch of departures was generated from, so the
+-- remainder can be regenerated later. tour_dates.series_id links generated
+-- dates back to it; hand-made dates keep series_id NULL.
+-- Run after geo/migration.sql (the location columns reference cities).
+
+CREATE TABLE IF NOT EXISTS departure_series (
+    id                   SERIAL PRIMARY KEY,
+    tour_id              INTEGER NOT NULL REFERENCES tours(id) ON DELETE CASCADE,
+    rrule                TEXT NOT NULL,
+    season_start         DATE NOT NULL,
+    season_end           DATE NOT NULL,
+    duration_days        INTEGER NOT NULL CHECK (duration_days >= 0),
+    exceptions           DATE[] NOT NULL DEFAULT '{}',
+    from_location_id     INTEGER NOT NULL REFERENCES cities(id),
+    to_location_id       INTEGER NOT NULL REFERENCES cities(id),
+    total_seats          INTEGER,
+    min_participants     INTEGER,
+    decision_days_before INTEGER NOT NULL DEFAULT 7,
+    created_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (season_end >= season_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_departure_series_tour_id ON departure_series(tour_id);
+
+ALTER TABLE tour_dates
+    ADD COLUMN IF NOT EXISTS series_id INTEGER REFERENCES departure_series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tour_dates_series_id ON tour_dates(series_id);
@@ -13,6 +13,16 @@ type TourDate struct {
 	ToLocationID   uint      `json:"toLocationId" gorm:"not null"`
 	DateFrom       time.Time `json:"dateFrom" gorm:"not null"`
 	DateTo         time.Time `json:"dateTo" gorm:"not null"`
+	TotalSeats     *int      `json:"totalSeats"`
+	IsRetired      bool      `json:"isRetired" gorm:"not null;default:false"`
+
+	MinParticipants    *int       `json:"minParticipants"`
+	DecisionDaysBefore int        `json:"decisionDaysBefore" gorm:"not null;default:7"`
+	IsGuaranteed       bool       `json:"isGuaranteed" gorm:"not null;default:false"`
+	GuaranteedAt       *time.Time `json:"guaranteedAt,omitempty"`
+	CancelledAt        *time.Time `json:"cancelledAt,omitempty"`
+	SeriesID           *uint      `json:"seriesId,omitempty"`
+	CancellationReason string     `json:"cancellationReason,omitempty"`
 
 	Tour         tourModels.Tour         `json:"tour,omitempty" gorm:"foreignKey:TourID;references:ID"`
 	FromLocation locationModels.Location `json:"from_location,omitempty" gorm:"foreignKey:FromLocationID;references:ID"`
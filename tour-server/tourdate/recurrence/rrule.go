@@ -0,0 +1,231 @@
+// Package recurrence expands RRULE-style recurrence rules (a practical
+// subset of RFC 5545) into departure dates.
+//
+// Supported parts: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, BYMONTHDAY
+// (negative values count from the end of the month), COUNT and UNTIL.
+// Weeks start on Monday. All dates are calendar days in UTC.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ part of a rule.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// MaxOccurrences caps how many dates one rule may produce.
+const MaxOccurrences = 366
+
+// ErrTooMany is returned when a rule would exceed MaxOccurrences.
+var ErrTooMany = fmt.Errorf("recurrence: rule produces more than %d dates", MaxOccurrences)
+
+// Rule is a parsed recurrence rule.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int       // 0 = unlimited
+	Until      time.Time // zero = unlimited
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;BYDAY=SA" (an "RRULE:" prefix
+// is allowed).
+func Parse(s string) (Rule, error) {
+	r := Rule{Interval: 1}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return r, errors.New("recurrence: empty rule")
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return r, fmt.Errorf("recurrence: malformed part %q", part)
+		}
+		key, val := strings.ToUpper(strings.TrimSpace(kv[0])), strings.ToUpper(strings.TrimSpace(kv[1]))
+
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly:
+				r.Freq = Frequency(val)
+			default:
+				return r, fmt.Errorf("recurrence: unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 52 {
+				return r, fmt.Errorf("recurrence: invalid INTERVAL %q", val)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				wd, ok := weekdays[d]
+				if !ok {
+					return r, fmt.Errorf("recurrence: invalid BYDAY %q", d)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return r, fmt.Errorf("recurrence: invalid BYMONTHDAY %q", d)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("recurrence: invalid COUNT %q", val)
+			}
+			r.Count = n
+		case "UNTIL":
+			t, err := parseUntil(val)
+			if err != nil {
+				return r, err
+			}
+			r.Until = t
+		default:
+			return r, fmt.Errorf("recurrence: unsupported part %q", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return r, errors.New("recurrence: FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return r, errors.New("recurrence: COUNT and UNTIL are mutually exclusive")
+	}
+	return r, nil
+}
+
+func parseUntil(v string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return Day(t), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("recurrence: invalid UNTIL %q", v)
+}
+
+// Day truncates t to midnight UTC of its calendar day.
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Expand returns the rule's dates from start to end inclusive, in order,
+// leaving out exceptions. start anchors INTERVAL and the default weekday /
+// day of month, as DTSTART does in RFC 5545. Like RFC 5545 EXDATE,
+// exceptions still count towards COUNT.
+func (r Rule) Expand(start, end time.Time, exceptions []time.Time) ([]time.Time, error) {
+	start, end = Day(start), Day(end)
+	if !r.Until.IsZero() && r.Until.Before(end) {
+		end = r.Until
+	}
+
+	skip := make(map[time.Time]bool, len(exceptions))
+	for _, e := range exceptions {
+		skip[Day(e)] = true
+	}
+
+	var out []time.Time
+	matched := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !r.matches(start, d) {
+			continue
+		}
+		matched++
+		if r.Count > 0 && matched > r.Count {
+			break
+		}
+		if skip[d] {
+			continue
+		}
+		if len(out) == MaxOccurrences {
+			return nil, ErrTooMany
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (r Rule) matches(start, d time.Time) bool {
+	switch r.Freq {
+	case Daily:
+		days := int(d.Sub(start).Hours() / 24)
+		return days%r.Interval == 0 && r.dayAllowed(d)
+
+	case Weekly:
+		weeks := int(weekStart(d).Sub(weekStart(start)).Hours() / 24 / 7)
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return d.Weekday() == start.Weekday()
+		}
+		return r.dayAllowed(d)
+
+	case Monthly:
+		months := (d.Year()-start.Year())*12 + int(d.Month()) - int(start.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		switch {
+		case len(r.ByMonthDay) > 0:
+			return r.monthDayAllowed(d) && r.dayAllowed(d)
+		case len(r.ByDay) > 0:
+			return r.dayAllowed(d)
+		default:
+			return d.Day() == start.Day()
+		}
+	}
+	return false
+}
+
+func (r Rule) dayAllowed(d time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if d.Weekday() == wd {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) monthDayAllowed(d time.Time) bool {
+	last := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, md := range r.ByMonthDay {
+		if md < 0 {
+			md = last + md + 1
+		}
+		if d.Day() == md {
+			return true
+		}
+	}
+	return false
+}
+
+// weekStart returns the Monday of d's week.
+func weekStart(d time.Time) time.Time {
+	offset := (int(d.Weekday()) + 6) % 7
+	return d.AddDate(0, 0, -offset)
+}
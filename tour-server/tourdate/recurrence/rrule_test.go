@@ -0,0 +1,109 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func dates(ts []time.Time) []string {
+	out := make([]string, len(ts))
+	for i, t := range ts {
+		out[i] = t.Format("2006-01-02")
+	}
+	return out
+}
+
+func expect(t *testing.T, got []time.Time, want ...string) {
+	t.Helper()
+	g := dates(got)
+	if len(g) != len(want) {
+		t.Fatalf("got %v, want %v", g, want)
+	}
+	for i := range want {
+		if g[i] != want[i] {
+			t.Fatalf("got %v, want %v", g, want)
+		}
+	}
+}
+
+func TestWeeklySaturdaysWithException(t *testing.T) {
+	r, err := Parse("RRULE:FREQ=WEEKLY;BYDAY=SA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Expand(day("2026-06-01"), day("2026-06-30"), []time.Time{day("2026-06-13")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, got, "2026-06-06", "2026-06-20", "2026-06-27")
+}
+
+func TestWeeklyDefaultsToStartWeekday(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;INTERVAL=2")
+	got, _ := r.Expand(day("2026-06-03"), day("2026-07-15"), nil) // a Wednesday
+	expect(t, got, "2026-06-03", "2026-06-17", "2026-07-01", "2026-07-15")
+}
+
+func TestWeeklyIntervalMultipleDays(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR")
+	got, _ := r.Expand(day("2026-06-03"), day("2026-06-22"), nil)
+	// Week of 06-01 matches (its Monday is before start), week of 06-08 doesn't.
+	expect(t, got, "2026-06-05", "2026-06-15", "2026-06-19")
+}
+
+func TestDailyInterval(t *testing.T) {
+	r, _ := Parse("FREQ=DAILY;INTERVAL=3")
+	got, _ := r.Expand(day("2026-06-01"), day("2026-06-10"), nil)
+	expect(t, got, "2026-06-01", "2026-06-04", "2026-06-07", "2026-06-10")
+}
+
+func TestMonthlyLastDay(t *testing.T) {
+	r, _ := Parse("FREQ=MONTHLY;BYMONTHDAY=-1")
+	got, _ := r.Expand(day("2026-01-15"), day("2026-04-30"), nil)
+	expect(t, got, "2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30")
+}
+
+func TestCountIncludesExceptions(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;BYDAY=SA;COUNT=3")
+	got, _ := r.Expand(day("2026-06-01"), day("2026-12-31"), []time.Time{day("2026-06-13")})
+	expect(t, got, "2026-06-06", "2026-06-20")
+}
+
+func TestUntilLimitsEnd(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;BYDAY=SA;UNTIL=20260614T000000Z")
+	got, _ := r.Expand(day("2026-06-01"), day("2026-12-31"), nil)
+	expect(t, got, "2026-06-06", "2026-06-13")
+}
+
+func TestTooManyOccurrences(t *testing.T) {
+	r, _ := Parse("FREQ=DAILY")
+	if _, err := r.Expand(day("2026-01-01"), day("2027-12-31"), nil); err != ErrTooMany {
+		t.Errorf("expected ErrTooMany, got %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"BYDAY=SA",
+		"FREQ=YEARLY",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;INTERVAL=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=WEEKLY;COUNT=2;UNTIL=20260101",
+		"FREQ=WEEKLY;BYSETPOS=1",
+		"FREQ",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected error", s)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package touritinerary holds the day-by-day tour programme shared by the
+// public tour page, the admin editor, emails and printable documents.
+package touritinerary
+
+import (
+	"strings"
+	"tour-server/email"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Meals that can be included on a day.
+const (
+	MealBreakfast = "breakfast"
+	MealLunch     = "lunch"
+	MealDinner    = "dinner"
+)
+
+// AllMeals lists the meals in serving order.
+var AllMeals = []string{MealBreakfast, MealLunch, MealDinner}
+
+var mealNames = map[string]string{
+	MealBreakfast: "сніданок",
+	MealLunch:     "обід",
+	MealDinner:    "вечеря",
+}
+
+// ValidMeal reports whether m is a known meal.
+func ValidMeal(m string) bool {
+	_, ok := mealNames[m]
+	return ok
+}
+
+// Location is the overnight stop of a day.
+type Location struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+}
+
+// Day is one day of a tour's itinerary.
+type Day struct {
+	ID                uint      `json:"id"`
+	DayNumber         int       `json:"dayNumber"`
+	Title             string    `json:"title"`
+	Description       string    `json:"description"`
+	OvernightLocation *Location `json:"overnightLocation"`
+	Meals             []string  `json:"meals"`
+	Images            []string  `json:"images"`
+}
+
+// MealsText renders the included meals in Ukrainian, e.g. "сніданок, вечеря".
+func (d Day) MealsText() string {
+	names := make([]string, 0, len(d.Meals))
+	for _, m := range AllMeals {
+		for _, got := range d.Meals {
+			if got == m {
+				names = append(names, mealNames[m])
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// OvernightText renders the overnight stop as "Name, Country".
+func (d Day) OvernightText() string {
+	if d.OvernightLocation == nil {
+		return ""
+	}
+	if d.OvernightLocation.Country == "" {
+		return d.OvernightLocation.Name
+	}
+	return d.OvernightLocation.Name + ", " + d.OvernightLocation.Country
+}
+
+// Load returns the tour's itinerary ordered by day.
+func Load(db *gorm.DB, tourID uint) ([]Day, error) {
+	var rows []struct {
+		ID          uint           `gorm:"column:id"`
+		DayNumber   int            `gorm:"column:day_number"`
+		Title       string         `gorm:"column:title"`
+		Description string         `gorm:"column:description"`
+		LocationID  *uint          `gorm:"column:location_id"`
+		Location    string         `gorm:"column:location_name"`
+		Country     string         `gorm:"column:location_country"`
+		Meals       pq.StringArray `gorm:"column:meals;type:text[]"`
+		Images      pq.StringArray `gorm:"column:images;type:text[]"`
+	}
+	err := db.Raw(`
+		SELECT d.id, d.day_number, d.title, d.description,
+			l.id AS location_id, COALESCE(l.name, '') AS location_name,
+			COALESCE(l.country, '') AS location_country,
+			d.meals, d.images
+		FROM tour_itinerary_days d
+		LEFT JOIN locations l ON d.overnight_location_id = l.id
+		WHERE d.tour_id = ?
+		ORDER BY d.day_number
+	`, tourID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]Day, 0, len(rows))
+	for _, r := range rows {
+		d := Day{
+			ID:          r.ID,
+			DayNumber:   r.DayNumber,
+			Title:       r.Title,
+			Description: r.Description,
+			Meals:       append([]string{}, r.Meals...),
+			Images:      append([]string{}, r.Images...),
+		}
+		if r.LocationID != nil {
+			d.OvernightLocation = &Location{ID: *r.LocationID, Name: r.Location, Country: r.Country}
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// LoadForBooking returns the itinerary of the tour a booking is for.
+func LoadForBooking(db *gorm.DB, bookingID uint) ([]Day, error) {
+	var tourID uint
+	db.Raw(`
+		SELECT td.tour_id FROM bookings b
+		JOIN tour_dates td ON b.tour_date_id = td.id
+		WHERE b.id = ?
+	`, bookingID).Scan(&tourID)
+	if tourID == 0 {
+		return nil, nil
+	}
+	return Load(db, tourID)
+}
+
+// EmailDays converts an itinerary to the rows shown in emails.
+func EmailDays(days []Day) []email.ItineraryDay {
+	out := make([]email.ItineraryDay, 0, len(days))
+	for _, d := range days {
+		out = append(out, email.ItineraryDay{
+			DayNumber:   d.DayNumber,
+			Title:       d.Title,
+			Description: d.Description,
+			Overnight:   d.OvernightText(),
+			Meals:       d.MealsText(),
+		})
+	}
+	return out
+}
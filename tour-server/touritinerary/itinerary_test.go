@@ -0,0 +1,23 @@
+package touritinerary
+
+import "testing"
+
+func TestMealsText(t *testing.T) {
+	d := Day{Meals: []string{MealDinner, MealBreakfast}}
+	if got := d.MealsText(); got != "сніданок, вечеря" {
+		t.Errorf("expected meals in serving order, got %q", got)
+	}
+	if got := (Day{}).MealsText(); got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+}
+
+func TestOvernightText(t *testing.T) {
+	d := Day{OvernightLocation: &Location{Name: "Яремче", Country: "Україна"}}
+	if got := d.OvernightText(); got != "Яремче, Україна" {
+		t.Errorf("got %q", got)
+	}
+	if got := (Day{}).OvernightText(); got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+}
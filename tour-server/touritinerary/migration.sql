@@ -0,0 +1,20 @@
+-- Migration: day-by-day tour itinerary
+-- One row per day of a tour. day_number is unique per tour; the constraint
+-- is deferred so days can be reordered inside one transaction.
+-- Run after geo/migration.sql (overnight stops reference cities).
+
+CREATE TABLE IF NOT EXISTS tour_itinerary_days (
+    id                    SERIAL PRIMARY KEY,
+    tour_id               INTEGER NOT NULL REFERENCES tours(id) ON DELETE CASCADE,
+    day_number            INTEGER NOT NULL CHECK (day_number >= 1),
+    title                 VARCHAR(255) NOT NULL,
+    description           TEXT NOT NULL DEFAULT '',
+    overnight_location_id INTEGER REFERENCES cities(id) ON DELETE SET NULL,
+    meals                 TEXT[] NOT NULL DEFAULT '{}',
+    images                TEXT[] NOT NULL DEFAULT '{}',
+    created_at            TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_tour_itinerary_day UNIQUE (tour_id, day_number) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX IF NOT EXISTS idx_tour_itinerary_days_tour_id ON tour_itinerary_days(tour_id);
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"tour-server/tourratings"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GET /tours/:id/rating-summary
+func GetRatingSummary(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Невірний ID туру"})
+		}
+
+		summary, found, err := tourratings.GetSummary(db, uint(tourID))
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch rating summary"})
+		}
+		if !found {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Тур не знайдено"})
+		}
+		return c.JSON(http.StatusOK, summary)
+	}
+}
@@ -0,0 +1,89 @@
+-- Migration: computed tour rating
+-- tours.rating used to be edited by hand while tour_ratings (POST
+-- /tour-ratings) and rated comments in tour_reviews went their own way.
+-- From here on tours.rating is a Bayesian average of both, kept current
+-- by triggers, with review_count next to it:
+--
+--     rating = (C * m + sum of votes) / (C + votes),  C = 5
+--
+-- where m is the mean of all votes on all tours. Tours without votes get
+-- 0 ("no rating yet"). m drifts slowly, so tourratings.StartJob also
+-- recalculates every tour daily.
+
+ALTER TABLE tours
+    ADD COLUMN IF NOT EXISTS review_count       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_updated_at  TIMESTAMP;
+
+-- One vote per person and tour: the explicit tour_ratings row if there is
+-- one, otherwise their latest rated top-level comment. Guest comments
+-- count one each.
+CREATE OR REPLACE VIEW tour_rating_votes AS
+SELECT tour_id, rating::numeric AS rating
+FROM tour_ratings
+UNION ALL
+SELECT tour_id, rating
+FROM (
+    SELECT DISTINCT ON (r.tour_id, COALESCE(r.user_id, -r.id))
+        r.tour_id, r.rating::numeric AS rating
+    FROM tour_reviews r
+    WHERE r.parent_id IS NULL
+      AND r.rating IS NOT NULL
+      AND NOT EXISTS (
+          SELECT 1 FROM tour_ratings tr
+          WHERE tr.tour_id = r.tour_id AND tr.user_id = r.user_id
+      )
+    ORDER BY r.tour_id, COALESCE(r.user_id, -r.id), r.updated_at DESC
+) rv;
+
+CREATE OR REPLACE FUNCTION refresh_tour_rating(p_tour_id INTEGER) RETURNS VOID AS $$
+DECLARE
+    prior_weight CONSTANT NUMERIC := 5;
+    prior_mean NUMERIC;
+    n INTEGER;
+    total NUMERIC;
+    computed NUMERIC := 0;
+BEGIN
+    SELECT COALESCE(AVG(rating), 4) INTO prior_mean FROM tour_rating_votes;
+    SELECT COUNT(*), COALESCE(SUM(rating), 0) INTO n, total
+    FROM tour_rating_votes WHERE tour_id = p_tour_id;
+
+    IF n > 0 THEN
+        computed := ROUND((prior_weight * prior_mean + total) / (prior_weight + n), 2);
+    END IF;
+
+    -- Skip no-op writes: tours.rating feeds the search index trigger.
+    UPDATE tours
+    SET rating = computed, review_count = n, rating_updated_at = NOW()
+    WHERE id = p_tour_id
+      AND (rating IS DISTINCT FROM computed OR review_count IS DISTINCT FROM n);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION tour_rating_sync() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM refresh_tour_rating(OLD.tour_id);
+    END IF;
+    IF TG_OP <> 'DELETE' AND (TG_OP = 'INSERT' OR NEW.tour_id IS DISTINCT FROM OLD.tour_id) THEN
+        PERFORM refresh_tour_rating(NEW.tour_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- The old triggers set tours.rating to a plain average of one table each.
+DROP TRIGGER IF EXISTS trg_update_tour_rating ON tour_reviews;
+DROP TRIGGER IF EXISTS trg_update_tour_rating_from_ratings ON tour_ratings;
+
+DROP TRIGGER IF EXISTS trg_tour_rating ON tour_ratings;
+CREATE TRIGGER trg_tour_rating
+    AFTER INSERT OR UPDATE OF tour_id, user_id, rating OR DELETE ON tour_ratings
+    FOR EACH ROW EXECUTE FUNCTION tour_rating_sync();
+
+DROP TRIGGER IF EXISTS trg_tour_rating ON tour_reviews;
+CREATE TRIGGER trg_tour_rating
+    AFTER INSERT OR UPDATE OF tour_id, user_id, parent_id, rating OR DELETE ON tour_reviews
+    FOR EACH ROW EXECUTE FUNCTION tour_rating_sync();
+
+-- Backfill
+SELECT refresh_tour_rating(id) FROM tours;
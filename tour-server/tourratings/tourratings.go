@@ -0,0 +1,108 @@
+// Package tourratings reads and refreshes the computed tour rating: a
+// Bayesian average of tour_ratings and rated comments that triggers keep
+// in tours.rating and tours.review_count (see migration.sql).
+package tourratings
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StarCount is one bar of the histogram: votes rounded to Stars.
+type StarCount struct {
+	Stars int `json:"stars" gorm:"column:stars"`
+	Count int `json:"count" gorm:"column:count"`
+}
+
+// Summary is the rating block on a tour page.
+type Summary struct {
+	TourID      uint        `json:"tour_id"`
+	Rating      float64     `json:"rating"`       // Bayesian, what search sorts and filters on
+	Average     float64     `json:"average"`      // plain mean of the votes
+	ReviewCount int         `json:"review_count"` // votes, one per person
+	Histogram   []StarCount `json:"histogram"`    // 5 stars down to 1, zeros included
+}
+
+// GetSummary loads the rating summary of a tour. found is false if the
+// tour doesn't exist.
+func GetSummary(db *gorm.DB, tourID uint) (s Summary, found bool, err error) {
+	var tour struct {
+		ID          uint    `gorm:"column:id"`
+		Rating      float64 `gorm:"column:rating"`
+		ReviewCount int     `gorm:"column:review_count"`
+	}
+	err = db.Raw("SELECT id, COALESCE(rating, 0) AS rating, review_count FROM tours WHERE id = ?", tourID).
+		Scan(&tour).Error
+	if err != nil || tour.ID == 0 {
+		return s, false, err
+	}
+
+	var agg struct {
+		Average float64 `gorm:"column:average"`
+	}
+	var rows []StarCount
+	err = db.Raw(`
+		SELECT COALESCE(ROUND(AVG(rating), 2), 0) AS average
+		FROM tour_rating_votes WHERE tour_id = ?
+	`, tourID).Scan(&agg).Error
+	if err != nil {
+		return s, true, err
+	}
+	err = db.Raw(`
+		SELECT LEAST(GREATEST(ROUND(rating), 1), 5)::int AS stars, COUNT(*) AS count
+		FROM tour_rating_votes WHERE tour_id = ?
+		GROUP BY 1
+	`, tourID).Scan(&rows).Error
+	if err != nil {
+		return s, true, err
+	}
+
+	return Summary{
+		TourID:      tour.ID,
+		Rating:      tour.Rating,
+		Average:     agg.Average,
+		ReviewCount: tour.ReviewCount,
+		Histogram:   histogram(rows),
+	}, true, nil
+}
+
+// histogram returns counts for 5 stars down to 1, zeros included.
+func histogram(rows []StarCount) []StarCount {
+	out := make([]StarCount, 5)
+	for i := range out {
+		out[i].Stars = 5 - i
+	}
+	for _, r := range rows {
+		if r.Stars >= 1 && r.Stars <= 5 {
+			out[5-r.Stars].Count += r.Count
+		}
+	}
+	return out
+}
+
+// Refresh recalculates one tour's rating. Triggers already do this on
+// every vote; this is for callers that changed votes behind them.
+func Refresh(db *gorm.DB, tourID uint) error {
+	return db.Exec("SELECT refresh_tour_rating(?)", tourID).Error
+}
+
+// RefreshAll recalculates every tour, picking up changes in the overall
+// mean the Bayesian prior is based on.
+func RefreshAll(db *gorm.DB) error {
+	return db.Exec("SELECT refresh_tour_rating(id) FROM tours").Error
+}
+
+// StartJob runs RefreshAll every interval in a goroutine.
+func StartJob(db *gorm.DB, interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+			if err := RefreshAll(db); err != nil {
+				log.Printf("Tour ratings refresh failed: %v", err)
+			}
+		}
+	}()
+	log.Printf("Tour ratings refresh job started (every %s)", interval)
+}
@@ -0,0 +1,16 @@
+package tourratings
+
+import "testing"
+
+func TestHistogram(t *testing.T) {
+	h := histogram([]StarCount{{Stars: 5, Count: 3}, {Stars: 2, Count: 1}})
+	if len(h) != 5 {
+		t.Fatalf("expected 5 bars, got %d", len(h))
+	}
+	want := []StarCount{{5, 3}, {4, 0}, {3, 0}, {2, 1}, {1, 0}}
+	for i, w := range want {
+		if h[i] != w {
+			t.Errorf("bar %d: expected %+v, got %+v", i, w, h[i])
+		}
+	}
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"log"
 	"net/http"
+	"tour-server/middleware"
+	"tour-server/moderation"
 	"tour-server/tourreviews/dto"
 	"tour-server/tourreviews/models"
 
@@ -25,14 +27,28 @@ func CreateTourReview(db *gorm.DB) echo.HandlerFunc {
 			})
 		}
 		userID := c.Get("user_id").(uint)
+		req.Comment = middleware.SanitizeComment(req.Comment, 1000)
+
+		var confirmed int64
+		db.Raw(`
+			SELECT COUNT(*) FROM bookings b
+			JOIN tour_dates td ON b.tour_date_id = td.id
+			WHERE td.tour_id = ? AND b.user_id = ? AND b.status = 'confirmed'
+		`, req.TourID, userID).Scan(&confirmed)
 
 		review := models.TourReviews{
-			TourID:    req.TourID,
-			UserID:    userID,
-			BookingID: req.BookingID,
-			Rating:    req.Rating,
-			Comment:   req.Comment,
+			TourID:          req.TourID,
+			UserID:          userID,
+			BookingID:       req.BookingID,
+			Rating:          req.Rating,
+			Comment:         req.Comment,
+			IsVerifiedBuyer: confirmed > 0,
 		}
+		review.Status, review.ModerationFlags = moderation.Screen(db, moderation.Submission{
+			Text:          review.Comment,
+			UserID:        &userID,
+			VerifiedBuyer: review.IsVerifiedBuyer,
+		}, 0)
 
 		if err := db.Create(&review).Error; err != nil {
 			log.Printf("Failed to create tour review: %v\n", err)
@@ -46,6 +62,7 @@ func CreateTourReview(db *gorm.DB) echo.HandlerFunc {
 			UserID:    review.UserID,
 			Rating:    review.Rating,
 			Comment:   review.Comment,
+			Status:    review.Status,
 			CreatedAt: review.CreatedAt,
 		}
 
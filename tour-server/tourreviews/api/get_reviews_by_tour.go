@@ -18,7 +18,7 @@ func GetReviewsByTourID(db *gorm.DB) echo.HandlerFunc {
 
 		err := db.Table("tour_reviews").
 			Select("id,user_id,rating,comment,created_at").
-			Where("tour_id = ?", tourID).
+			Where("tour_id = ? AND status = 'approved'", tourID).
 			Scan(&reviews).Error
 
 		if err != nil {
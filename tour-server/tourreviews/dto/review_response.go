@@ -7,5 +7,6 @@ type TourReviewResponse struct {
 	UserID    uint      `json:"user_id"`
 	Rating    int       `json:"rating"`
 	Comment   string    `json:"comment"`
+	Status    string    `json:"status,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
 
 type TourReviews struct {
 	ID        uint `json:"id" gorm:"primaryKey"`
@@ -11,4 +15,9 @@ type TourReviews struct {
 	Comment   string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Moderation (moderation package)
+	IsVerifiedBuyer bool
+	Status          string         `gorm:"default:approved"`
+	ModerationFlags pq.StringArray `gorm:"type:text[]"`
 }
@@ -23,6 +23,7 @@ func GetTourSeatsByTourID(db *gorm.DB) echo.HandlerFunc {
 			Joins("JOIN tour_dates ON tour_seats.tour_date_id = tour_dates.id"). // Join to get date information
 			Joins("JOIN tours ON tour_dates.tour_id = tours.id").                // Join to get tour price
 			Where("tour_dates.tour_id =?", tourID).                              // Filter by the specified tour ID
+			Where("NOT tour_dates.is_retired").                                  // Retired departures take no new bookings
 			Scan(&tourSeats).Error
 
 		// Handle database errors
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tour-server/tourtaxonomy"
+	"tour-server/tourtaxonomy/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CategoryRequest struct {
+	Slug        *string `json:"slug"` // create: derived from name if omitted
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	SortOrder   *int    `json:"sort_order"`
+}
+
+type CategoryItem struct {
+	models.Category
+	ToursCount int64 `json:"tours_count" gorm:"column:tours_count"`
+}
+
+// validateCategory trims and checks the fields that are present.
+func validateCategory(req *CategoryRequest) string {
+	if req.Name != nil {
+		n := strings.TrimSpace(*req.Name)
+		if n == "" || len(n) > 100 {
+			return "name is required (max 100 characters)"
+		}
+		req.Name = &n
+	}
+	if req.Slug != nil {
+		s := strings.TrimSpace(*req.Slug)
+		if !tourtaxonomy.ValidSlug(s) {
+			return "slug may contain only a-z, 0-9 and dashes"
+		}
+		req.Slug = &s
+	}
+	return ""
+}
+
+// GET /categories
+// All categories with the number of active tours in each.
+func GetCategories(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var items []CategoryItem
+		err := db.Raw(`
+			SELECT c.*, COUNT(t.id) AS tours_count
+			FROM categories c
+			LEFT JOIN tour_categories tc ON tc.category_id = c.id
+			LEFT JOIN tours t ON t.id = tc.tour_id
+				AND t.status_id = (SELECT id FROM statuses WHERE name = 'active')
+			GROUP BY c.id
+			ORDER BY c.sort_order, c.name
+		`).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch categories",
+			})
+		}
+
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+// POST /admin/categories
+func CreateCategory(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CategoryRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.Name == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "name is required",
+			})
+		}
+		if req.Slug == nil {
+			s := tourtaxonomy.Slugify(*req.Name)
+			req.Slug = &s
+		}
+		if msg := validateCategory(&req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		var exists int64
+		db.Table("categories").Where("slug = ?", *req.Slug).Count(&exists)
+		if exists > 0 {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "Category with this slug already exists",
+			})
+		}
+
+		category := models.Category{Slug: *req.Slug, Name: *req.Name}
+		if req.Description != nil {
+			category.Description = *req.Description
+		}
+		if req.SortOrder != nil {
+			category.SortOrder = *req.SortOrder
+		}
+		if err := db.Create(&category).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create category",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message":  "Category created",
+			"category": category,
+		})
+	}
+}
+
+// PUT /admin/categories/:id
+func UpdateCategory(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid category ID",
+			})
+		}
+
+		var req CategoryRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if msg := validateCategory(&req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		updates := map[string]interface{}{"updated_at": time.Now()}
+		if req.Slug != nil {
+			var exists int64
+			db.Table("categories").Where("slug = ? AND id <> ?", *req.Slug, id).Count(&exists)
+			if exists > 0 {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "Category with this slug already exists",
+				})
+			}
+			updates["slug"] = *req.Slug
+		}
+		if req.Name != nil {
+			updates["name"] = *req.Name
+		}
+		if req.Description != nil {
+			updates["description"] = *req.Description
+		}
+		if req.SortOrder != nil {
+			updates["sort_order"] = *req.SortOrder
+		}
+
+		result := db.Table("categories").Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update category",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Category not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Category updated",
+		})
+	}
+}
+
+// DELETE /admin/categories/:id
+// Tours in the category simply lose it.
+func DeleteCategory(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid category ID",
+			})
+		}
+
+		result := db.Exec("DELETE FROM categories WHERE id = ?", id)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete category",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Category not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Category deleted",
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tour-server/tour/dto"
+	"tour-server/tourtaxonomy"
+	"tour-server/tourtaxonomy/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CollectionRequest struct {
+	Slug         *string `json:"slug"` // create: derived from title if omitted
+	Title        *string `json:"title"`
+	Description  *string `json:"description"`
+	CoverImage   *string `json:"cover_image"`
+	IsActive     *bool   `json:"is_active"`
+	ShowInSwiper *bool   `json:"show_in_swiper"`
+	SortOrder    *int    `json:"sort_order"`
+}
+
+type CollectionItem struct {
+	models.Collection
+	ToursCount int64 `json:"tours_count" gorm:"column:tours_count"`
+}
+
+type CollectionDetail struct {
+	models.Collection
+	Tours []dto.TourCard `json:"tours"`
+}
+
+const maxCollectionTours = 100
+
+// validateCollection trims and checks the fields that are present.
+func validateCollection(req *CollectionRequest) string {
+	if req.Title != nil {
+		t := strings.TrimSpace(*req.Title)
+		if t == "" || len(t) > 255 {
+			return "title is required (max 255 characters)"
+		}
+		req.Title = &t
+	}
+	if req.Slug != nil {
+		s := strings.TrimSpace(*req.Slug)
+		if !tourtaxonomy.ValidSlug(s) {
+			return "slug may contain only a-z, 0-9 and dashes"
+		}
+		req.Slug = &s
+	}
+	if req.CoverImage != nil && len(*req.CoverImage) > 500 {
+		return "cover_image is too long"
+	}
+	return ""
+}
+
+// GET /collections
+// Active collections with their number of active tours.
+func GetCollections(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var items []CollectionItem
+		err := db.Raw(`
+			SELECT col.*, COUNT(t.id) AS tours_count
+			FROM collections col
+			LEFT JOIN collection_tours ct ON ct.collection_id = col.id
+			LEFT JOIN tours t ON t.id = ct.tour_id
+				AND t.status_id = (SELECT id FROM statuses WHERE name = 'active')
+			WHERE col.is_active
+			GROUP BY col.id
+			ORDER BY col.sort_order, col.id
+		`).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch collections",
+			})
+		}
+
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+// GET /collections/:slug
+// A collection and its active tours in curated order.
+func GetCollectionBySlug(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		slug := strings.ToLower(c.Param("slug"))
+		if !tourtaxonomy.ValidSlug(slug) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Collection not found",
+			})
+		}
+
+		var detail CollectionDetail
+		err := db.Raw("SELECT * FROM collections WHERE slug = ? AND is_active", slug).Scan(&detail.Collection).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch collection",
+			})
+		}
+		if detail.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Collection not found",
+			})
+		}
+
+		detail.Tours = []dto.TourCard{}
+		err = db.Raw(`
+			SELECT t.tour_id AS id, t.title, t.price, t.rating,
+				COALESCE(t.image_src, 'no-image.jpg') AS image_src
+			FROM collection_tours ct
+			JOIN tour_search_index t ON t.tour_id = ct.tour_id
+			WHERE ct.collection_id = ?
+				AND t.status_id = (SELECT id FROM statuses WHERE name = 'active')
+			ORDER BY ct.position, t.tour_id
+		`, detail.ID).Scan(&detail.Tours).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch collection tours",
+			})
+		}
+
+		return c.JSON(http.StatusOK, detail)
+	}
+}
+
+// GET /admin/collections
+// Every collection, including inactive ones, with all assigned tour IDs.
+func GetAdminCollections(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var items []struct {
+			models.Collection
+			TourIDs []uint `json:"tour_ids" gorm:"-"`
+		}
+		if err := db.Raw("SELECT * FROM collections ORDER BY sort_order, id").Scan(&items).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch collections",
+			})
+		}
+
+		var links []struct {
+			CollectionID uint `gorm:"column:collection_id"`
+			TourID       uint `gorm:"column:tour_id"`
+		}
+		db.Raw("SELECT collection_id, tour_id FROM collection_tours ORDER BY collection_id, position").Scan(&links)
+		byCollection := map[uint][]uint{}
+		for _, l := range links {
+			byCollection[l.CollectionID] = append(byCollection[l.CollectionID], l.TourID)
+		}
+		for i := range items {
+			items[i].TourIDs = byCollection[items[i].ID]
+			if items[i].TourIDs == nil {
+				items[i].TourIDs = []uint{}
+			}
+		}
+
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+// POST /admin/collections
+func CreateCollection(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CollectionRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.Title == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "title is required",
+			})
+		}
+		if req.Slug == nil {
+			s := tourtaxonomy.Slugify(*req.Title)
+			req.Slug = &s
+		}
+		if msg := validateCollection(&req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		var exists int64
+		db.Table("collections").Where("slug = ?", *req.Slug).Count(&exists)
+		if exists > 0 {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "Collection with this slug already exists",
+			})
+		}
+
+		collection := models.Collection{Slug: *req.Slug, Title: *req.Title, IsActive: true}
+		if req.Description != nil {
+			collection.Description = *req.Description
+		}
+		if req.CoverImage != nil {
+			collection.CoverImage = *req.CoverImage
+		}
+		if req.IsActive != nil {
+			collection.IsActive = *req.IsActive
+		}
+		if req.ShowInSwiper != nil {
+			collection.ShowInSwiper = *req.ShowInSwiper
+		}
+		if req.SortOrder != nil {
+			collection.SortOrder = *req.SortOrder
+		}
+
+		tx := db.Begin()
+		if collection.ShowInSwiper {
+			tx.Exec("UPDATE collections SET show_in_swiper = FALSE WHERE show_in_swiper")
+		}
+		if err := tx.Create(&collection).Error; err != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create collection",
+			})
+		}
+		tx.Commit()
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message":    "Collection created",
+			"collection": collection,
+		})
+	}
+}
+
+// PUT /admin/collections/:id
+// Setting show_in_swiper moves the home page swiper to this collection.
+func UpdateCollection(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid collection ID",
+			})
+		}
+
+		var req CollectionRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if msg := validateCollection(&req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		updates := map[string]interface{}{"updated_at": time.Now()}
+		if req.Slug != nil {
+			var exists int64
+			db.Table("collections").Where("slug = ? AND id <> ?", *req.Slug, id).Count(&exists)
+			if exists > 0 {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "Collection with this slug already exists",
+				})
+			}
+			updates["slug"] = *req.Slug
+		}
+		if req.Title != nil {
+			updates["title"] = *req.Title
+		}
+		if req.Description != nil {
+			updates["description"] = *req.Description
+		}
+		if req.CoverImage != nil {
+			updates["cover_image"] = *req.CoverImage
+		}
+		if req.IsActive != nil {
+			updates["is_active"] = *req.IsActive
+		}
+		if req.ShowInSwiper != nil {
+			updates["show_in_swiper"] = *req.ShowInSwiper
+		}
+		if req.SortOrder != nil {
+			updates["sort_order"] = *req.SortOrder
+		}
+
+		tx := db.Begin()
+		if req.ShowInSwiper != nil && *req.ShowInSwiper {
+			tx.Exec("UPDATE collections SET show_in_swiper = FALSE WHERE show_in_swiper AND id <> ?", id)
+		}
+		result := tx.Table("collections").Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			tx.Rollback()
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update collection",
+			})
+		}
+		if result.RowsAffected == 0 {
+			tx.Rollback()
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Collection not found",
+			})
+		}
+		tx.Commit()
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Collection updated",
+		})
+	}
+}
+
+// DELETE /admin/collections/:id
+func DeleteCollection(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid collection ID",
+			})
+		}
+
+		result := db.Exec("DELETE FROM collections WHERE id = ?", id)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete collection",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Collection not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Collection deleted",
+		})
+	}
+}
+
+// PUT /admin/collections/:id/tours
+// Replaces the collection's tours; the order of tour_ids is the display order.
+func SetCollectionTours(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid collection ID",
+			})
+		}
+
+		var req struct {
+			TourIDs []uint `json:"tour_ids"`
+		}
+		if err := c.Bind(&req); err != nil || req.TourIDs == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "tour_ids required",
+			})
+		}
+		tourIDs := uniqueIDs(req.TourIDs)
+		if len(tourIDs) > maxCollectionTours {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "at most 100 tours per collection",
+			})
+		}
+
+		var exists int64
+		db.Table("collections").Where("id = ?", id).Count(&exists)
+		if exists == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Collection not found",
+			})
+		}
+		if len(tourIDs) > 0 {
+			var found int64
+			db.Table("tours").Where("id IN ?", tourIDs).Count(&found)
+			if int(found) != len(tourIDs) {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Unknown tour",
+				})
+			}
+		}
+
+		tx := db.Begin()
+		tx.Exec("DELETE FROM collection_tours WHERE collection_id = ?", id)
+		for i, tourID := range tourIDs {
+			if err := tx.Exec("INSERT INTO collection_tours (collection_id, tour_id, position) VALUES (?, ?, ?)", id, tourID, i).Error; err != nil {
+				tx.Rollback()
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to update collection tours",
+				})
+			}
+		}
+		tx.Exec("UPDATE collections SET updated_at = NOW() WHERE id = ?", id)
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update collection tours",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Collection tours updated",
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tour-server/tourtaxonomy"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type TagItem struct {
+	tourtaxonomy.Term
+	ToursCount int64 `json:"tours_count" gorm:"column:tours_count"`
+}
+
+// GET /tags?q=мор
+// Tags used by at least one active tour, most used first. q filters by
+// name prefix for autocomplete.
+func GetTags(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		where := "TRUE"
+		var args []interface{}
+		if q := strings.TrimSpace(c.QueryParam("q")); q != "" {
+			where = "tg.name ILIKE ?"
+			args = append(args, q+"%")
+		}
+
+		var items []TagItem
+		err := db.Raw(`
+			SELECT tg.id, tg.slug, tg.name, COUNT(t.id) AS tours_count
+			FROM tags tg
+			JOIN tour_tags tt ON tt.tag_id = tg.id
+			JOIN tours t ON t.id = tt.tour_id
+				AND t.status_id = (SELECT id FROM statuses WHERE name = 'active')
+			WHERE `+where+`
+			GROUP BY tg.id
+			ORDER BY tours_count DESC, tg.name
+			LIMIT 100
+		`, args...).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch tags",
+			})
+		}
+
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+// GET /admin/tags
+// Every tag, including unused ones left over after tours were retagged.
+func GetAdminTags(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var items []TagItem
+		err := db.Raw(`
+			SELECT tg.id, tg.slug, tg.name, COUNT(tt.tour_id) AS tours_count
+			FROM tags tg
+			LEFT JOIN tour_tags tt ON tt.tag_id = tg.id
+			GROUP BY tg.id
+			ORDER BY tg.name
+		`).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch tags",
+			})
+		}
+
+		return c.JSON(http.StatusOK, items)
+	}
+}
+
+// PUT /admin/tags/:id
+// Renames a tag. The slug follows the new name unless the result would
+// clash with another tag.
+func UpdateTag(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid tag ID",
+			})
+		}
+
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		name := strings.TrimSpace(req.Name)
+		slug := tourtaxonomy.Slugify(name)
+		if name == "" || len(name) > 100 || slug == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "name is required (max 100 characters)",
+			})
+		}
+
+		var exists int64
+		db.Table("tags").Where("slug = ? AND id <> ?", slug, id).Count(&exists)
+		if exists > 0 {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "Tag with this name already exists",
+			})
+		}
+
+		result := db.Exec("UPDATE tags SET name = ?, slug = ? WHERE id = ?", name, slug, id)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update tag",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tag not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Tag updated",
+		})
+	}
+}
+
+// DELETE /admin/tags/:id
+func DeleteTag(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid tag ID",
+			})
+		}
+
+		result := db.Exec("DELETE FROM tags WHERE id = ?", id)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete tag",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tag not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Tag deleted",
+		})
+	}
+}
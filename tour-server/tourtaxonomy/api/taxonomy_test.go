@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, msg := normalizeTags([]string{"Море", " море ", "Вино"})
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if len(tags) != 2 || tags["more"] != "Море" || tags["vyno"] != "Вино" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+
+	if _, msg := normalizeTags([]string{"!!!"}); msg == "" {
+		t.Error("expected error for a tag without letters")
+	}
+	if _, msg := normalizeTags(make([]string, 21)); msg == "" {
+		t.Error("expected error for too many tags")
+	}
+}
+
+func TestCreateCategory_MissingName(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/categories", strings.NewReader(`{"slug": "beach"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := CreateCategory(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestCreateCollection_InvalidSlug(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/collections", strings.NewReader(`{"title": "Літо 2026", "slug": "Літо"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := CreateCollection(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestGetCollectionBySlug_InvalidSlug(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/collections/not%20a%20slug", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("slug")
+	c.SetParamValues("not a slug")
+
+	handler := GetCollectionBySlug(nil)
+	handler(c)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestSetTourTaxonomy_InvalidTag(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/tours/1/taxonomy", strings.NewReader(`{"tags": ["   "]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	handler := SetTourTaxonomy(nil)
+	handler(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
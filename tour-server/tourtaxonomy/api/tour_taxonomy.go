@@ -0,0 +1,169 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tour-server/tourtaxonomy"
+	"tour-server/webhooks"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type TourTaxonomyRequest struct {
+	CategoryIDs []uint   `json:"category_ids"`
+	Tags        []string `json:"tags"` // tag names; unknown ones are created
+}
+
+const maxTourTags = 20
+
+// normalizeTags trims and dedupes tag names by slug, keeping the first
+// spelling. Returns an error message for invalid input.
+func normalizeTags(names []string) (map[string]string, string) {
+	if len(names) > maxTourTags {
+		return nil, "at most 20 tags per tour"
+	}
+	bySlug := map[string]string{}
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		slug := tourtaxonomy.Slugify(n)
+		if n == "" || len(n) > 100 || slug == "" {
+			return nil, "invalid tag: " + n
+		}
+		if _, ok := bySlug[slug]; !ok {
+			bySlug[slug] = n
+		}
+	}
+	return bySlug, ""
+}
+
+// GET /admin/tours/:id/taxonomy
+func GetTourTaxonomy(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		categories, tags, err := tourtaxonomy.LoadForTour(db, uint(tourID))
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch tour taxonomy",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"categories": categories,
+			"tags":       tags,
+		})
+	}
+}
+
+// PUT /admin/tours/:id/taxonomy
+// Replaces the tour's categories and tags. Omitted lists are left as is;
+// an empty list clears them.
+func SetTourTaxonomy(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tourID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || tourID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Невалідний ID туру",
+			})
+		}
+
+		var req TourTaxonomyRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		tags, msg := normalizeTags(req.Tags)
+		if msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		var exists int64
+		db.Table("tours").Where("id = ?", tourID).Count(&exists)
+		if exists == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Tour not found",
+			})
+		}
+
+		if len(req.CategoryIDs) > 0 {
+			var found int64
+			db.Table("categories").Where("id IN ?", req.CategoryIDs).Count(&found)
+			if int(found) != len(uniqueIDs(req.CategoryIDs)) {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Unknown category",
+				})
+			}
+		}
+
+		tx := db.Begin()
+
+		if req.CategoryIDs != nil {
+			tx.Exec("DELETE FROM tour_categories WHERE tour_id = ?", tourID)
+			for _, id := range uniqueIDs(req.CategoryIDs) {
+				if err := tx.Exec("INSERT INTO tour_categories (tour_id, category_id) VALUES (?, ?)", tourID, id).Error; err != nil {
+					tx.Rollback()
+					return c.JSON(http.StatusInternalServerError, map[string]string{
+						"error": "Failed to update categories",
+					})
+				}
+			}
+		}
+
+		if req.Tags != nil {
+			tx.Exec("DELETE FROM tour_tags WHERE tour_id = ?", tourID)
+			for slug, name := range tags {
+				err := tx.Exec(`
+					INSERT INTO tags (slug, name) VALUES (?, ?)
+					ON CONFLICT (slug) DO NOTHING
+				`, slug, name).Error
+				if err == nil {
+					err = tx.Exec(`
+						INSERT INTO tour_tags (tour_id, tag_id)
+						SELECT ?, id FROM tags WHERE slug = ?
+					`, tourID, slug).Error
+				}
+				if err != nil {
+					tx.Rollback()
+					return c.JSON(http.StatusInternalServerError, map[string]string{
+						"error": "Failed to update tags",
+					})
+				}
+			}
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update tour taxonomy",
+			})
+		}
+
+		webhooks.PublishTour(db, uint(tourID))
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Tour taxonomy updated",
+		})
+	}
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := map[uint]bool{}
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
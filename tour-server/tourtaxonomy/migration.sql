@@ -0,0 +1,63 @@
+-- Migration: tour categories, tags and curated collections
+-- Categories are an admin-managed fixed list, tags are free-form labels
+-- created on the fly; both are many-to-many with tours. Collections are
+-- hand-picked, ordered tour lists published at /collections/:slug.
+
+CREATE TABLE IF NOT EXISTS categories (
+    id           SERIAL PRIMARY KEY,
+    slug         VARCHAR(100) NOT NULL UNIQUE,
+    name         VARCHAR(100) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    sort_order   INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id          SERIAL PRIMARY KEY,
+    slug        VARCHAR(100) NOT NULL UNIQUE,
+    name        VARCHAR(100) NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tour_categories (
+    tour_id      INTEGER NOT NULL REFERENCES tours(id) ON DELETE CASCADE,
+    category_id  INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (tour_id, category_id)
+);
+CREATE INDEX IF NOT EXISTS idx_tour_categories_category ON tour_categories(category_id);
+
+CREATE TABLE IF NOT EXISTS tour_tags (
+    tour_id  INTEGER NOT NULL REFERENCES tours(id) ON DELETE CASCADE,
+    tag_id   INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (tour_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_tour_tags_tag ON tour_tags(tag_id);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id              SERIAL PRIMARY KEY,
+    slug            VARCHAR(100) NOT NULL UNIQUE,
+    title           VARCHAR(255) NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    cover_image     VARCHAR(500) NOT NULL DEFAULT '',
+    is_active       BOOLEAN NOT NULL DEFAULT TRUE,
+    show_in_swiper  BOOLEAN NOT NULL DEFAULT FALSE,   -- at most one, see index below
+    sort_order      INTEGER NOT NULL DEFAULT 0,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_collections_swiper ON collections(show_in_swiper) WHERE show_in_swiper;
+
+CREATE TABLE IF NOT EXISTS collection_tours (
+    collection_id  INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    tour_id        INTEGER NOT NULL REFERENCES tours(id) ON DELETE CASCADE,
+    position       INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (collection_id, tour_id)
+);
+
+INSERT INTO categories (slug, name, sort_order) VALUES
+    ('beach', 'Пляжний відпочинок', 1),
+    ('city-break', 'Міські тури', 2),
+    ('hiking', 'Походи', 3),
+    ('family', 'Сімейний відпочинок', 4)
+ON CONFLICT (slug) DO NOTHING;
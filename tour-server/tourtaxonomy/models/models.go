@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type Category struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Slug        string    `json:"slug" gorm:"not null;unique"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description"`
+	SortOrder   int       `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Slug      string    `json:"slug" gorm:"not null;unique"`
+	Name      string    `json:"name" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+type Collection struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Slug         string    `json:"slug" gorm:"not null;unique"`
+	Title        string    `json:"title" gorm:"not null"`
+	Description  string    `json:"description"`
+	CoverImage   string    `json:"cover_image"`
+	IsActive     bool      `json:"is_active" gorm:"not null;default:true"`
+	ShowInSwiper bool      `json:"show_in_swiper" gorm:"not null;default:false"`
+	SortOrder    int       `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (Collection) TableName() string {
+	return "collections"
+}
@@ -0,0 +1,95 @@
+// Package tourtaxonomy holds tour categories, tags and collections shared
+// by the public tour page, search and the admin editor.
+package tourtaxonomy
+
+import (
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Term is a category or tag as shown on a tour.
+type Term struct {
+	ID   uint   `json:"id" gorm:"column:id"`
+	Slug string `json:"slug" gorm:"column:slug"`
+	Name string `json:"name" gorm:"column:name"`
+}
+
+var slugRe = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidSlug reports whether s is a lowercase, dash-separated URL slug.
+func ValidSlug(s string) bool {
+	return len(s) <= 100 && slugRe.MatchString(s)
+}
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e",
+	'є': "ie", 'ж': "zh", 'з': "z", 'и': "y", 'і': "i", 'ї': "i", 'й': "i",
+	'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch",
+	'ш': "sh", 'щ': "shch", 'ю': "iu", 'я': "ia",
+}
+
+// Slugify turns a name into a slug, transliterating Ukrainian letters:
+// "Гірські походи" → "hirski-pokhody". Returns "" if nothing is left.
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case translit[r] != "":
+			b.WriteString(translit[r])
+			dash = false
+		case r == 'ь' || r == '\'' || r == '’':
+			// dropped without breaking the word
+		default:
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+	s := strings.TrimSuffix(b.String(), "-")
+	if len(s) > 100 {
+		s = strings.TrimSuffix(s[:100], "-")
+	}
+	return s
+}
+
+// LoadForTour returns the tour's categories and tags.
+func LoadForTour(db *gorm.DB, tourID uint) (categories, tags []Term, err error) {
+	categories, tags = []Term{}, []Term{}
+	err = db.Raw(`
+		SELECT c.id, c.slug, c.name FROM categories c
+		JOIN tour_categories tc ON tc.category_id = c.id
+		WHERE tc.tour_id = ?
+		ORDER BY c.sort_order, c.name
+	`, tourID).Scan(&categories).Error
+	if err != nil {
+		return
+	}
+	err = db.Raw(`
+		SELECT t.id, t.slug, t.name FROM tags t
+		JOIN tour_tags tt ON tt.tag_id = t.id
+		WHERE tt.tour_id = ?
+		ORDER BY t.name
+	`, tourID).Scan(&tags).Error
+	return
+}
+
+// ParseSlugs splits a comma-separated query value into valid slugs,
+// dropping anything malformed.
+func ParseSlugs(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if ValidSlug(part) {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package tourtaxonomy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"City Break":         "city-break",
+		"Гірські походи":     "hirski-pokhody",
+		"  Сім'я & діти!  ":  "simia-dity",
+		"Відпочинок на морі": "vidpochynok-na-mori",
+		"Їжа, вино та сир":   "izha-vyno-ta-syr",
+		"!!!":                "",
+		"Україна 2026":       "ukraina-2026",
+	}
+	for in, want := range cases {
+		if got := Slugify(in); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValidSlug(t *testing.T) {
+	for _, s := range []string{"beach", "city-break", "top-10"} {
+		if !ValidSlug(s) {
+			t.Errorf("expected %q to be valid", s)
+		}
+	}
+	for _, s := range []string{"", "Beach", "city--break", "-beach", "beach-", "пляж", "a b"} {
+		if ValidSlug(s) {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestParseSlugs(t *testing.T) {
+	got := ParseSlugs(" beach, Hiking ,,bad slug,family")
+	want := []string{"beach", "hiking", "family"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
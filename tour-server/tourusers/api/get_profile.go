@@ -20,13 +20,14 @@ func GetProfile(db *gorm.DB) echo.HandlerFunc {
 			})
 		}
 		response := dto.UserResponse{
-			ID:         user.ID,
-			Email:      user.Email,
-			Name:       user.Name,
-			Phone:      user.Phone,
-			AvatarURL:  user.AvatarURL,
-			Role:       user.Role,
-			IsVerified: user.IsVerified,
+			ID:               user.ID,
+			Email:            user.Email,
+			Name:             user.Name,
+			Phone:            user.Phone,
+			AvatarURL:        user.AvatarURL,
+			Role:             user.Role,
+			IsVerified:       user.IsVerified,
+			SMSNotifications: user.SMSNotifications,
 		}
 		return c.JSON(http.StatusOK, response)
 	}
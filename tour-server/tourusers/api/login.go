@@ -79,13 +79,14 @@ func LoginUser(db *gorm.DB) echo.HandlerFunc {
 		response := map[string]interface{}{
 			"token": tokenString,
 			"user": dto.UserResponse{
-				ID:         user.ID,
-				Email:      user.Email,
-				Name:       user.Name,
-				Phone:      user.Phone,
-				AvatarURL:  user.AvatarURL,
-				Role:       user.Role,
-				IsVerified: user.IsVerified,
+				ID:               user.ID,
+				Email:            user.Email,
+				Name:             user.Name,
+				Phone:            user.Phone,
+				AvatarURL:        user.AvatarURL,
+				Role:             user.Role,
+				IsVerified:       user.IsVerified,
+				SMSNotifications: user.SMSNotifications,
 			},
 		}
 		return c.JSON(http.StatusOK, response)
@@ -42,6 +42,9 @@ func UpdateProfile(db *gorm.DB) echo.HandlerFunc {
 		if req.AvatarURL != nil {
 			user.AvatarURL = strings.TrimSpace(*req.AvatarURL)
 		}
+		if req.SMSNotifications != nil {
+			user.SMSNotifications = *req.SMSNotifications
+		}
 
 		if err := db.Save(&user).Error; err != nil {
 			return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -50,13 +53,14 @@ func UpdateProfile(db *gorm.DB) echo.HandlerFunc {
 		}
 
 		response := dto.UserResponse{
-			ID:         user.ID,
-			Email:      user.Email,
-			Name:       user.Name,
-			Phone:      user.Phone,
-			AvatarURL:  user.AvatarURL,
-			Role:       user.Role,
-			IsVerified: user.IsVerified,
+			ID:               user.ID,
+			Email:            user.Email,
+			Name:             user.Name,
+			Phone:            user.Phone,
+			AvatarURL:        user.AvatarURL,
+			Role:             user.Role,
+			IsVerified:       user.IsVerified,
+			SMSNotifications: user.SMSNotifications,
 		}
 
 		return c.JSON(http.StatusOK, response)
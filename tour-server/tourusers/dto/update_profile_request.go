@@ -1,7 +1,8 @@
 package dto
 
 type UpdateProfileRequest struct {
-	Name      string  `json:"name"`
-	Phone     *string `json:"phone"`
-	AvatarURL *string `json:"avatar_url"`
+	Name             string  `json:"name"`
+	Phone            *string `json:"phone"`
+	AvatarURL        *string `json:"avatar_url"`
+	SMSNotifications *bool   `json:"sms_notifications"`
 }
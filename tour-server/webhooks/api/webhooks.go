@@ -0,0 +1,370 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"tour-server/webhooks"
+	"tour-server/webhooks/models"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type EndpointRequest struct {
+	URL          *string  `json:"url"`
+	Events       []string `json:"events"`
+	Description  *string  `json:"description"`
+	IsActive     *bool    `json:"is_active"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+type EndpointItem struct {
+	models.WebhookEndpoint
+	SecretHint string `json:"secret_hint"`
+	Delivered  int64  `json:"delivered" gorm:"column:delivered"`
+	Pending    int64  `json:"pending" gorm:"column:pending"`
+	Failed     int64  `json:"failed" gorm:"column:failed"`
+}
+
+type DeliveryItem struct {
+	models.WebhookDelivery
+	Payload json.RawMessage `json:"payload"`
+}
+
+// validateEndpoint checks the URL and event names; nil fields are skipped.
+func validateEndpoint(req EndpointRequest) string {
+	if req.URL != nil {
+		u, err := url.Parse(strings.TrimSpace(*req.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "url must be an absolute http(s) URL"
+		}
+	}
+	for _, e := range req.Events {
+		if !webhooks.ValidEvent(e) {
+			return "Unknown event: " + e
+		}
+	}
+	return ""
+}
+
+func secretHint(secret string) string {
+	if len(secret) <= 4 {
+		return ""
+	}
+	return "…" + secret[len(secret)-4:]
+}
+
+// GET /admin/webhooks
+// Lists endpoints with delivery counts. Secrets are never returned here —
+// only on create and rotation.
+func GetWebhookEndpoints(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var items []EndpointItem
+		err := db.Raw(`
+			SELECT we.*,
+				COUNT(wd.id) FILTER (WHERE wd.status = 'delivered') AS delivered,
+				COUNT(wd.id) FILTER (WHERE wd.status = 'pending')   AS pending,
+				COUNT(wd.id) FILTER (WHERE wd.status = 'failed')    AS failed
+			FROM webhook_endpoints we
+			LEFT JOIN webhook_deliveries wd ON wd.endpoint_id = we.id
+			GROUP BY we.id
+			ORDER BY we.id
+		`).Scan(&items).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to load webhooks",
+			})
+		}
+		for i := range items {
+			items[i].SecretHint = secretHint(items[i].Secret)
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"endpoints": items,
+			"events":    webhooks.AllEvents,
+		})
+	}
+}
+
+// POST /admin/webhooks
+// Registers an endpoint. Empty events = subscribe to everything. The signing
+// secret is generated here and shown once in the response.
+func CreateWebhookEndpoint(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req EndpointRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if req.URL == nil || *req.URL == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "url is required",
+			})
+		}
+		if msg := validateEndpoint(req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		secret, err := webhooks.NewSecret()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to generate secret",
+			})
+		}
+
+		endpoint := models.WebhookEndpoint{
+			URL:      strings.TrimSpace(*req.URL),
+			Secret:   secret,
+			Events:   pq.StringArray(req.Events),
+			IsActive: true,
+		}
+		if endpoint.Events == nil {
+			endpoint.Events = pq.StringArray{}
+		}
+		if req.Description != nil {
+			endpoint.Description = *req.Description
+		}
+		if req.IsActive != nil {
+			endpoint.IsActive = *req.IsActive
+		}
+
+		if err := db.Create(&endpoint).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create webhook",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, map[string]interface{}{
+			"message":  "Webhook created",
+			"endpoint": endpoint,
+			"secret":   secret,
+		})
+	}
+}
+
+// PUT /admin/webhooks/:id
+func UpdateWebhookEndpoint(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid webhook ID",
+			})
+		}
+
+		var req EndpointRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid request",
+			})
+		}
+		if msg := validateEndpoint(req); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": msg,
+			})
+		}
+
+		updates := map[string]interface{}{"updated_at": time.Now()}
+		if req.URL != nil {
+			updates["url"] = strings.TrimSpace(*req.URL)
+		}
+		if req.Events != nil {
+			updates["events"] = pq.StringArray(req.Events)
+		}
+		if req.Description != nil {
+			updates["description"] = *req.Description
+		}
+		if req.IsActive != nil {
+			updates["is_active"] = *req.IsActive
+		}
+		var secret string
+		if req.RotateSecret {
+			if secret, err = webhooks.NewSecret(); err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to generate secret",
+				})
+			}
+			updates["secret"] = secret
+		}
+
+		result := db.Table("webhook_endpoints").Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to update webhook",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Webhook not found",
+			})
+		}
+
+		resp := map[string]interface{}{"message": "Webhook updated"}
+		if secret != "" {
+			resp["secret"] = secret
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+// DELETE /admin/webhooks/:id
+// Removes the endpoint together with its delivery log.
+func DeleteWebhookEndpoint(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid webhook ID",
+			})
+		}
+
+		result := db.Exec("DELETE FROM webhook_endpoints WHERE id = ?", id)
+		if result.Error != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete webhook",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Webhook not found",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Webhook deleted",
+		})
+	}
+}
+
+// GET /admin/webhooks/:id/deliveries?status=failed&event=booking.created&page=1&limit=20
+// The endpoint's delivery log, newest first.
+func GetWebhookDeliveries(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid webhook ID",
+			})
+		}
+
+		page, _ := strconv.Atoi(c.QueryParam("page"))
+		if page <= 0 {
+			page = 1
+		}
+		limit, _ := strconv.Atoi(c.QueryParam("limit"))
+		if limit <= 0 || limit > 100 {
+			limit = 20
+		}
+
+		where := "endpoint_id = ?"
+		args := []interface{}{id}
+		if status := c.QueryParam("status"); status != "" {
+			where += " AND status = ?"
+			args = append(args, status)
+		}
+		if event := c.QueryParam("event"); event != "" {
+			where += " AND event = ?"
+			args = append(args, event)
+		}
+
+		var total int64
+		db.Raw("SELECT COUNT(*) FROM webhook_deliveries WHERE "+where, args...).Scan(&total)
+
+		var rows []struct {
+			models.WebhookDelivery
+			PayloadText string `gorm:"column:payload_text"`
+		}
+		err = db.Raw(`
+			SELECT *, payload::text AS payload_text
+			FROM webhook_deliveries
+			WHERE `+where+`
+			ORDER BY created_at DESC, id DESC
+			LIMIT ? OFFSET ?
+		`, append(args, limit, (page-1)*limit)...).Scan(&rows).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to load deliveries",
+			})
+		}
+
+		items := make([]DeliveryItem, len(rows))
+		for i, r := range rows {
+			items[i] = DeliveryItem{WebhookDelivery: r.WebhookDelivery, Payload: json.RawMessage(r.PayloadText)}
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"deliveries": items,
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+		})
+	}
+}
+
+// POST /admin/webhooks/deliveries/:id/replay
+// Re-sends a logged delivery as a new delivery with the same event ID and
+// payload, attempted immediately; failures then follow the normal retry
+// schedule.
+func ReplayWebhookDelivery(db *gorm.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid delivery ID",
+			})
+		}
+
+		var original struct {
+			ID       uint `gorm:"column:id"`
+			IsActive bool `gorm:"column:is_active"`
+		}
+		db.Raw(`
+			SELECT wd.id, we.is_active
+			FROM webhook_deliveries wd
+			JOIN webhook_endpoints we ON wd.endpoint_id = we.id
+			WHERE wd.id = ?
+		`, id).Scan(&original)
+		if original.ID == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Delivery not found",
+			})
+		}
+		if !original.IsActive {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Webhook endpoint is disabled",
+			})
+		}
+
+		var replayID uint
+		err = db.Raw(`
+			INSERT INTO webhook_deliveries (endpoint_id, event_id, event, payload, next_attempt_at, replay_of)
+			SELECT endpoint_id, event_id, event, payload, NOW(), id
+			FROM webhook_deliveries WHERE id = ?
+			RETURNING id
+		`, id).Scan(&replayID).Error
+		if err != nil || replayID == 0 {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to queue replay",
+			})
+		}
+
+		webhooks.Attempt(db, replayID)
+
+		var replay models.WebhookDelivery
+		db.First(&replay, replayID)
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message":  "Delivery replayed",
+			"delivery": replay,
+		})
+	}
+}
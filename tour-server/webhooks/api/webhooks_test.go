@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreateWebhookEndpoint_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"missing url", `{"events":["booking.created"]}`, "url is required"},
+		{"relative url", `{"url":"/hooks"}`, "url must be an absolute http(s) URL"},
+		{"bad scheme", `{"url":"ftp://crm.example.com/hook"}`, "url must be an absolute http(s) URL"},
+		{"unknown event", `{"url":"https://crm.example.com/hook","events":["booking.deleted"]}`, "Unknown event: booking.deleted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			CreateWebhookEndpoint(nil)(c)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rec.Code)
+			}
+			var resp map[string]string
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			if resp["error"] != tt.want {
+				t.Errorf("error = %q, want %q", resp["error"], tt.want)
+			}
+		})
+	}
+}
+
+func TestReplayWebhookDelivery_InvalidID(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/webhooks/deliveries/abc/replay", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+
+	ReplayWebhookDelivery(nil)(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,195 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAttempts is how many times a delivery is tried before it is marked failed.
+	MaxAttempts = 6
+
+	requestTimeout = 10 * time.Second
+	// lease keeps a delivery claimed while one attempt is in flight, so the
+	// retry job and an immediate attempt never send it twice.
+	lease = 2 * time.Minute
+
+	maxResponseBody = 1024
+)
+
+// backoff is the wait after each failed attempt: 1m, 5m, 30m, 2h, 12h.
+var backoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	12 * time.Hour,
+}
+
+// NextRetry returns the delay before the next try after the given number of
+// failed attempts, and false once MaxAttempts is reached.
+func NextRetry(attempts int) (time.Duration, bool) {
+	if attempts >= MaxAttempts || attempts < 1 {
+		return 0, false
+	}
+	if attempts > len(backoff) {
+		return backoff[len(backoff)-1], true
+	}
+	return backoff[attempts-1], true
+}
+
+var httpClient = &http.Client{Timeout: requestTimeout}
+
+// Request is one signed POST to an endpoint.
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID uint
+	EventID    string
+	Event      string
+	Payload    []byte
+}
+
+// Send POSTs the payload with signature headers and returns the response
+// status and (truncated) body. Any non-2xx status is an error.
+func Send(ctx context.Context, r Request) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OpenWorld-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", r.Event)
+	req.Header.Set("X-Webhook-ID", r.EventID)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(r.DeliveryID), 10))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(ts, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(r.Secret, ts, r.Payload))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(body), fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// Attempt makes one delivery attempt and records the outcome. It is a
+// no-op if the delivery isn't due or another attempt holds it.
+func Attempt(db *gorm.DB, deliveryID uint) {
+	var d struct {
+		ID       uint   `gorm:"column:id"`
+		EventID  string `gorm:"column:event_id"`
+		Event    string `gorm:"column:event"`
+		Payload  string `gorm:"column:payload"`
+		Attempts int    `gorm:"column:attempts"`
+		URL      string `gorm:"column:url"`
+		Secret   string `gorm:"column:secret"`
+		IsActive bool   `gorm:"column:is_active"`
+	}
+	// Claim: push next_attempt_at past the lease; only one caller wins.
+	err := db.Raw(`
+		UPDATE webhook_deliveries wd
+		SET next_attempt_at = NOW() + make_interval(secs => ?)
+		FROM webhook_endpoints we
+		WHERE wd.id = ? AND wd.endpoint_id = we.id
+			AND wd.status = 'pending' AND wd.next_attempt_at <= NOW()
+		RETURNING wd.id, wd.event_id, wd.event, wd.payload::text AS payload,
+			wd.attempts, we.url, we.secret, we.is_active
+	`, lease.Seconds(), deliveryID).Scan(&d).Error
+	if err != nil || d.ID == 0 {
+		return
+	}
+
+	if !d.IsActive {
+		db.Exec(`
+			UPDATE webhook_deliveries
+			SET status = 'failed', next_attempt_at = NULL, last_error = 'endpoint disabled'
+			WHERE id = ?
+		`, d.ID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	code, body, sendErr := Send(ctx, Request{
+		URL:        d.URL,
+		Secret:     d.Secret,
+		DeliveryID: d.ID,
+		EventID:    d.EventID,
+		Event:      d.Event,
+		Payload:    []byte(d.Payload),
+	})
+
+	var statusCode *int
+	if code != 0 {
+		statusCode = &code
+	}
+	attempts := d.Attempts + 1
+
+	if sendErr == nil {
+		db.Exec(`
+			UPDATE webhook_deliveries
+			SET status = 'delivered', attempts = ?, last_status_code = ?, last_error = '',
+				response_body = ?, next_attempt_at = NULL, delivered_at = NOW()
+			WHERE id = ?
+		`, attempts, statusCode, body, d.ID)
+		log.Printf("Webhook delivered: %s #%d → %s", d.Event, d.ID, d.URL)
+		return
+	}
+
+	if delay, ok := NextRetry(attempts); ok {
+		db.Exec(`
+			UPDATE webhook_deliveries
+			SET attempts = ?, last_status_code = ?, last_error = ?, response_body = ?,
+				next_attempt_at = NOW() + make_interval(secs => ?)
+			WHERE id = ?
+		`, attempts, statusCode, sendErr.Error(), body, delay.Seconds(), d.ID)
+		log.Printf("Webhook %s #%d → %s failed (attempt %d), retry in %s: %v",
+			d.Event, d.ID, d.URL, attempts, delay, sendErr)
+		return
+	}
+
+	db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = 'failed', attempts = ?, last_status_code = ?, last_error = ?,
+			response_body = ?, next_attempt_at = NULL
+		WHERE id = ?
+	`, attempts, statusCode, sendErr.Error(), body, d.ID)
+	log.Printf("Webhook %s #%d → %s gave up after %d attempts: %v",
+		d.Event, d.ID, d.URL, attempts, sendErr)
+}
+
+// StartRetryJob periodically re-attempts deliveries whose backoff has
+// elapsed. Runs in a goroutine.
+func StartRetryJob(db *gorm.DB, interval time.Duration) {
+	go func() {
+		for {
+			var due []uint
+			db.Raw(`
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT 100
+			`).Scan(&due)
+			for _, id := range due {
+				Attempt(db, id)
+			}
+			time.Sleep(interval)
+		}
+	}()
+	log.Printf("Webhook retry job started (every %s)", interval)
+}
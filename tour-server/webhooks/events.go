@@ -0,0 +1,182 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event names an outbound webhook event.
+type Event string
+
+const (
+	EventBookingCreated   Event = "booking.created"
+	EventBookingConfirmed Event = "booking.confirmed"
+	EventBookingCancelled Event = "booking.cancelled"
+	EventPaymentFailed    Event = "payment.failed"
+	EventTourUpdated      Event = "tour.updated"
+)
+
+// AllEvents lists every event an endpoint can subscribe to.
+var AllEvents = []Event{
+	EventBookingCreated,
+	EventBookingConfirmed,
+	EventBookingCancelled,
+	EventPaymentFailed,
+	EventTourUpdated,
+}
+
+// ValidEvent reports whether name is a known event.
+func ValidEvent(name string) bool {
+	for _, e := range AllEvents {
+		if string(e) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Envelope is the JSON body POSTed to endpoints. ID stays the same across
+// retries and manual replays so receivers can deduplicate.
+type Envelope struct {
+	ID        string      `json:"id"`
+	Event     Event       `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// BookingData is the "data" object of booking.* and payment.failed events.
+type BookingData struct {
+	ID            uint      `json:"id" gorm:"column:id"`
+	UserID        *uint     `json:"user_id" gorm:"column:user_id"`
+	TourID        uint      `json:"tour_id" gorm:"column:tour_id"`
+	TourTitle     string    `json:"tour_title" gorm:"column:tour_title"`
+	TourDateID    uint      `json:"tour_date_id" gorm:"column:tour_date_id"`
+	DateFrom      time.Time `json:"date_from" gorm:"column:date_from"`
+	DateTo        time.Time `json:"date_to" gorm:"column:date_to"`
+	Seats         int       `json:"seats" gorm:"column:seats"`
+	TotalPrice    float64   `json:"total_price" gorm:"column:total_price"`
+	Status        string    `json:"status" gorm:"column:status"`
+	PaymentStatus string    `json:"payment_status" gorm:"column:payment_status"`
+	CustomerName  string    `json:"customer_name" gorm:"column:customer_name"`
+	CustomerEmail string    `json:"customer_email" gorm:"column:customer_email"`
+	CustomerPhone string    `json:"customer_phone" gorm:"column:customer_phone"`
+	IsGuest       bool      `json:"is_guest" gorm:"column:is_guest_booking"`
+	BookedAt      time.Time `json:"booked_at" gorm:"column:booked_at"`
+}
+
+// TourData is the "data" object of tour.updated.
+type TourData struct {
+	ID         uint    `json:"id" gorm:"column:id"`
+	Title      string  `json:"title" gorm:"column:title"`
+	Price      float64 `json:"price" gorm:"column:price"`
+	StatusID   uint    `json:"status_id" gorm:"column:status_id"`
+	TotalSeats int     `json:"total_seats" gorm:"column:total_seats"`
+}
+
+// PublishBooking queues event for the booking to every subscribed endpoint.
+// Runs in a goroutine — never blocks or fails the caller, the same contract
+// as the email and SMS notifications it sits next to.
+func PublishBooking(db *gorm.DB, event Event, bookingID uint) {
+	if db == nil || bookingID == 0 {
+		return
+	}
+	go func() {
+		var data BookingData
+		err := db.Raw(`
+			SELECT b.id, b.user_id, td.tour_id, t.title AS tour_title, b.tour_date_id,
+				td.date_from, td.date_to, b.seats, b.total_price, b.status,
+				COALESCE(b.payment_status, 'pending') AS payment_status,
+				b.customer_name, COALESCE(b.customer_email, '') AS customer_email,
+				b.customer_phone, b.is_guest_booking, b.booked_at
+			FROM bookings b
+			JOIN tour_dates td ON b.tour_date_id = td.id
+			JOIN tours t ON td.tour_id = t.id
+			WHERE b.id = ?
+		`, bookingID).Scan(&data).Error
+		if err != nil || data.ID == 0 {
+			log.Printf("Webhook %s: booking #%d not found", event, bookingID)
+			return
+		}
+		if err := Publish(db, event, data); err != nil {
+			log.Printf("Webhook %s error: %v", event, err)
+		}
+	}()
+}
+
+// PublishTour queues tour.updated for the tour. Async, like PublishBooking.
+func PublishTour(db *gorm.DB, tourID uint) {
+	if db == nil || tourID == 0 {
+		return
+	}
+	go func() {
+		var data TourData
+		err := db.Raw(
+			"SELECT id, title, price, status_id, COALESCE(total_seats, 0) AS total_seats FROM tours WHERE id = ?",
+			tourID,
+		).Scan(&data).Error
+		if err != nil || data.ID == 0 {
+			log.Printf("Webhook %s: tour #%d not found", EventTourUpdated, tourID)
+			return
+		}
+		if err := Publish(db, EventTourUpdated, data); err != nil {
+			log.Printf("Webhook %s error: %v", EventTourUpdated, err)
+		}
+	}()
+}
+
+// Publish records one delivery per active endpoint subscribed to event and
+// attempts each right away. Failed attempts are picked up by the retry job.
+func Publish(db *gorm.DB, event Event, data interface{}) error {
+	var endpointIDs []uint
+	if err := db.Raw(`
+		SELECT id FROM webhook_endpoints
+		WHERE is_active AND (cardinality(events) = 0 OR ? = ANY(events))
+	`, string(event)).Scan(&endpointIDs).Error; err != nil {
+		return fmt.Errorf("load endpoints: %w", err)
+	}
+	if len(endpointIDs) == 0 {
+		return nil
+	}
+
+	eventID, err := newEventID()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(Envelope{
+		ID:        eventID,
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", event, err)
+	}
+
+	for _, endpointID := range endpointIDs {
+		var deliveryID uint
+		if err := db.Raw(`
+			INSERT INTO webhook_deliveries (endpoint_id, event_id, event, payload, next_attempt_at)
+			VALUES (?, ?, ?, ?::jsonb, NOW())
+			RETURNING id
+		`, endpointID, eventID, string(event), string(payload)).Scan(&deliveryID).Error; err != nil {
+			log.Printf("Webhook %s: failed to queue for endpoint #%d: %v", event, endpointID, err)
+			continue
+		}
+		go Attempt(db, deliveryID)
+	}
+	return nil
+}
+
+func newEventID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("event id: %w", err)
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,36 @@
+-- Migration: outbound partner webhooks
+-- Admin-registered endpoints receive HMAC-signed JSON events; every send is
+-- kept in webhook_deliveries as the per-endpoint delivery log.
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id           SERIAL PRIMARY KEY,
+    url          TEXT NOT NULL,
+    secret       VARCHAR(100) NOT NULL,
+    events       TEXT[] NOT NULL DEFAULT '{}',   -- empty = all events
+    description  VARCHAR(255) NOT NULL DEFAULT '',
+    is_active    BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id                SERIAL PRIMARY KEY,
+    endpoint_id       INTEGER NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    event_id          VARCHAR(40) NOT NULL,     -- same across retries and replays
+    event             VARCHAR(50) NOT NULL,
+    payload           JSONB NOT NULL,
+    status            VARCHAR(20) NOT NULL DEFAULT 'pending'
+                      CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts          INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at   TIMESTAMP,
+    last_status_code  INTEGER,
+    last_error        TEXT NOT NULL DEFAULT '',
+    response_body     TEXT NOT NULL DEFAULT '',
+    replay_of         INTEGER REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at      TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+    ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,117 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"booking.created"}`)
+	sig := Sign("whsec_test", 1700000000, body)
+
+	if !strings.HasPrefix(sig, "sha256=") || len(sig) != len("sha256=")+64 {
+		t.Fatalf("unexpected signature format: %s", sig)
+	}
+	if !Verify("whsec_test", 1700000000, body, sig) {
+		t.Error("signature did not verify")
+	}
+	if Verify("whsec_other", 1700000000, body, sig) {
+		t.Error("signature verified with wrong secret")
+	}
+	if Verify("whsec_test", 1700000001, body, sig) {
+		t.Error("signature verified with wrong timestamp")
+	}
+	if Verify("whsec_test", 1700000000, []byte(`{"event":"booking.cancelled"}`), sig) {
+		t.Error("signature verified with tampered body")
+	}
+}
+
+func TestNextRetry(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+		ok       bool
+	}{
+		{1, time.Minute, true},
+		{2, 5 * time.Minute, true},
+		{5, 12 * time.Hour, true},
+		{MaxAttempts, 0, false},
+		{0, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := NextRetry(tt.attempts)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NextRetry(%d) = (%s, %v), want (%s, %v)", tt.attempts, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestValidEvent(t *testing.T) {
+	for _, e := range AllEvents {
+		if !ValidEvent(string(e)) {
+			t.Errorf("ValidEvent(%q) = false", e)
+		}
+	}
+	if ValidEvent("booking.deleted") {
+		t.Error("ValidEvent accepted unknown event")
+	}
+}
+
+func TestSendSignsRequest(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","event":"booking.confirmed","data":{}}`)
+
+	var gotHeaders http.Header
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Write([]byte("thanks"))
+	}))
+	defer srv.Close()
+
+	code, body, err := Send(context.Background(), Request{
+		URL:        srv.URL,
+		Secret:     "whsec_test",
+		DeliveryID: 7,
+		EventID:    "evt_1",
+		Event:      "booking.confirmed",
+		Payload:    payload,
+	})
+	if err != nil || code != http.StatusOK || body != "thanks" {
+		t.Fatalf("Send = (%d, %q, %v)", code, body, err)
+	}
+
+	if string(gotBody) != string(payload) {
+		t.Errorf("body = %s", gotBody)
+	}
+	if gotHeaders.Get("X-Webhook-Event") != "booking.confirmed" || gotHeaders.Get("X-Webhook-ID") != "evt_1" ||
+		gotHeaders.Get("X-Webhook-Delivery") != "7" {
+		t.Errorf("unexpected headers: %v", gotHeaders)
+	}
+	ts, _ := strconv.ParseInt(gotHeaders.Get("X-Webhook-Timestamp"), 10, 64)
+	if !Verify("whsec_test", ts, gotBody, gotHeaders.Get("X-Webhook-Signature")) {
+		t.Error("receiver could not verify signature")
+	}
+}
+
+func TestSendNon2xxIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("down"))
+	}))
+	defer srv.Close()
+
+	code, body, err := Send(context.Background(), Request{URL: srv.URL, Secret: "s", Payload: []byte("{}")})
+	if err == nil {
+		t.Fatal("expected error for 503")
+	}
+	if code != http.StatusServiceUnavailable || body != "down" {
+		t.Errorf("Send = (%d, %q)", code, body)
+	}
+}
@@ -14,6 +14,7 @@ import { TourDetails } from '../../pages/TourDetails/TourDetails';
 import { UserProfile } from '../../pages/Profile/UserProfile';
 import { UserBookings } from '../../pages/Bookings/UserBookings';
 import { GuestPay } from '../../pages/GuestPay/GuestPay';
+import { Unsubscribe } from '../../pages/Unsubscribe/Unsubscribe';
 import { UserFavorites } from '../../pages/Favorites/UserFavorites';
 import { AdminLayout, Dashboard, AdminBookings, AdminTours, AdminUsers } from '../../pages/Admin';
 import { ForgotPasswordPage, ResetPasswordPage } from '../../pages/ResetPassword/ResetPassword';
@@ -74,6 +75,7 @@ function App() {
                   <Route path="/profile" element={<UserProfile />} />
                   <Route path="/bookings" element={<UserBookings />} />
                   <Route path="/pay/:token" element={<GuestPay />} />
+                  <Route path="/unsubscribe/:token" element={<Unsubscribe />} />
                   <Route path="/favorites" element={<UserFavorites />} />
                   <Route path="/settings" element={<UserSettings />} />
 
@@ -0,0 +1,66 @@
+import React, { useEffect, useState } from 'react';
+import { useParams } from 'react-router-dom';
+import { Card, CardBody, Button, Spinner } from '@heroui/react';
+import { BellOff, AlertCircle } from 'lucide-react';
+import { Navbar } from '../../components/Navbar/Navbar';
+import { Footer } from '../Main/components/Footer/Footer';
+
+const API = process.env.REACT_APP_API_URL!;
+
+// Landing page for the unsubscribe link in saved-search alert emails.
+export const Unsubscribe: React.FC = () => {
+  const { token = '' } = useParams<{ token: string }>();
+  const [message, setMessage] = useState('');
+  const [error, setError] = useState('');
+  const [loading, setLoading] = useState(true);
+
+  useEffect(() => {
+    let cancelled = false;
+    const unsubscribe = async () => {
+      try {
+        const res = await fetch(`${API}/saved-searches/unsubscribe/${encodeURIComponent(token)}`, {
+          method: 'POST',
+        });
+        const data = await res.json();
+        if (!res.ok) throw new Error(data.error || 'Не вдалося вимкнути сповіщення');
+        if (!cancelled) setMessage(data.message);
+      } catch (err) {
+        if (!cancelled) setError(err instanceof Error ? err.message : 'Помилка');
+      } finally {
+        if (!cancelled) setLoading(false);
+      }
+    };
+    unsubscribe();
+    return () => { cancelled = true; };
+  }, [token]);
+
+  return (
+    <>
+      <Navbar />
+      <div style={{ maxWidth: 600, margin: '0 auto', padding: '32px 16px 64px' }}>
+        <Card>
+          <CardBody style={{ textAlign: 'center', padding: '32px 24px' }}>
+            {loading ? (
+              <div style={{ display: 'flex', flexDirection: 'column', alignItems: 'center', gap: 12 }}>
+                <Spinner size="lg" />
+                <p style={{ color: '#64748b' }}>Вимикаємо сповіщення...</p>
+              </div>
+            ) : (
+              <>
+                {error
+                  ? <AlertCircle size={48} style={{ color: '#ef4444', margin: '0 auto 12px' }} />
+                  : <BellOff size={48} style={{ color: '#0ea5e9', margin: '0 auto 12px' }} />}
+                <h3 style={{ fontSize: 18, fontWeight: 700, marginBottom: 6 }}>
+                  {error ? 'Посилання недоступне' : 'Сповіщення вимкнено'}
+                </h3>
+                <p style={{ color: '#64748b', marginBottom: 16 }}>{error || message}</p>
+                <Button color="primary" onClick={() => window.location.href = '/'}>На головну</Button>
+              </>
+            )}
+          </CardBody>
+        </Card>
+      </div>
+      <Footer />
+    </>
+  );
+};